@@ -0,0 +1,161 @@
+// Package darkness builds darkness sites from other Go programs, the same
+// way `darkness build` does, without exiting or touching global state, so
+// that many sites can be built at the same time in one process.
+//
+//	result, err := darkness.New(darkness.Config{WorkDir: "site"}).Build(ctx)
+package darkness
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
+	"github.com/thecsw/darkness/v3/ichika"
+)
+
+// configFile is the name of the config file in the site's directory.
+const configFile = "darkness.toml"
+
+// ErrFailed is returned when the build had problems that fail it, which
+// are in the result.
+var ErrFailed = ichika.ErrBuildFailed
+
+// Config is how to build the site, the zero values are what `darkness build`
+// does without any flags.
+type Config struct {
+	// WorkDir is the directory of the site, the directory of ConfigFile if empty.
+	WorkDir string
+
+	// ConfigFile is the location of darkness.toml, the one in WorkDir if empty.
+	ConfigFile string
+
+	// Url overrides the url of the site from darkness.toml.
+	Url string
+
+	// Dev makes the urls local paths, to browse the site from the disk.
+	Dev bool
+
+	// Workers is the number of workers parsing and exporting the pages, 4 if unset.
+	Workers int
+
+	// Akaneless skips the post-processing, like page previews.
+	Akaneless bool
+
+	// Force redoes the post-processing even if its outputs exist.
+	Force bool
+
+	// VendorGalleries stubs in local copies of the remote gallery images.
+	VendorGalleries bool
+
+	// Lfs enables linking the Git LFS images.
+	Lfs bool
+
+	// Strict fails the build on warnings, like broken links.
+	Strict bool
+
+	// BuildReport writes a report of where the build spent its time.
+	BuildReport bool
+
+	// Debug enables the debug knobs, like writing the parsed pages as json.
+	Debug bool
+
+	// Stdout gets the build progress, summaries, and the output of the hooks,
+	// which are dropped if nil.
+	Stdout io.Writer
+
+	// Enrichers are the enrichment steps to add after the built-in ones,
+	// replacing the built-in ones with the same names.
+	Enrichers []alpha.Enricher
+
+	// Logger gets the logs of the build, only the warnings go to stderr if nil.
+	Logger *log.Logger
+}
+
+// Darkness builds a site.
+type Darkness struct {
+	config Config
+}
+
+// Result is what the build did.
+type Result struct {
+	// Pages is the number of pages exported, generated ones included.
+	Pages int64
+
+	// Elapsed is how long it took to build the pages.
+	Elapsed time.Duration
+
+	// Outputs are what the build did with its outputs.
+	Outputs uiharu.Counts
+
+	// Problems are what went wrong, the fatal ones failed the build.
+	Problems []*hitagi.Problem
+}
+
+// New returns darkness that builds the site with the config.
+func New(config Config) *Darkness {
+	return &Darkness{config: config}
+}
+
+// Build builds the site with its hooks, the error is ErrFailed if the problems
+// in the result failed the build, or why the site couldn't be built at all.
+func (d *Darkness) Build(ctx context.Context) (*Result, error) {
+	options, err := d.options()
+	if err != nil {
+		return nil, err
+	}
+	conf, err := alpha.NewConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := ichika.Build(ctx, conf)
+	if summary == nil {
+		return nil, err
+	}
+	return &Result{
+		Pages:    summary.Pages,
+		Elapsed:  summary.Elapsed,
+		Outputs:  summary.Outputs,
+		Problems: conf.Runtime.Problems.Problems(),
+	}, err
+}
+
+// options returns the alpha options of the config, with the paths made absolute.
+func (d *Darkness) options() (alpha.Options, error) {
+	workDir, config := d.config.WorkDir, d.config.ConfigFile
+	if len(workDir) < 1 && len(config) > 0 {
+		workDir = filepath.Dir(config)
+	}
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return alpha.Options{}, err
+	}
+	if len(config) < 1 {
+		config = filepath.Join(workDir, configFile)
+	}
+	stdout := d.config.Stdout
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	return alpha.Options{
+		DarknessConfig:  config,
+		Url:             d.config.Url,
+		WorkDir:         workDir,
+		Dev:             d.config.Dev,
+		Debug:           d.config.Debug,
+		VendorGalleries: d.config.VendorGalleries,
+		Strict:          d.config.Strict,
+		Workers:         d.config.Workers,
+		Akaneless:       d.config.Akaneless,
+		Force:           d.config.Force,
+		BuildReport:     d.config.BuildReport,
+		Lfs:             d.config.Lfs,
+		Stdout:          stdout,
+		Enrichers:       d.config.Enrichers,
+		Logger:          d.config.Logger,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package darkness
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// writeSite writes the files of a site into a temporary directory and returns it
+func writeSite(t *testing.T, files map[string]string) string {
+	workDir := t.TempDir()
+	for file, data := range files {
+		filename := filepath.Join(workDir, file)
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return workDir
+}
+
+// TestBuildConcurrently tests that the sites built at the same time
+// keep their global macros to themselves
+func TestBuildConcurrently(t *testing.T) {
+	names := []string{"Ishmael", "Queequeg", "Starbuck", "Stubb"}
+	workDirs := make([]string, len(names))
+	for i, name := range names {
+		workDirs[i] = writeSite(t, map[string]string{
+			"darkness.toml": "url = \"https://example.com\"\n",
+			"_macros.org":   "#+macro: who " + name + "\n",
+			"index.org":     "#+title: Moby\n\nCall me {{{who}}}.\n",
+			"notes/cat.org": "#+title: Cat\n\n{{{who}}} was here.\n",
+		})
+	}
+	wg := sync.WaitGroup{}
+	for i, workDir := range workDirs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := New(Config{WorkDir: workDir, Akaneless: true}).Build(context.Background())
+			if err != nil {
+				t.Errorf("building %s: %v", names[i], err)
+				return
+			}
+			if result.Pages != 2 {
+				t.Errorf("built %d pages of %s, expected 2", result.Pages, names[i])
+			}
+		}()
+	}
+	wg.Wait()
+	for i, workDir := range workDirs {
+		for _, file := range []string{"index.html", "notes/cat.html"} {
+			data, err := os.ReadFile(filepath.Join(workDir, file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j, name := range names {
+				if found := strings.Contains(string(data), name); found != (i == j) {
+					t.Errorf("%s of %s mentions %s: %t", file, names[i], name, found)
+				}
+			}
+		}
+	}
+}
+
+// TestBuildErrors tests that the build returns errors instead of exiting
+func TestBuildErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "nowhere")
+	if _, err := New(Config{WorkDir: missing}).Build(context.Background()); err == nil {
+		t.Errorf("expected an error for a site without darkness.toml")
+	}
+
+	workDir := writeSite(t, map[string]string{
+		"darkness.toml": "url = \"https://example.com\"\n",
+		"index.org":     "#+title: Moby\n\nCall me {{{who}}}.\n",
+	})
+	result, err := New(Config{WorkDir: workDir, Akaneless: true}).Build(context.Background())
+	if !errors.Is(err, ErrFailed) {
+		t.Fatalf("expected the build to fail, got %v", err)
+	}
+	if len(result.Problems) != 1 || !result.Problems[0].Fatal || result.Problems[0].File != "index.org" {
+		t.Errorf("expected the undefined macro to fail index.org, got %+v", result.Problems)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := New(Config{WorkDir: workDir}).Build(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled build not to start, got %v", err)
+	}
+}
+
+// TestBuildCancelled tests that a build stopped halfway returns promptly
+// without writing the pages it was working on
+func TestBuildCancelled(t *testing.T) {
+	workDir := writeSite(t, map[string]string{
+		"darkness.toml": "url = \"https://example.com\"\n\n[[hooks.filters]]\ncommand = \"sleep 10\"\n",
+		"index.org":     "#+title: Moby\n\nCall me Ishmael.\n",
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := New(Config{WorkDir: workDir, Akaneless: true}).Build(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the build to stop, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the stopped build took %s to return", elapsed)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "index.html")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the stopped page not to be written, got %v", err)
+	}
+}
+
+// TestBuildAgain tests that a build doesn't remember the setupfiles
+// read by the build before it
+func TestBuildAgain(t *testing.T) {
+	workDir := writeSite(t, map[string]string{
+		"darkness.toml": "url = \"https://example.com\"\n",
+		"setup.org":     "#+macro: who Ishmael\n",
+		"index.org":     "#+title: Moby\n#+setupfile: setup.org\n\nCall me {{{who}}}.\n",
+	})
+	d := New(Config{WorkDir: workDir, Akaneless: true})
+	for _, name := range []string{"Ishmael", "Queequeg"} {
+		if err := os.WriteFile(filepath.Join(workDir, "setup.org"), []byte("#+macro: who "+name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := d.Build(context.Background()); err != nil {
+			t.Fatalf("building with %s: %v", name, err)
+		}
+		data, err := os.ReadFile(filepath.Join(workDir, "index.html"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "Call me "+name) {
+			t.Errorf("expected index.html to call me %s", name)
+		}
+	}
+}
+
+// TestBuildLogger tests that the build logs through the given logger
+func TestBuildLogger(t *testing.T) {
+	workDir := writeSite(t, map[string]string{
+		"darkness.toml": "url = \"https://example.com\"\n",
+		"index.org":     "#+title: Moby\n\nCall me Ishmael.\n",
+	})
+	logs := &strings.Builder{}
+	logger := log.NewWithOptions(logs, log.Options{Level: log.DebugLevel})
+	if _, err := New(Config{WorkDir: workDir, Akaneless: true, Logger: logger}).Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"Parsed", "Wrote"} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Errorf("expected %q in the logs, got %q", expected, logs.String())
+		}
+	}
+}
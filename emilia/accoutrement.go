@@ -26,6 +26,12 @@ const (
 	optionToc               = `toc`
 	optionRssPrefix         = `rss-prefix`
 	optionRssTitle          = `rss-title`
+	optionSitemap           = `sitemap`
+	optionSitemapChangeFreq = `sitemap-changefreq`
+	optionSitemapPriority   = `sitemap-priority`
+	optionRelated           = `related`
+	optionReadingTime       = `reading-time`
+	optionBacklinks         = `backlinks`
 )
 
 var accoutrementActions = map[string]func(string, *yunyun.Accoutrement){
@@ -44,6 +50,12 @@ var accoutrementActions = map[string]func(string, *yunyun.Accoutrement){
 	optionToc:               accoutrementToc,
 	optionRssPrefix:         accoutrementRssPrefix,
 	optionRssTitle:          accoutrementRssTitle,
+	optionSitemap:           accoutrementSitemap,
+	optionSitemapChangeFreq: accoutrementSitemapChangeFreq,
+	optionSitemapPriority:   accoutrementSitemapPriority,
+	optionRelated:           accoutrementRelated,
+	optionReadingTime:       accoutrementReadingTime,
+	optionBacklinks:         accoutrementBacklinks,
 }
 
 // InitializeAccoutrement fills accoutrement according to the config
@@ -76,6 +88,19 @@ func FillAccoutrement(tombs []yunyun.RelativePathDir, options *string, page *yun
 	}
 }
 
+// UnknownAccoutrementOptions returns the keys in `options` that darkness
+// doesn't know, so the user can find their typos.
+func UnknownAccoutrementOptions(options string) []string {
+	var unknown []string
+	for option := range strings.FieldsSeq(options) {
+		key, _ := breakOption(option)
+		if _, ok := accoutrementActions[key]; !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	return unknown
+}
+
 // breakOption breaks the option into two parts, the first part is the
 // key, and the second part is the value. If the option doesn't have
 // a value, then the second part is `enableOption` by default.
@@ -165,6 +190,36 @@ func accoutrementRssTitle(what string, target *yunyun.Accoutrement) {
 	target.RssTitle = what
 }
 
+// accoutrementSitemap sets the sitemap inclusion option of the accoutrement.
+func accoutrementSitemap(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.Sitemap)
+}
+
+// accoutrementSitemapChangeFreq sets the sitemap changefreq option of the accoutrement.
+func accoutrementSitemapChangeFreq(what string, target *yunyun.Accoutrement) {
+	target.SitemapChangeFreq = what
+}
+
+// accoutrementSitemapPriority sets the sitemap priority option of the accoutrement.
+func accoutrementSitemapPriority(what string, target *yunyun.Accoutrement) {
+	target.SitemapPriority = what
+}
+
+// accoutrementRelated sets the related pages option of the accoutrement.
+func accoutrementRelated(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.Related)
+}
+
+// accoutrementReadingTime shows/hides the reading time next to the date.
+func accoutrementReadingTime(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.ReadingTime)
+}
+
+// accoutrementBacklinks shows/hides the pages linking here.
+func accoutrementBacklinks(what string, target *yunyun.Accoutrement) {
+	accoutrementBool(what, &target.Backlinks)
+}
+
 // accoutrementBool sets the bool value of the target according to the what.
 func accoutrementBool(what string, target *yunyun.AccoutrementFlip) {
 	switch strings.TrimSpace(what) {
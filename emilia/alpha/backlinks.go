@@ -0,0 +1,16 @@
+package alpha
+
+import "github.com/thecsw/darkness/v3/yunyun"
+
+// setupBacklinks fills in the backlinks defaults.
+func (conf *DarknessConfig) setupBacklinks() {
+	for i, dir := range conf.Backlinks.Dirs {
+		conf.Backlinks.Dirs[i] = cleanDir(dir)
+	}
+}
+
+// Shows returns true if the page at the location is in one
+// of the directories that show backlinks.
+func (b BacklinksConfig) Shows(location yunyun.RelativePathDir) bool {
+	return insideAny(location, b.Dirs)
+}
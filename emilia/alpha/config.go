@@ -11,17 +11,46 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/thecsw/darkness/v3/emilia/puck"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
 )
 
-// BuildConfig builds the config from the passed options.
+// defaultWorkers is the number of workers processing the pages if not set.
+const defaultWorkers = 4
+
+// BuildConfig builds the config from the passed options, exiting if it can't.
 func BuildConfig(options Options) *DarknessConfig {
+	conf, err := NewConfig(options)
+	if err != nil {
+		puck.Logger.Fatal("Building the config", "err", err)
+	}
+	return conf
+}
+
+// NewConfig builds the config from the passed options.
+func NewConfig(options Options) (*DarknessConfig, error) {
 	conf := &DarknessConfig{}
-	conf.Runtime.Logger = puck.NewLogger("Alpha ☕")
+	conf.Runtime.Logger = options.Logger
+	if conf.Runtime.Logger == nil {
+		conf.Runtime.Logger = puck.NewLogger("Alpha ☕")
+	}
 	conf.Runtime.WorkDir = WorkingDirectory(options.WorkDir)
 	conf.Runtime.WriteParsedPagesAsJson = options.Debug
+	conf.Runtime.Debug = options.Debug
+	conf.Runtime.Workers = options.Workers
+	conf.Runtime.Akaneless = options.Akaneless
+	conf.Runtime.Force = options.Force
+	conf.Runtime.BuildReport = options.BuildReport
+	conf.Runtime.Lfs = options.Lfs
+	conf.Runtime.Stdout = options.Stdout
+	conf.Runtime.Enrichers = slices.Clone(options.Enrichers)
+	conf.Runtime.State = &BuildState{}
+	if conf.Runtime.Workers < 1 {
+		conf.Runtime.Workers = defaultWorkers
+	}
+	if conf.Runtime.Stdout == nil {
+		conf.Runtime.Stdout = os.Stdout
+	}
 
 	// Record the time it takes to initialize the options.
 	defer puck.Stopwatch("Initialized options").Record(conf.Runtime.Logger)
@@ -35,13 +64,13 @@ func BuildConfig(options Options) *DarknessConfig {
 	// Read the config file.
 	data, err := os.ReadFile(options.DarknessConfig)
 	if err != nil && !options.Test {
-		conf.Runtime.Logger.Fatal("Opening config", "path", options.DarknessConfig, "err", err)
+		return nil, fmt.Errorf("opening config %s: %w", options.DarknessConfig, err)
 	}
 
-	// If we can't decode the config, then exit.
+	// If we can't decode the config, then bail.
 	_, err = toml.Decode(string(data), &conf)
 	if err != nil {
-		conf.Runtime.Logger.Fatal("Decoding config", "path", options.DarknessConfig, "err", err)
+		return nil, fmt.Errorf("decoding config %s: %w", options.DarknessConfig, err)
 	}
 
 	// Define the preview filename.
@@ -56,8 +85,7 @@ func BuildConfig(options Options) *DarknessConfig {
 	if len(conf.Url) < 1 || options.Dev {
 		conf.Url, err = os.Getwd()
 		if err != nil {
-			conf.Runtime.Logger.Error("Getting working directory, no config url found", "err", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("getting working directory, no config url found: %w", err)
 		}
 	}
 
@@ -77,10 +105,14 @@ func BuildConfig(options Options) *DarknessConfig {
 	if !conf.Runtime.isUrlLocal {
 		conf.Runtime.UrlPath, err = url.Parse(conf.Url)
 		if err != nil {
-			conf.Runtime.Logger.Error("Parsing url from config", "url", conf.Url, "err", err)
-			os.Exit(1)
+			return nil, fmt.Errorf("parsing url %s from config: %w", conf.Url, err)
 		}
 	}
+
+	// Set up the output directory, local urls point into it.
+	if err := conf.setupOutputDirectory(); err != nil {
+		return nil, err
+	}
 	conf.Runtime.urlSlice = []string{conf.Url}
 
 	// Set up the custom highlight languages if they exist.
@@ -93,6 +125,55 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.Website.SyntaxHighlightingTheme = highlightJsThemeDefaultPath
 	}
 
+	// Fill in the raw html sanitization policy.
+	conf.setupSanitizer()
+
+	// Clean up the languages of the site.
+	conf.setupLanguages()
+
+	// Build the catalog of user-visible strings.
+	conf.setupMessages()
+
+	// Validate the feeds we need to generate.
+	conf.setupFeeds()
+
+	// Fill in the sitemap defaults.
+	conf.setupSitemap()
+
+	// Validate the generated listings.
+	conf.setupListings()
+
+	// Fill in the tags defaults.
+	conf.setupTags()
+
+	// Fill in the related pages defaults.
+	conf.setupRelated()
+
+	// Fill in the search defaults.
+	conf.setupSearch()
+
+	// Fill in the reading speeds.
+	conf.setupReading()
+
+	// Fill in the backlinks defaults.
+	conf.setupBacklinks()
+
+	// Clean up the series directories.
+	conf.setupSeries()
+
+	// Fill in the structured data defaults.
+	conf.setupStructuredData()
+
+	// Pick the warnings that fail the build.
+	if err := conf.setupStrict(options); err != nil {
+		return nil, err
+	}
+
+	// Fill in the hooks defaults.
+	if err := conf.setupHooks(); err != nil {
+		return nil, err
+	}
+
 	// Set the default vendor directory if it's not set.
 	if isUnset(conf.Project.DarknessVendorDirectory) {
 		conf.Project.DarknessVendorDirectory = puck.DefaultVendorDirectory
@@ -115,7 +196,7 @@ func BuildConfig(options Options) *DarknessConfig {
 		strings.Join(yunyun.AnyPathsToStrings(conf.Project.Exclude), "|"))
 	conf.Project.ExcludeRegex, err = regexp.Compile(excludePattern)
 	if err != nil {
-		conf.Runtime.Logger.Fatalf("bad exclude regex passed ('%s'): %v", excludePattern, err)
+		return nil, fmt.Errorf("bad exclude regex passed ('%s'): %w", excludePattern, err)
 	}
 
 	// Check whether the author image is full or not by running
@@ -128,13 +209,15 @@ func BuildConfig(options Options) *DarknessConfig {
 	}
 
 	// Set up the project extensions.
-	conf.setupProjectExtensions(options)
+	if err := conf.setupProjectExtensions(options); err != nil {
+		return nil, err
+	}
 
 	// Set up the gallery vendoring.
 	conf.setupGalleryVendoring(options)
 
 	// Only if we need LFS, do we need to enable the Git integration workflow.x
-	if kuroko.LfsEnabled {
+	if conf.Runtime.Lfs {
 		// Last but not least, let's try to set up the git remote.
 		if isUnset(conf.External.GitRemotePath) || isUnset(conf.External.GitRemoteService) {
 			service, path, err := ExtractGitRemote(conf)
@@ -162,8 +245,7 @@ func BuildConfig(options Options) *DarknessConfig {
 		conf.External.GitRemotesAreValid = true
 	}
 
-	// Set up the gallery vendoring.
-	return conf
+	return conf, nil
 }
 
 // isUnset returns true if the passed value is a zero value of its type.
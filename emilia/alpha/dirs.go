@@ -0,0 +1,45 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// cleanDir cleans up a directory from the config, so it can be matched
+// against the page locations, with the root being ".".
+func cleanDir(dir yunyun.RelativePathDir) yunyun.RelativePathDir {
+	return yunyun.RelativePathDir(filepath.Clean(strings.Trim(string(dir), "/")))
+}
+
+// insideAny returns true if the location is in one of the directories.
+func insideAny(location yunyun.RelativePathDir, dirs []yunyun.RelativePathDir) bool {
+	for _, dir := range dirs {
+		if yunyun.IsInsideDir(location, cleanDir(dir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// deepestDir returns the index of the rule whose directory has the location
+// and is the deepest one to have it, or -1 if no rule's directory has it.
+func deepestDir[T any](location yunyun.RelativePathDir, rules []T, dir func(T) yunyun.RelativePathDir) int {
+	best, longest := -1, -1
+	for i, rule := range rules {
+		matched := cleanDir(dir(rule))
+		if !yunyun.IsInsideDir(location, matched) {
+			continue
+		}
+		// The root has every page, so any other directory is deeper.
+		length := len(matched)
+		if matched == "." {
+			length = 0
+		}
+		if length > longest {
+			best, longest = i, length
+		}
+	}
+	return best
+}
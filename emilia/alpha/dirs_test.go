@@ -0,0 +1,45 @@
+package alpha
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestDeepestDir tests that the deepest directory with the location wins,
+// however the directories are written in the config
+func TestDeepestDir(t *testing.T) {
+	rules := []SitemapRule{{Dir: "/"}, {Dir: "blog/"}, {Dir: "blog/drafts"}, {Dir: "/notes/"}}
+	dir := func(rule SitemapRule) yunyun.RelativePathDir { return rule.Dir }
+	tests := []struct {
+		location yunyun.RelativePathDir
+		expected int
+	}{
+		{".", 0},
+		{"about", 0},
+		{"blog", 1},
+		{"blog/post", 1},
+		{"blogroll", 0},
+		{"blog/drafts/wip", 2},
+		{"notes", 3},
+	}
+	for _, tt := range tests {
+		if got := deepestDir(tt.location, rules, dir); got != tt.expected {
+			t.Errorf("deepestDir(%q) = %d, expected %d", tt.location, got, tt.expected)
+		}
+	}
+	if got := deepestDir("blog", rules[3:], dir); got != -1 {
+		t.Errorf("deepestDir() = %d without a matching rule, expected -1", got)
+	}
+}
+
+// TestFiltersFor tests that the filters only run on the pages inside their directories
+func TestFiltersFor(t *testing.T) {
+	hooks := &HooksConfig{Filters: []FilterConfig{{Command: "a"}, {Command: "b", Dir: "blog/"}}}
+	if got := len(hooks.FiltersFor("blogroll")); got != 1 {
+		t.Errorf("got %d filters for blogroll, expected 1", got)
+	}
+	if got := len(hooks.FiltersFor("blog/post")); got != 2 {
+		t.Errorf("got %d filters for blog/post, expected 2", got)
+	}
+}
@@ -0,0 +1,73 @@
+package alpha
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Enricher is an enrichment step added by the program embedding darkness.
+type Enricher struct {
+	// Name is the name of the step in darkness.toml, the built-in
+	// step with the same name is replaced in its place.
+	Name string
+
+	// Enrich returns the page option of the step for the site, with the step's
+	// options from darkness.toml, which are nil if the step has none.
+	Enrich func(conf *DarknessConfig, site *yunyun.Site, options map[string]any) yunyun.PageOption
+}
+
+// Rule returns the enrichment rule for the given location, which is the rule with
+// the longest matching directory merged into the section, so its order wins, its
+// options are added on top, and the steps it disables or enables are applied.
+func (e *EnrichmentConfig) Rule(location yunyun.RelativePathDir) EnrichmentRule {
+	best := deepestDir(location, e.Rules, func(rule EnrichmentRule) yunyun.RelativePathDir { return rule.Dir })
+	rule := EnrichmentRule{Order: e.Order, Disable: e.Disable, Options: e.Options}
+	if best < 0 {
+		return rule
+	}
+	override := e.Rules[best]
+	rule.Dir = override.Dir
+	if len(override.Order) > 0 {
+		rule.Order = override.Order
+	}
+	rule.Disable = slices.DeleteFunc(slices.Concat(rule.Disable, override.Disable), func(step string) bool {
+		return slices.Contains(override.Enable, step)
+	})
+	if len(override.Options) > 0 {
+		rule.Options = maps.Clone(e.Options)
+		if rule.Options == nil {
+			rule.Options = make(map[string]map[string]any, len(override.Options))
+		}
+		for step, options := range override.Options {
+			merged := maps.Clone(rule.Options[step])
+			if merged == nil {
+				merged = make(map[string]any, len(options))
+			}
+			maps.Copy(merged, options)
+			rule.Options[step] = merged
+		}
+	}
+	return rule
+}
+
+// OptionTables returns the options of the steps of the section and
+// of its rules, in the order they're written.
+func (e *EnrichmentConfig) OptionTables() []map[string]map[string]any {
+	tables := []map[string]map[string]any{e.Options}
+	for _, rule := range e.Rules {
+		tables = append(tables, rule.Options)
+	}
+	return tables
+}
+
+// Steps returns the names of all the steps mentioned in the section.
+func (e *EnrichmentConfig) Steps() []string {
+	steps := slices.Concat(e.Order, e.Disable, slices.Collect(maps.Keys(e.Options)))
+	for _, rule := range e.Rules {
+		steps = slices.Concat(steps, rule.Order, rule.Disable, rule.Enable, slices.Collect(maps.Keys(rule.Options)))
+	}
+	slices.Sort(steps)
+	return slices.Compact(steps)
+}
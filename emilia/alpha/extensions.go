@@ -1,11 +1,14 @@
 package alpha
 
 import (
+	"fmt"
+
 	"github.com/thecsw/darkness/v3/emilia/puck"
 )
 
-// setupProjectExtensions sets up the input/output extensions for the project.
-func (conf *DarknessConfig) setupProjectExtensions(options Options) {
+// setupProjectExtensions sets up the input/output extensions for the project,
+// which have to be the ones darkness can parse and export.
+func (conf *DarknessConfig) setupProjectExtensions(options Options) error {
 	// If input/output formats are empty, default to .org/.html respectively.
 	if isUnset(conf.Project.Input) {
 		conf.Runtime.Logger.Warn("Input format not found, using a default", "ext", puck.ExtensionOrgmode)
@@ -24,4 +27,12 @@ func (conf *DarknessConfig) setupProjectExtensions(options Options) {
 		conf.Runtime.Logger.Warn("Output extension was overwritten", "ext", options.OutputExtension)
 		conf.Project.Output = options.OutputExtension
 	}
+
+	if conf.Project.Input != puck.ExtensionOrgmode {
+		return fmt.Errorf("unknown input format: %s", conf.Project.Input)
+	}
+	if conf.Project.Output != puck.ExtensionHtml {
+		return fmt.Errorf("unknown output type: %s", conf.Project.Output)
+	}
+	return nil
 }
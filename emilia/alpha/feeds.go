@@ -0,0 +1,69 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// FeedFormatRss is the RSS 2.0 feed format.
+	FeedFormatRss = "rss"
+	// FeedFormatAtom is the Atom feed format.
+	FeedFormatAtom = "atom"
+	// FeedFormatJson is the JSON Feed 1.1 format.
+	FeedFormatJson = "json"
+)
+
+// defaultFeedPaths are the default paths of feeds by their format.
+var defaultFeedPaths = map[string]yunyun.RelativePathFile{
+	FeedFormatRss:  "feed.xml",
+	FeedFormatAtom: "atom.xml",
+	FeedFormatJson: "feed.json",
+}
+
+// FeedMimeTypes are the mime types of feeds by their format.
+var FeedMimeTypes = map[string]string{
+	FeedFormatRss:  "application/rss+xml",
+	FeedFormatAtom: "application/atom+xml",
+	FeedFormatJson: "application/feed+json",
+}
+
+// setupFeeds validates the configured feeds and fills in the default paths,
+// feeds without a language are split into a feed per language of the site.
+func (conf *DarknessConfig) setupFeeds() {
+	feeds := make([]FeedConfig, 0, len(conf.Feeds))
+	for _, feed := range conf.Feeds {
+		feed.Format = strings.ToLower(strings.TrimSpace(feed.Format))
+		defaultPath, known := defaultFeedPaths[feed.Format]
+		if !known {
+			conf.Runtime.Logger.Warn("Skipping feed with unknown format", "format", feed.Format, "path", feed.Path)
+			continue
+		}
+		if isUnset(feed.Path) {
+			feed.Path = defaultPath
+		}
+		if isSet(feed.Language) {
+			feed.Language = yunyun.NormalizeLanguage(feed.Language)
+			feeds = append(feeds, feed)
+			continue
+		}
+		for _, language := range conf.Website.AllLanguages() {
+			languageFeed := feed
+			languageFeed.Language = language
+			if language != conf.Website.Language() {
+				languageFeed.Path = feedPathForLanguage(feed.Path, language)
+			}
+			feeds = append(feeds, languageFeed)
+		}
+	}
+	conf.Feeds = feeds
+}
+
+// feedPathForLanguage returns the path of the feed in the language,
+// so `feed.xml` in japanese is `feed.ja.xml`.
+func feedPathForLanguage(path yunyun.RelativePathFile, language string) yunyun.RelativePathFile {
+	ext := filepath.Ext(string(path))
+	return yunyun.RelativePathFile(strings.TrimSuffix(string(path), ext) + "." + language + ext)
+}
@@ -18,6 +18,8 @@ const (
 
 	// what we pass to --pretty
 	gitPretty = "format:%cd"
+	// gitPrettyMarker marks the commit lines when listing file names.
+	gitPrettyMarker = "darkness-commit "
 	// this is what we pass to git --date to generate RFC3339
 	rfc3339GitFormat = "format:%Y-%m-%dT%H:%M:%SZ%z"
 	rfc3339Pattern   = "2006-01-02T15:04:05Z-0700"
@@ -83,8 +85,8 @@ func isPathSafe(path string) bool {
 	}
 	
 	// Ensure it doesn't have any suspicious characters
-	// Only allow alphanumeric, '_', '-', '/', '.', and space
-	safePattern := regexp.MustCompile(`^[a-zA-Z0-9_\-/\. ]+$`)
+	// Only allow letters, digits, '_', '-', '/', '.', and space
+	safePattern := regexp.MustCompile(`^[\p{L}\p{M}\p{N}_\-/\. ]+$`)
 	return safePattern.MatchString(cleanPath)
 }
 
@@ -106,3 +108,41 @@ func ExtractGitLastModified(conf *DarknessConfig, path yunyun.RelativePathFile)
 	}
 	return time.Parse(rfc3339Pattern, string(out))
 }
+
+// ExtractGitLastModifiedAll walks the git history once and returns the date of
+// when each file (relative to the working directory) was last modified. This is
+// much faster than calling `ExtractGitLastModified` on every single file.
+func ExtractGitLastModifiedAll(conf *DarknessConfig) (map[yunyun.RelativePathFile]time.Time, error) {
+	// With -z, the file names are separated by NULs and not quoted, so the
+	// names with spaces or non-ASCII letters come out as they are.
+	cmd := exec.Command("git", "log", "-z", "--relative", "--name-only", "--date", rfc3339GitFormat, "--pretty=format:"+gitPrettyMarker+"%cd", "--", ".")
+	cmd.Dir = string(conf.Runtime.WorkDir)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("getting git history: %v", err)
+	}
+
+	lastModified := make(map[yunyun.RelativePathFile]time.Time)
+	var current time.Time
+	for name := range strings.SplitSeq(string(out), "\x00") {
+		// Commits start with the marker and the date, followed by a newline
+		// and the first file name of the commit.
+		if header, isCommit := strings.CutPrefix(name, gitPrettyMarker); isCommit {
+			date, first, _ := strings.Cut(header, "\n")
+			current, err = time.Parse(rfc3339Pattern, date)
+			if err != nil {
+				return nil, fmt.Errorf("parsing git date %s: %v", date, err)
+			}
+			name = first
+		}
+		if len(name) < 1 {
+			continue
+		}
+		// The log goes from newest to oldest, so only keep the first date.
+		if _, seen := lastModified[yunyun.RelativePathFile(name)]; !seen {
+			lastModified[yunyun.RelativePathFile(name)] = current
+		}
+	}
+	return lastModified, nil
+}
@@ -0,0 +1,66 @@
+package alpha
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// runGit runs git with the arguments in the directory and fails the test on errors.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=darkness", "-c", "user.email=darkness@example.com"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2024-01-02T03:04:05Z", "GIT_AUTHOR_DATE=2024-01-02T03:04:05Z")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// TestExtractGitLastModifiedAll tests that the file names with spaces and
+// non-ASCII letters get their dates, instead of git's quoted names
+func TestExtractGitLastModifiedAll(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	files := []string{"日本.org", "notes/café au lait.org", "plain.org"}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte("* "+file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "pages")
+
+	conf := &DarknessConfig{}
+	conf.Runtime.WorkDir = WorkingDirectory(dir)
+	lastModified, err := ExtractGitLastModifiedAll(conf)
+	if err != nil {
+		t.Fatalf("ExtractGitLastModifiedAll() error = %v", err)
+	}
+	if len(lastModified) != len(files) {
+		t.Errorf("got %d files, want %d: %v", len(lastModified), len(files), lastModified)
+	}
+	for _, file := range files {
+		date, found := lastModified[yunyun.RelativePathFile(file)]
+		if !found {
+			t.Errorf("%s is missing", file)
+			continue
+		}
+		if want := "2024-01-02T03:04:05Z"; date.UTC().Format("2006-01-02T15:04:05Z") != want {
+			t.Errorf("%s date = %v, want %s", file, date, want)
+		}
+		single, err := ExtractGitLastModified(conf, yunyun.RelativePathFile(file))
+		if err != nil || !single.Equal(date) {
+			t.Errorf("ExtractGitLastModified(%s) = %v, %v, want %v", file, single, err, date)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package alpha
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// defaultHookTimeout is how long a pre or post build command can run if not set.
+	defaultHookTimeout = 10 * time.Minute
+	// defaultFilterTimeout is how long a filter can run per page if not set.
+	defaultFilterTimeout = 30 * time.Second
+)
+
+// setupHooks fills in the hooks defaults and validates the filters.
+func (conf *DarknessConfig) setupHooks() error {
+	if isUnset(conf.Hooks.Timeout) {
+		conf.Hooks.Timeout = defaultHookTimeout
+	}
+	for i := range conf.Hooks.Filters {
+		filter := &conf.Hooks.Filters[i]
+		if len(strings.TrimSpace(filter.Command)) < 1 {
+			return fmt.Errorf("hook filter #%d has no command", i)
+		}
+		if isUnset(filter.Timeout) {
+			filter.Timeout = defaultFilterTimeout
+		}
+		filter.Dir = cleanDir(filter.Dir)
+	}
+	return nil
+}
+
+// FiltersFor returns the filters for the pages at the location.
+func (h *HooksConfig) FiltersFor(location yunyun.RelativePathDir) []FilterConfig {
+	filters := make([]FilterConfig, 0, len(h.Filters))
+	for _, filter := range h.Filters {
+		if yunyun.IsInsideDir(location, cleanDir(filter.Dir)) {
+			filters = append(filters, filter)
+		}
+	}
+	return filters
+}
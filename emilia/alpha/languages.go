@@ -0,0 +1,51 @@
+package alpha
+
+import (
+	"slices"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// defaultLanguage is the language of the pages if the site has no locale.
+const defaultLanguage = "en"
+
+// setupLanguages normalizes the site's languages and drops the bad ones.
+func (conf *DarknessConfig) setupLanguages() {
+	languages := make([]string, 0, len(conf.Website.Languages))
+	for _, language := range conf.Website.Languages {
+		language = yunyun.NormalizeLanguage(language)
+		if !yunyun.IsLanguageTag(language) {
+			conf.Runtime.Logger.Warn("Skipping bad language tag", "language", language)
+			continue
+		}
+		if language == conf.Website.Language() || slices.Contains(languages, language) {
+			continue
+		}
+		languages = append(languages, language)
+	}
+	conf.Website.Languages = languages
+}
+
+// setupMessages builds the catalog of messages with the overrides, which
+// falls back to the site's default language.
+func (conf *DarknessConfig) setupMessages() {
+	messages, err := beatrice.NewCatalog(conf.Website.Language(), conf.Messages)
+	if err != nil {
+		conf.Runtime.Logger.Warn("Skipping bad messages", "err", err)
+	}
+	conf.Runtime.Messages = messages
+}
+
+// Language returns the default language of the pages, taken from the locale.
+func (w WebsiteConfig) Language() string {
+	if language := yunyun.NormalizeLanguage(w.Locale); yunyun.IsLanguageTag(language) {
+		return language
+	}
+	return defaultLanguage
+}
+
+// AllLanguages returns the default language followed by the other languages.
+func (w WebsiteConfig) AllLanguages() []string {
+	return append([]string{w.Language()}, w.Languages...)
+}
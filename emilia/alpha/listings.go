@@ -0,0 +1,27 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// setupListings cleans up the listing directories and fills in the defaults.
+func (conf *DarknessConfig) setupListings() {
+	for i := range conf.Listings {
+		listing := &conf.Listings[i]
+		listing.Dir = yunyun.RelativePathDir(filepath.Clean(strings.Trim(string(listing.Dir), "/")))
+		if isUnset(listing.Title) {
+			listing.Title = filepath.Base(string(listing.Dir))
+			if listing.Dir == "." {
+				listing.Title = conf.Title
+			}
+		}
+		if listing.Sort != yunyun.ListingSortTitle {
+			listing.Sort = yunyun.ListingSortDate
+		}
+		listing.Limit = max(listing.Limit, 0)
+		listing.PageSize = max(listing.PageSize, 0)
+	}
+}
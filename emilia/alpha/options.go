@@ -1,5 +1,11 @@
 package alpha
 
+import (
+	"io"
+
+	l "github.com/charmbracelet/log"
+)
+
 // Options is used for passing options when initiating emilia.
 type Options struct {
 	// DarknessConfig is the location of darkness's toml config file.
@@ -25,4 +31,32 @@ type Options struct {
 
 	// VendorGalleries dictates whether we should stub in local gallery images.
 	VendorGalleries bool
+
+	// Strict makes the warnings fail the build.
+	Strict bool
+
+	// Workers is the number of workers processing the pages, 4 if unset.
+	Workers int
+
+	// Akaneless skips the post-processing, like page previews.
+	Akaneless bool
+
+	// Force redoes the post-processing even if its outputs exist.
+	Force bool
+
+	// BuildReport produces a report of where the build spent its time.
+	BuildReport bool
+
+	// Lfs enables linking the Git LFS images.
+	Lfs bool
+
+	// Stdout is where the build progress, summaries, and hooks' output go,
+	// os.Stdout if nil.
+	Stdout io.Writer
+
+	// Enrichers are the enrichment steps to add after the built-in ones.
+	Enrichers []Enricher
+
+	// Logger is where the build logs, a logger of warnings on stderr if nil.
+	Logger *l.Logger
 }
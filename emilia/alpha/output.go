@@ -0,0 +1,47 @@
+package alpha
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// setupOutputDirectory points the outputs into the output directory if the user
+// wants to keep the source tree clean, and keeps darkness from looking into it.
+func (conf *DarknessConfig) setupOutputDirectory() error {
+	conf.Runtime.OutputDir = conf.Runtime.WorkDir
+	if isUnset(conf.Project.OutputDirectory) {
+		return nil
+	}
+	dir := filepath.Clean(string(conf.Project.OutputDirectory))
+	if filepath.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		return fmt.Errorf("output directory %s has to be inside the working directory", dir)
+	}
+	conf.Project.OutputDirectory = yunyun.RelativePathDir(dir)
+	conf.Runtime.OutputDir = WorkingDirectory(conf.Runtime.WorkDir.JoinGeneric(dir))
+	conf.Project.inputRoot, conf.Project.outputRoot = string(conf.Runtime.WorkDir), string(conf.Runtime.OutputDir)
+
+	// The outputs are not inputs, don't look for pages or watch for changes there.
+	conf.Project.Exclude = append(conf.Project.Exclude, conf.Project.OutputDirectory)
+
+	// Local urls point at the files, so point them at the output directory.
+	if conf.Runtime.isUrlLocal {
+		conf.Url = filepath.Join(conf.Url, dir) + "/"
+	}
+	return nil
+}
+
+// intoOutputDirectory moves the filename from the working directory into
+// the output directory, if there is one.
+func (p ProjectConfig) intoOutputDirectory(filename string) string {
+	if len(p.outputRoot) < 1 {
+		return filename
+	}
+	relative, err := filepath.Rel(p.inputRoot, filename)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, "../") {
+		return filename
+	}
+	return filepath.Join(p.outputRoot, relative)
+}
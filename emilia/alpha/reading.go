@@ -0,0 +1,19 @@
+package alpha
+
+const (
+	// defaultWordsPerMinute is the reading speed of words if not set.
+	defaultWordsPerMinute = 230
+
+	// defaultCharactersPerMinute is the reading speed of CJK characters if not set.
+	defaultCharactersPerMinute = 500
+)
+
+// setupReading fills in the reading speed defaults.
+func (conf *DarknessConfig) setupReading() {
+	if conf.Reading.WordsPerMinute < 1 {
+		conf.Reading.WordsPerMinute = defaultWordsPerMinute
+	}
+	if conf.Reading.CharactersPerMinute < 1 {
+		conf.Reading.CharactersPerMinute = defaultCharactersPerMinute
+	}
+}
@@ -0,0 +1,13 @@
+package alpha
+
+const (
+	// defaultRelatedCount is the number of related pages if not set.
+	defaultRelatedCount = 5
+)
+
+// setupRelated fills in the related pages defaults.
+func (conf *DarknessConfig) setupRelated() {
+	if conf.Related.Count < 1 {
+		conf.Related.Count = defaultRelatedCount
+	}
+}
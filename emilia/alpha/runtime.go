@@ -1,9 +1,13 @@
 package alpha
 
 import (
+	"io"
 	"net/url"
 
 	l "github.com/charmbracelet/log"
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 )
 
 // WorkingDirectory is the directory of where darkness project lives.
@@ -15,6 +19,10 @@ type RuntimeConfig struct {
 	// WorkDir is the directory of where darkness project lives.
 	WorkDir WorkingDirectory
 
+	// OutputDir is the directory where the outputs are written, the
+	// working directory unless the output directory is set.
+	OutputDir WorkingDirectory
+
 	// Slice with just `Url` in it.
 	urlSlice []string
 
@@ -37,4 +45,41 @@ type RuntimeConfig struct {
 
 	// WriteParsedPagesAsJson will flush parsed pages as json in the same dir.
 	WriteParsedPagesAsJson bool
+
+	// Messages are the user-visible strings in all the languages.
+	Messages *beatrice.Catalog
+
+	// Manifest records the outputs of the current build, nil outside of builds.
+	Manifest *uiharu.Manifest
+
+	// Problems are what went wrong in the current build.
+	Problems *hitagi.Report
+
+	// Workers is the number of workers processing the pages.
+	Workers int
+
+	// Debug enables the debug knobs of the workers.
+	Debug bool
+
+	// Akaneless skips the post-processing.
+	Akaneless bool
+
+	// Force redoes the post-processing even if its outputs exist.
+	Force bool
+
+	// BuildReport produces a report of where the build spent its time.
+	BuildReport bool
+
+	// Lfs enables linking the Git LFS images.
+	Lfs bool
+
+	// Stdout is where the build progress, summaries, and hooks' output go.
+	Stdout io.Writer
+
+	// Enrichers are the enrichment steps added by the program embedding
+	// darkness, in the order they were added.
+	Enrichers []Enricher
+
+	// State is what the packages remember during the current build.
+	State *BuildState
 }
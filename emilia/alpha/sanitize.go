@@ -0,0 +1,148 @@
+package alpha
+
+import (
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// iframeTag is only allowed when the iframes are enabled or listed.
+const iframeTag = "iframe"
+
+var (
+	// defaultSanitizeTags are the html tags allowed in raw html blocks by default,
+	// iframes are left out as they would embed any website, see `iframes`.
+	defaultSanitizeTags = []string{
+		"a", "abbr", "audio", "b", "blockquote", "br", "caption", "center", "cite",
+		"code", "dd", "del", "details", "div", "dl", "dt", "em", "figcaption", "figure",
+		"h1", "h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd",
+		"li", "mark", "ol", "p", "picture", "pre", "q", "s", "samp", "small", "source",
+		"span", "strong", "sub", "summary", "sup", "table", "tbody", "td", "tfoot",
+		"th", "thead", "time", "tr", "track", "u", "ul", "var", "video", "wbr",
+	}
+
+	// defaultSanitizeAttributes are the html attributes allowed in raw html blocks by default.
+	defaultSanitizeAttributes = []string{
+		"alt", "allow", "allowfullscreen", "class", "colspan", "controls", "datetime",
+		"frameborder", "height", "href", "id", "lang", "loading", "loop", "muted",
+		"name", "open", "poster", "rel", "rowspan", "src", "srcset", "start", "target",
+		"title", "type", "width", "aria-*", "data-*",
+	}
+
+	// defaultSanitizeSchemes are the url schemes allowed by default.
+	defaultSanitizeSchemes = []string{"http", "https", "mailto", "tel", "ftp"}
+
+	// defaultSanitizeTagsSet and defaultSanitizeSchemesSet are used when the
+	// config was built by hand, without going through `BuildConfig`.
+	defaultSanitizeTagsSet    = toLowerSet(defaultSanitizeTags)
+	defaultSanitizeSchemesSet = toLowerSet(defaultSanitizeSchemes)
+)
+
+// setupSanitizer fills in the default sanitization policy and builds
+// the lookup sets out of it.
+func (conf *DarknessConfig) setupSanitizer() {
+	s := &conf.Sanitize
+	if len(s.Tags) < 1 {
+		s.Tags = defaultSanitizeTags
+	}
+	if len(s.Attributes) < 1 {
+		s.Attributes = defaultSanitizeAttributes
+	}
+	if len(s.Schemes) < 1 {
+		s.Schemes = defaultSanitizeSchemes
+	}
+	s.tags = toLowerSet(s.Tags)
+	if s.Iframes {
+		s.tags[iframeTag] = struct{}{}
+	}
+	for i, trusted := range s.Trusted {
+		s.Trusted[i] = cleanDir(trusted)
+	}
+	s.schemes = toLowerSet(s.Schemes)
+	s.attributes = map[string]struct{}{}
+	s.attributePrefixes = nil
+	for _, attribute := range s.Attributes {
+		attribute = strings.ToLower(strings.TrimSpace(attribute))
+		if prefix, starred := strings.CutSuffix(attribute, "*"); starred {
+			s.attributePrefixes = append(s.attributePrefixes, prefix)
+			continue
+		}
+		s.attributes[attribute] = struct{}{}
+	}
+}
+
+// ShouldSanitize returns true if a raw html block on a page with the given
+// location needs to go through the sanitizer. Blocks marked as `unsafe` are
+// only trusted when the policy is not strict.
+func (s *SanitizeConfig) ShouldSanitize(location yunyun.RelativePathDir, unsafe bool) bool {
+	if !s.Enable {
+		return false
+	}
+	if unsafe && !s.Strict {
+		return false
+	}
+	return !insideAny(location, s.Trusted)
+}
+
+// AllowsTag returns true if the (lowercase) tag is allowed.
+func (s *SanitizeConfig) AllowsTag(tag string) bool {
+	tags := s.tags
+	if tags == nil {
+		tags = defaultSanitizeTagsSet
+	}
+	_, ok := tags[tag]
+	return ok || (tag == iframeTag && s.Iframes)
+}
+
+// AllowsAttribute returns true if the (lowercase) attribute is allowed.
+// Event handlers, like `onclick`, are never allowed.
+func (s *SanitizeConfig) AllowsAttribute(attribute string) bool {
+	if strings.HasPrefix(attribute, "on") {
+		return false
+	}
+	if s.attributes == nil {
+		return s.allowsDefaultAttribute(attribute)
+	}
+	if _, ok := s.attributes[attribute]; ok {
+		return true
+	}
+	for _, prefix := range s.attributePrefixes {
+		if strings.HasPrefix(attribute, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsDefaultAttribute checks the attribute against the defaults, used
+// when the config was built by hand and the sets are not there.
+func (s *SanitizeConfig) allowsDefaultAttribute(attribute string) bool {
+	for _, allowed := range defaultSanitizeAttributes {
+		if prefix, starred := strings.CutSuffix(allowed, "*"); starred && strings.HasPrefix(attribute, prefix) {
+			return true
+		}
+		if allowed == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsScheme returns true if the (lowercase) url scheme is allowed.
+func (s *SanitizeConfig) AllowsScheme(scheme string) bool {
+	schemes := s.schemes
+	if schemes == nil {
+		schemes = defaultSanitizeSchemesSet
+	}
+	_, ok := schemes[scheme]
+	return ok
+}
+
+// toLowerSet returns a set of trimmed and lowercased strings.
+func toLowerSet(what []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(what))
+	for _, v := range what {
+		set[strings.ToLower(strings.TrimSpace(v))] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,55 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// defaultSearchDir is where the search page and the index go if not set.
+	defaultSearchDir yunyun.RelativePathDir = "search"
+
+	// defaultSearchShardDepth is the depth of the index shards if not set.
+	defaultSearchShardDepth = 1
+
+	// searchManifestFilename is the file listing all the index shards.
+	searchManifestFilename = "index.json"
+)
+
+// setupSearch fills in the search defaults.
+func (conf *DarknessConfig) setupSearch() {
+	conf.Search.Dir = cleanDir(conf.Search.Dir)
+	if conf.Search.Dir == "." {
+		conf.Search.Dir = defaultSearchDir
+	}
+	if isUnset(conf.Search.Title) {
+		conf.Search.Title = conf.Runtime.Messages.Text(conf.Website.Language(), beatrice.Search)
+	}
+	if conf.Search.ShardDepth < 1 {
+		conf.Search.ShardDepth = defaultSearchShardDepth
+	}
+}
+
+// ManifestPath returns the relative path of the search index manifest.
+func (s SearchConfig) ManifestPath() yunyun.RelativePathFile {
+	return yunyun.JoinRelativePaths(s.Dir, searchManifestFilename)
+}
+
+// ShardPath returns the relative path of the search index shard.
+func (s SearchConfig) ShardPath(shard string) yunyun.RelativePathFile {
+	return yunyun.JoinRelativePaths(s.Dir, yunyun.RelativePathFile("index-"+shard+".json"))
+}
+
+// Shard returns the name of the index shard that the page at the location
+// goes into, which is its parent directory cut down to the shard depth.
+func (s SearchConfig) Shard(location yunyun.RelativePathDir) string {
+	parent := filepath.ToSlash(filepath.Dir(string(location)))
+	if parent == "." {
+		return "root"
+	}
+	parts := strings.Split(parent, "/")
+	return strings.Join(parts[:min(len(parts), s.ShardDepth)], "-")
+}
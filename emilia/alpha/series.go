@@ -0,0 +1,21 @@
+package alpha
+
+import "github.com/thecsw/darkness/v3/yunyun"
+
+// setupSeries cleans up the series directories.
+func (conf *DarknessConfig) setupSeries() {
+	for i, dir := range conf.Series.Dirs {
+		conf.Series.Dirs[i] = cleanDir(dir)
+	}
+}
+
+// Shows returns true if the page at the location is inside one of the
+// directories, whose pages link to each other.
+func (s SeriesConfig) Shows(location yunyun.RelativePathDir) bool {
+	for _, dir := range s.Dirs {
+		if location != dir && yunyun.IsInsideDir(location, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package alpha
+
+import "github.com/thecsw/darkness/v3/yunyun"
+
+const (
+	// defaultSitemapPath is where the sitemap goes if not set.
+	defaultSitemapPath yunyun.RelativePathFile = "sitemap.xml"
+)
+
+// setupSitemap fills in the sitemap defaults.
+func (conf *DarknessConfig) setupSitemap() {
+	if isUnset(conf.Sitemap.Path) {
+		conf.Sitemap.Path = defaultSitemapPath
+	}
+}
+
+// Rule returns the sitemap rule for the given location, which is the rule
+// with the longest matching directory, falling back to the section defaults.
+func (s *SitemapConfig) Rule(location yunyun.RelativePathDir) SitemapRule {
+	best := deepestDir(location, s.Rules, func(rule SitemapRule) yunyun.RelativePathDir { return rule.Dir })
+	rule := SitemapRule{ChangeFreq: s.ChangeFreq, Priority: s.Priority}
+	if best < 0 {
+		return rule
+	}
+	rule.Dir, rule.Exclude = s.Rules[best].Dir, s.Rules[best].Exclude
+	if len(s.Rules[best].ChangeFreq) > 0 {
+		rule.ChangeFreq = s.Rules[best].ChangeFreq
+	}
+	if len(s.Rules[best].Priority) > 0 {
+		rule.Priority = s.Rules[best].Priority
+	}
+	return rule
+}
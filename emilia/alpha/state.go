@@ -0,0 +1,39 @@
+package alpha
+
+import "sync"
+
+// BuildState is what the packages remember while building the site, under
+// keys of their own types, so that the sites built at the same time don't
+// share it and nothing lingers after the build. The nil state remembers
+// nothing, every value it loads is made anew.
+type BuildState struct {
+	values sync.Map
+}
+
+// Load returns the value under the key, storing the one made by create if
+// there's none yet.
+func (s *BuildState) Load(key any, create func() any) any {
+	if s == nil {
+		return create()
+	}
+	if value, found := s.values.Load(key); found {
+		return value
+	}
+	value, _ := s.values.LoadOrStore(key, create())
+	return value
+}
+
+// Take returns the value under the key and forgets it.
+func (s *BuildState) Take(key any) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	return s.values.LoadAndDelete(key)
+}
+
+// Reset forgets everything the build remembered.
+func (s *BuildState) Reset() {
+	if s != nil {
+		s.values.Clear()
+	}
+}
@@ -0,0 +1,32 @@
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
+)
+
+// setupStrict validates the warning classes, defaulting to all of them,
+// and starts the report of the problems.
+func (conf *DarknessConfig) setupStrict(options Options) error {
+	conf.Strict.Enable = conf.Strict.Enable || options.Strict
+	if len(conf.Strict.Warnings) < 1 {
+		conf.Strict.Warnings = hitagi.Classes
+	}
+	for _, class := range conf.Strict.Warnings {
+		if !hitagi.IsClass(class) {
+			return fmt.Errorf("unknown strict warning class %q, the known ones are %v", class, hitagi.Classes)
+		}
+	}
+	conf.Runtime.Problems = conf.Strict.NewReport()
+	return nil
+}
+
+// NewReport returns an empty report of the problems, where the chosen
+// warnings fail the build if the strict mode is on.
+func (s StrictConfig) NewReport() *hitagi.Report {
+	if !s.Enable {
+		return hitagi.NewReport(nil)
+	}
+	return hitagi.NewReport(s.Warnings)
+}
@@ -0,0 +1,28 @@
+package alpha
+
+import "github.com/thecsw/darkness/v3/yunyun"
+
+// defaultStructuredDataType is the schema.org type of dated pages if not set.
+const defaultStructuredDataType = "Article"
+
+// setupStructuredData fills in the structured data defaults.
+func (conf *DarknessConfig) setupStructuredData() {
+	if isUnset(conf.StructuredData.Type) {
+		conf.StructuredData.Type = defaultStructuredDataType
+	}
+}
+
+// Rule returns the structured data rule for the given location, which is the rule
+// with the longest matching directory, falling back to the section defaults.
+func (s *StructuredDataConfig) Rule(location yunyun.RelativePathDir) StructuredDataRule {
+	best := deepestDir(location, s.Rules, func(rule StructuredDataRule) yunyun.RelativePathDir { return rule.Dir })
+	rule := StructuredDataRule{Type: s.Type}
+	if best < 0 {
+		return rule
+	}
+	rule.Dir, rule.Exclude = s.Rules[best].Dir, s.Rules[best].Exclude
+	if len(s.Rules[best].Type) > 0 {
+		rule.Type = s.Rules[best].Type
+	}
+	return rule
+}
@@ -0,0 +1,29 @@
+package alpha
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// defaultTagsDir is where the tag pages go if not set.
+const defaultTagsDir yunyun.RelativePathDir = "tags"
+
+// setupTags fills in the tags defaults.
+func (conf *DarknessConfig) setupTags() {
+	conf.Tags.Dir = yunyun.RelativePathDir(filepath.Clean(strings.Trim(string(conf.Tags.Dir), "/")))
+	if conf.Tags.Dir == "." {
+		conf.Tags.Dir = defaultTagsDir
+	}
+	if isUnset(conf.Tags.Title) {
+		conf.Tags.Title = conf.Runtime.Messages.Text(conf.Website.Language(), beatrice.Tags)
+	}
+	conf.Tags.PageSize = max(conf.Tags.PageSize, 0)
+}
+
+// TagLocation returns the location of the tag's page.
+func (t TagsConfig) TagLocation(slug string) yunyun.RelativePathDir {
+	return yunyun.RelativePathDir(filepath.Join(string(t.Dir), slug))
+}
@@ -2,7 +2,9 @@ package alpha
 
 import (
 	"regexp"
+	"time"
 
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
@@ -34,6 +36,52 @@ type DarknessConfig struct {
 
 	// External is config for external services.
 	External ExternalConfig `toml:"external"`
+
+	// Sanitize is the policy for raw html export blocks.
+	Sanitize SanitizeConfig `toml:"sanitize"`
+
+	// Feeds is the list of feeds (rss, atom, json) to generate.
+	Feeds []FeedConfig `toml:"feeds"`
+
+	// Sitemap is the sitemap section of the config.
+	Sitemap SitemapConfig `toml:"sitemap"`
+
+	// Listings are the directories that get generated index pages.
+	Listings []ListingConfig `toml:"listings"`
+
+	// Tags is the tags section of the config.
+	Tags TagsConfig `toml:"tags"`
+
+	// Related is the related pages section of the config.
+	Related RelatedConfig `toml:"related"`
+
+	// Search is the search section of the config.
+	Search SearchConfig `toml:"search"`
+
+	// Reading is the reading time section of the config.
+	Reading ReadingConfig `toml:"reading"`
+
+	// Backlinks is the backlinks section of the config.
+	Backlinks BacklinksConfig `toml:"backlinks"`
+
+	// Series is the series section of the config.
+	Series SeriesConfig `toml:"series"`
+
+	// StructuredData is the JSON-LD section of the config.
+	StructuredData StructuredDataConfig `toml:"structured_data"`
+
+	// Strict is the strict mode section of the config.
+	Strict StrictConfig `toml:"strict"`
+
+	// Enrichment is the enrichment steps section of the config.
+	Enrichment EnrichmentConfig `toml:"enrichment"`
+
+	// Hooks is the external commands section of the config.
+	Hooks HooksConfig `toml:"hooks"`
+
+	// Messages override the user-visible strings by their languages and
+	// names, like `[messages.ja]` with `table_of_contents = "目次"`.
+	Messages map[string]map[string]any `toml:"messages"`
 }
 
 // ProjectConfig is the project section of the config
@@ -54,6 +102,17 @@ type ProjectConfig struct {
 	// Excludes is the list of relative paths to exclude from the project
 	Exclude []yunyun.RelativePathDir `toml:"exclude"`
 
+	// KeepOrphans only reports the outputs of removed pages after a build,
+	// instead of removing them.
+	KeepOrphans bool `toml:"keep_orphans"`
+
+	// OutputDirectory is where the pages and the static files they use are
+	// written, next to the inputs if not set.
+	OutputDirectory yunyun.RelativePathDir `toml:"output_directory"`
+
+	// inputRoot and outputRoot are the working and output directories.
+	inputRoot, outputRoot string
+
 	ExcludeEnabled bool `toml:"-"`
 }
 
@@ -77,9 +136,14 @@ type WebsiteConfig struct {
 	PreviewGenNameFont  yunyun.RelativePathFile `toml:"preview_gen_name_font"`
 	PrevietGenTimeFont  yunyun.RelativePathFile `toml:"preview_gen_time_font"`
 
-	// Locale is the locale of the site
+	// Locale is the locale of the site, its language is
+	// the default language of the pages, defaults to "en".
 	Locale string `toml:"locale"`
 
+	// Languages are the other languages the site is translated
+	// into, every feed is split into one feed per language.
+	Languages []string `toml:"languages"`
+
 	// SyntaxHighlightingTheme decides what theme to use from highlight.js
 	SyntaxHighlightingTheme yunyun.RelativePathFile `toml:"syntax_highlighting_theme"`
 
@@ -220,3 +284,301 @@ type ExternalConfig struct {
 	GitBranch          string `toml:"git_branch"`
 	GitRemotesAreValid bool   `toml:"-"`
 }
+
+// SanitizeConfig is the policy applied to `#+begin_export html` blocks.
+type SanitizeConfig struct {
+	// Enable turns on sanitization of raw html blocks.
+	Enable bool `toml:"enable"`
+
+	// Strict will sanitize even the blocks marked as `unsafe`. By
+	// default, `unsafe` blocks are trusted and left untouched.
+	Strict bool `toml:"strict"`
+
+	// Trusted is the list of relative directories whose raw html
+	// blocks, including their subdirectories', are never sanitized.
+	Trusted []yunyun.RelativePathDir `toml:"trusted"`
+
+	// Tags is the allow-list of html tags, everything else is
+	// stripped (leaving the inner text), while scripts and styles
+	// are removed with their contents.
+	Tags []string `toml:"tags"`
+
+	// Iframes allows the iframes in the sanitized blocks, which can
+	// embed any website with an allowed scheme, so they are off by default.
+	Iframes bool `toml:"iframes"`
+
+	// Attributes is the allow-list of html attributes. A trailing
+	// star matches by prefix, like `data-*`.
+	Attributes []string `toml:"attributes"`
+
+	// Schemes is the allow-list of url schemes for links and sources,
+	// also used when escaping links in the regular text.
+	Schemes []string `toml:"schemes"`
+
+	// tags, attributes, schemes are the sets built from the lists above.
+	tags, attributes, schemes map[string]struct{}
+
+	// attributePrefixes are the starred attributes.
+	attributePrefixes []string
+}
+
+// FeedConfig is a single feed to generate, multiple feeds can be
+// listed with `[[feeds]]`.
+type FeedConfig struct {
+	// Format is the feed format, one of "rss", "atom", or "json".
+	Format string `toml:"format"`
+
+	// Path is the relative path of the feed file, defaults to
+	// "feed.xml", "atom.xml", or "feed.json" depending on the format.
+	Path yunyun.RelativePathFile `toml:"path"`
+
+	// Dirs are the relative paths of directories to include in the
+	// feed, empty means the whole website.
+	Dirs []string `toml:"dirs"`
+
+	// Language is the language of the pages in the feed, feeds without
+	// one are split into a feed per language of the site.
+	Language string `toml:"language"`
+}
+
+// SitemapConfig is the sitemap section of the config.
+type SitemapConfig struct {
+	// Enable will generate the sitemap on every build.
+	Enable bool `toml:"enable"`
+
+	// Path is the relative path of the sitemap, defaults to "sitemap.xml".
+	Path yunyun.RelativePathFile `toml:"path"`
+
+	// ChangeFreq is the default `<changefreq>` of pages, can be empty.
+	ChangeFreq string `toml:"changefreq"`
+
+	// Priority is the default `<priority>` of pages, can be empty.
+	Priority string `toml:"priority"`
+
+	// Rules are the per directory overrides, the rule with the
+	// longest matching directory wins.
+	Rules []SitemapRule `toml:"rules"`
+}
+
+// SitemapRule overrides the sitemap settings for a directory.
+type SitemapRule struct {
+	// Dir is the relative path of the directory this rule applies to.
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Exclude removes the pages of the directory from the sitemap.
+	Exclude bool `toml:"exclude"`
+
+	// ChangeFreq is the `<changefreq>` of the directory's pages.
+	ChangeFreq string `toml:"changefreq"`
+
+	// Priority is the `<priority>` of the directory's pages.
+	Priority string `toml:"priority"`
+}
+
+// StructuredDataConfig is the JSON-LD (schema.org) section of the config.
+type StructuredDataConfig struct {
+	// Enable adds the JSON-LD to every page, so search engines can
+	// show rich results for them.
+	Enable bool `toml:"enable"`
+
+	// Type is the default schema.org type of the dated pages, like
+	// "BlogPosting", defaults to "Article".
+	Type string `toml:"type"`
+
+	// Rules are the per directory overrides, the rule with the
+	// longest matching directory wins.
+	Rules []StructuredDataRule `toml:"rules"`
+}
+
+// StructuredDataRule overrides the JSON-LD settings for a directory.
+type StructuredDataRule struct {
+	// Dir is the relative path of the directory this rule applies to.
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Exclude removes the JSON-LD from the pages of the directory.
+	Exclude bool `toml:"exclude"`
+
+	// Type is the schema.org type of the directory's dated pages.
+	Type string `toml:"type"`
+}
+
+// StrictConfig makes the warnings fail the build, so CI doesn't deploy a
+// site with broken links or missing images.
+type StrictConfig struct {
+	// Enable fails the build on the warnings, same as `-strict`.
+	Enable bool `toml:"enable"`
+
+	// Warnings are the warning classes that fail the build, like
+	// "unresolved_links" and "missing_images", all of them by default.
+	Warnings []hitagi.Class `toml:"warnings"`
+}
+
+// EnrichmentConfig picks and orders the steps that enrich the parsed pages
+// before exporting, like "highlighting" or "related", by their names.
+type EnrichmentConfig struct {
+	// Order are the steps that run first and in this order, the rest
+	// follow in their default order.
+	Order []string `toml:"order"`
+
+	// Disable are the steps that don't run.
+	Disable []string `toml:"disable"`
+
+	// Options are the settings of the steps by their names, for the steps
+	// that have any, like `count` under `[enrichment.options.related]`.
+	Options map[string]map[string]any `toml:"options"`
+
+	// Rules are the per directory overrides, the rule with the
+	// longest matching directory wins.
+	Rules []EnrichmentRule `toml:"rules"`
+}
+
+// EnrichmentRule overrides the enrichment steps for a directory.
+type EnrichmentRule struct {
+	// Dir is the relative path of the directory this rule applies to.
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Order replaces the order of the steps for the directory.
+	Order []string `toml:"order"`
+
+	// Disable are the steps that don't run in the directory.
+	Disable []string `toml:"disable"`
+
+	// Enable are the steps disabled for the site that run in the directory.
+	Enable []string `toml:"enable"`
+
+	// Options override the settings of the steps for the directory.
+	Options map[string]map[string]any `toml:"options"`
+}
+
+// HooksConfig runs external commands before and after the build, and
+// pipes the exported pages through filters.
+type HooksConfig struct {
+	// PreBuild are the shell commands run in the working directory before
+	// the build, like fetching data or generating pages.
+	PreBuild []string `toml:"pre_build"`
+
+	// PostBuild are the shell commands run in the working directory after
+	// the build, unless it failed, like minifying or deploying.
+	PostBuild []string `toml:"post_build"`
+
+	// Timeout is how long a pre or post build command can run, like "5m",
+	// defaults to 10 minutes.
+	Timeout time.Duration `toml:"timeout"`
+
+	// Filters are the commands the exported pages go through.
+	Filters []FilterConfig `toml:"filters"`
+}
+
+// FilterConfig is a shell command that gets the exported page on stdin
+// and returns the page to write on stdout, declared with `[[hooks.filters]]`.
+type FilterConfig struct {
+	// Command is the shell command to run.
+	Command string `toml:"command"`
+
+	// Dir limits the filter to the pages of the directory, all by default.
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Timeout is how long the command can run per page, defaults to 30 seconds.
+	Timeout time.Duration `toml:"timeout"`
+}
+
+// ListingConfig generates an index page listing the pages of a directory,
+// multiple listings can be declared with `[[listings]]`. If the directory
+// already has its own index page, use `#+list_pages:` in it instead.
+type ListingConfig struct {
+	// Dir is the relative path of the directory to list.
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Title is the title of the generated page, defaults to the directory name.
+	Title string `toml:"title"`
+
+	// Sort is either "date" (newest first, default) or "title".
+	Sort yunyun.ListingSort `toml:"sort"`
+
+	// Limit is the maximum number of listed pages, 0 lists all of them.
+	Limit int `toml:"limit"`
+
+	// Recursive also lists the pages of nested directories.
+	Recursive bool `toml:"recursive"`
+
+	// PageSize splits the listing into pages like `page/2/`, 0 doesn't split.
+	PageSize int `toml:"page_size"`
+}
+
+// TagsConfig is the tags section of the config.
+type TagsConfig struct {
+	// Enable generates a page for every tag, the tags index,
+	// and links the tags from the pages' headers.
+	Enable bool `toml:"enable"`
+
+	// Dir is the relative path of the tag pages, defaults to "tags".
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Title is the title of the tags index page, defaults to
+	// "Tags" in the site's language.
+	Title string `toml:"title"`
+
+	// PageSize splits the tag pages into pages like `page/2/`, 0 doesn't split.
+	PageSize int `toml:"page_size"`
+}
+
+// RelatedConfig is the related pages section of the config.
+type RelatedConfig struct {
+	// Enable shows the related pages at the bottom of every dated page,
+	// pages can opt out with `related:nil` or opt in with `related:t`.
+	Enable bool `toml:"enable"`
+
+	// Count is the maximum number of related pages, defaults to 5.
+	Count int `toml:"count"`
+}
+
+// SearchConfig is the search section of the config.
+type SearchConfig struct {
+	// Enable builds the search index and the search page.
+	Enable bool `toml:"enable"`
+
+	// Dir is the relative path of the search page and the
+	// index files, defaults to "search".
+	Dir yunyun.RelativePathDir `toml:"dir"`
+
+	// Title is the title of the search page, defaults to
+	// "Search" in the site's language.
+	Title string `toml:"title"`
+
+	// ShardDepth is how many directories deep the index is split
+	// into separate files, defaults to 1 (one per top directory).
+	ShardDepth int `toml:"shard_depth"`
+}
+
+// ReadingConfig is the reading time section of the config.
+type ReadingConfig struct {
+	// Enable shows the reading time next to the page's date, in
+	// listings, and in feeds, pages can opt out with `reading-time:nil`.
+	Enable bool `toml:"enable"`
+
+	// WordsPerMinute is the reading speed of space-separated
+	// words, defaults to 230.
+	WordsPerMinute int `toml:"words_per_minute"`
+
+	// CharactersPerMinute is the reading speed of CJK text, which
+	// is counted by characters, defaults to 500.
+	CharactersPerMinute int `toml:"characters_per_minute"`
+}
+
+// BacklinksConfig is the backlinks section of the config.
+type BacklinksConfig struct {
+	// Dirs are the directories whose pages show the pages linking to them,
+	// pages can opt in or out with `backlinks:t` or `backlinks:nil`.
+	Dirs []yunyun.RelativePathDir `toml:"dirs"`
+
+	// Title is the title of the backlinks section, defaults
+	// to "Pages linking here" in the page's language.
+	Title string `toml:"title"`
+}
+
+// SeriesConfig is the series section of the config.
+type SeriesConfig struct {
+	// Dirs are the directories whose dated pages link to the previous
+	// and the next pages of the same directory, like one long series.
+	Dirs []yunyun.RelativePathDir `toml:"dirs"`
+}
@@ -1,6 +1,7 @@
 package alpha
 
 import (
+	"path/filepath"
 	"strings"
 
 	"github.com/thecsw/darkness/v3/yunyun"
@@ -11,12 +12,16 @@ const (
 	debugStructExtension = ".json"
 )
 
-// InputFilenameToOutput converts input filename to the filename to write.
+// InputFilenameToOutput converts input filename to the filename to write,
+// translations like `index.ja.org` are written to `ja/index.html`.
 func (p ProjectConfig) InputFilenameToOutput(file yunyun.FullPathFile) string {
-	return strings.Replace(string(file), p.Input, p.Output, 1)
+	if language := yunyun.FileLanguage(file); len(language) > 0 {
+		return p.intoOutputDirectory(filepath.Join(filepath.Dir(string(file)), language, "index"+p.Output))
+	}
+	return p.intoOutputDirectory(strings.Replace(string(file), p.Input, p.Output, 1))
 }
 
 // InputFilenameToDebugStruct flushes pages as json for debugging.
 func (p ProjectConfig) InputFilenameToDebugStruct(file yunyun.FullPathFile) string {
-	return strings.Replace(string(file), p.Input, debugStructExtension, 1)
+	return p.intoOutputDirectory(strings.Replace(string(file), p.Input, debugStructExtension, 1))
 }
@@ -0,0 +1,132 @@
+package beatrice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Message is a message in its plural forms, messages without
+// plurals only have the `Other` form.
+type Message map[Plural]string
+
+// Catalog holds the messages of all the languages with the user's
+// overrides on top of the built-in ones. A nil catalog is valid and
+// only has the built-in messages.
+type Catalog struct {
+	// fallback is the language to use if the message is missing in the asked one.
+	fallback string
+	// overrides are the messages from darkness.toml by their languages.
+	overrides map[string]map[Key]Message
+}
+
+// NewCatalog creates the catalog with the fallback language and the overrides
+// as decoded from `[messages.LANGUAGE]`, where a value is either a string or
+// a table of plural forms, like `days = { one = "%d day", other = "%d days" }`.
+// Bad overrides are skipped and returned as the error.
+func NewCatalog(fallback string, overrides map[string]map[string]any) (*Catalog, error) {
+	c := &Catalog{
+		fallback:  yunyun.NormalizeLanguage(fallback),
+		overrides: make(map[string]map[Key]Message, len(overrides)),
+	}
+	errs := make([]error, 0, 4)
+	for language, messages := range overrides {
+		language = yunyun.NormalizeLanguage(language)
+		if c.overrides[language] == nil {
+			c.overrides[language] = make(map[Key]Message, len(messages))
+		}
+		for key, value := range messages {
+			message, err := toMessage(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("message %s in %s: %w", key, language, err))
+				continue
+			}
+			c.overrides[language][Key(key)] = message
+		}
+	}
+	return c, errors.Join(errs...)
+}
+
+// toMessage turns the decoded toml value into a message.
+func toMessage(value any) (Message, error) {
+	switch value := value.(type) {
+	case string:
+		return text(value), nil
+	case map[string]any:
+		message := make(Message, len(value))
+		for form, formValue := range value {
+			s, ok := formValue.(string)
+			if !ok {
+				return nil, fmt.Errorf("plural form %s is not a string", form)
+			}
+			switch plural := Plural(form); plural {
+			case Zero, One, Two, Few, Many, Other:
+				message[plural] = s
+			default:
+				return nil, fmt.Errorf("unknown plural form %s", form)
+			}
+		}
+		if _, ok := message[Other]; !ok {
+			return nil, errors.New(`plural forms need the "other" form`)
+		}
+		return message, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a table of plural forms, got %T", value)
+	}
+}
+
+// Text returns the message in the language, formatted with the arguments.
+func (c *Catalog) Text(language string, key Key, args ...any) string {
+	return format(c.lookup(language, key)[Other], args)
+}
+
+// Plural returns the plural form of the message for the count in the language,
+// formatted with the arguments, which default to the count itself.
+func (c *Catalog) Plural(language string, key Key, count int, args ...any) string {
+	message := c.lookup(language, key)
+	form, ok := message[PluralOf(language, count)]
+	if !ok {
+		form = message[Other]
+	}
+	if len(args) < 1 {
+		args = []any{count}
+	}
+	return format(form, args)
+}
+
+// lookup finds the message in the language, then in its primary language,
+// then in the fallback language, and finally in english.
+func (c *Catalog) lookup(language string, key Key) Message {
+	language = yunyun.NormalizeLanguage(language)
+	languages := []string{language, primaryLanguage(language)}
+	if c != nil {
+		languages = append(languages, c.fallback, primaryLanguage(c.fallback))
+	}
+	for _, language := range append(languages, defaultLanguage) {
+		if c != nil {
+			if message, ok := c.overrides[language][key]; ok {
+				return message
+			}
+		}
+		if message, ok := catalogs[language][key]; ok {
+			return message
+		}
+	}
+	return text(string(key))
+}
+
+// format formats the message with the arguments, if there are any.
+func format(message string, args []any) string {
+	if len(args) < 1 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// primaryLanguage returns the primary language of the tag, so `pt-BR` gives `pt`.
+func primaryLanguage(language string) string {
+	primary, _, _ := strings.Cut(language, "-")
+	return strings.ToLower(primary)
+}
@@ -0,0 +1,59 @@
+package beatrice
+
+import (
+	"testing"
+)
+
+// TestPlural tests the plural forms in languages with different rules
+func TestPlural(t *testing.T) {
+	tests := []struct {
+		language string
+		count    int
+		expected string
+	}{
+		{"en", 1, "1 day"},
+		{"en-GB", 2, "2 days"},
+		{"ru", 1, "1 день"},
+		{"ru", 3, "3 дня"},
+		{"ru", 5, "5 дней"},
+		{"ru", 11, "11 дней"},
+		{"ru", 21, "21 день"},
+		{"ja", 3, "3日"},
+		{"de", 2, "2 days"},
+	}
+	var catalog *Catalog
+	for _, tt := range tests {
+		if got := catalog.Plural(tt.language, Days, tt.count); got != tt.expected {
+			t.Errorf("Plural(%q, %d) = %q, expected %q", tt.language, tt.count, got, tt.expected)
+		}
+	}
+}
+
+// TestOverrides tests that the messages from the config win over
+// the built-in ones and fall back to the site's language
+func TestOverrides(t *testing.T) {
+	catalog, err := NewCatalog("ja", map[string]map[string]any{
+		"en":    {"related": "See also"},
+		"pt_BR": {"days": map[string]any{"one": "%d dia", "other": "%d dias"}},
+		"fr":    {"days": map[string]any{"one": "%d jour"}, "tags": 42},
+	})
+	if err == nil {
+		t.Errorf("expected errors for the bad french messages")
+	}
+	tests := []struct {
+		got, expected string
+	}{
+		{catalog.Text("en", Related), "See also"},
+		{catalog.Text("en-US", Related), "See also"},
+		{catalog.Plural("pt-BR", Days, 0), "0 dia"},
+		{catalog.Plural("pt-BR", Days, 7), "7 dias"},
+		{catalog.Text("de", Tags), "タグ"},
+		{catalog.Text("fr", Tags), "タグ"},
+		{catalog.Text("ja", SeriesPart, 2, 5, "Go"), "Go：全5回中の第2回"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("got %q, expected %q", tt.got, tt.expected)
+		}
+	}
+}
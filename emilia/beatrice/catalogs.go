@@ -0,0 +1,101 @@
+package beatrice
+
+// defaultLanguage is the language that always has all the messages.
+const defaultLanguage = "en"
+
+// text is a message without plurals.
+func text(s string) Message { return Message{Other: s} }
+
+// catalogs are the built-in messages by their languages.
+var catalogs = map[string]map[Key]Message{
+	"en": {
+		TableOfContents:     text("table of Contents"),
+		DateSince:           text("At least %s ago"),
+		Today:               text("today"),
+		Years:               {One: "%d year", Other: "%d years"},
+		Months:              {One: "%d month", Other: "%d months"},
+		Days:                {One: "%d day", Other: "%d days"},
+		SinceTwo:            text("%s and %s"),
+		SinceThree:          text("%s, %s, and %s"),
+		ReadingTime:         text("%d min read"),
+		Words:               {One: "%s word", Other: "%s words"},
+		ContinueReading:     text("Continue reading..."),
+		ContinueReadingTime: text("%s, continue reading..."),
+		VideoFallback:       text("Sorry, your browser doesn't support embedded videos."),
+		AudioFallback:       text("music is good for the soul"),
+		ViewFootnote:        text("View footnote."),
+		PreviewAlt:          text("Preview"),
+		PaginationPrev:      text("← Previous"),
+		PaginationNext:      text("Next →"),
+		PaginationCurrent:   text("Page %d of %d"),
+		Related:             text("Related"),
+		Backlinks:           text("Pages linking here"),
+		SeriesPart:          text("Part %d of %d in %s"),
+		Tags:                text("Tags"),
+		Search:              text("Search"),
+		SearchPlaceholder:   text("Search..."),
+		SearchSection:       text("Section"),
+		SearchEverywhere:    text("Everywhere"),
+		SearchNothingFound:  text("Nothing found"),
+	},
+	"ja": {
+		TableOfContents:     text("目次"),
+		DateSince:           text("少なくとも%s前"),
+		Today:               text("今日"),
+		Years:               text("%d年"),
+		Months:              text("%dか月"),
+		Days:                text("%d日"),
+		SinceTwo:            text("%s%s"),
+		SinceThree:          text("%s%s%s"),
+		ReadingTime:         text("%d分で読めます"),
+		Words:               text("%s文字"),
+		ContinueReading:     text("続きを読む..."),
+		ContinueReadingTime: text("%s、続きを読む..."),
+		VideoFallback:       text("お使いのブラウザは動画の埋め込みに対応していません。"),
+		AudioFallback:       text("音楽は心の栄養です"),
+		ViewFootnote:        text("脚注を見る"),
+		PreviewAlt:          text("プレビュー"),
+		PaginationPrev:      text("← 前へ"),
+		PaginationNext:      text("次へ →"),
+		PaginationCurrent:   text("%d / %d ページ"),
+		Related:             text("関連ページ"),
+		Backlinks:           text("このページへのリンク"),
+		SeriesPart:          text("%[3]s：全%[2]d回中の第%[1]d回"),
+		Tags:                text("タグ"),
+		Search:              text("検索"),
+		SearchPlaceholder:   text("検索..."),
+		SearchSection:       text("セクション"),
+		SearchEverywhere:    text("すべて"),
+		SearchNothingFound:  text("見つかりませんでした"),
+	},
+	"ru": {
+		TableOfContents:     text("Содержание"),
+		DateSince:           text("Не менее %s назад"),
+		Today:               text("сегодня"),
+		Years:               {One: "%d год", Few: "%d года", Many: "%d лет", Other: "%d года"},
+		Months:              {One: "%d месяц", Few: "%d месяца", Many: "%d месяцев", Other: "%d месяца"},
+		Days:                {One: "%d день", Few: "%d дня", Many: "%d дней", Other: "%d дня"},
+		SinceTwo:            text("%s и %s"),
+		SinceThree:          text("%s, %s и %s"),
+		ReadingTime:         text("%d мин чтения"),
+		Words:               {One: "%s слово", Few: "%s слова", Many: "%s слов", Other: "%s слова"},
+		ContinueReading:     text("Читать дальше..."),
+		ContinueReadingTime: text("%s, читать дальше..."),
+		VideoFallback:       text("Ваш браузер не поддерживает встроенные видео."),
+		AudioFallback:       text("музыка полезна для души"),
+		ViewFootnote:        text("Показать сноску."),
+		PreviewAlt:          text("Превью"),
+		PaginationPrev:      text("← Назад"),
+		PaginationNext:      text("Дальше →"),
+		PaginationCurrent:   text("Страница %d из %d"),
+		Related:             text("Похожие страницы"),
+		Backlinks:           text("Ссылки на эту страницу"),
+		SeriesPart:          text("Часть %d из %d в серии «%s»"),
+		Tags:                text("Теги"),
+		Search:              text("Поиск"),
+		SearchPlaceholder:   text("Поиск..."),
+		SearchSection:       text("Раздел"),
+		SearchEverywhere:    text("Везде"),
+		SearchNothingFound:  text("Ничего не найдено"),
+	},
+}
@@ -0,0 +1,70 @@
+package beatrice
+
+// Key is the name of the message, also used in `[messages.LANGUAGE]`
+// of darkness.toml to override it.
+type Key string
+
+const (
+	// TableOfContents is the heading of the table of contents.
+	TableOfContents Key = "table_of_contents"
+
+	// DateSince is the date section, with how long ago the page was published.
+	DateSince Key = "date_since"
+	// Today is how long ago the page was published, if it was today.
+	Today Key = "today"
+	// Years is the number of years, with plurals.
+	Years Key = "years"
+	// Months is the number of months, with plurals.
+	Months Key = "months"
+	// Days is the number of days, with plurals.
+	Days Key = "days"
+	// SinceTwo joins two parts of how long ago the page was published.
+	SinceTwo Key = "since_two"
+	// SinceThree joins three parts of how long ago the page was published.
+	SinceThree Key = "since_three"
+
+	// ReadingTime is the reading time in minutes, with plurals.
+	ReadingTime Key = "reading_time"
+	// Words is the number of words, with plurals.
+	Words Key = "words"
+	// ContinueReading is the end of the rss item's description.
+	ContinueReading Key = "continue_reading"
+	// ContinueReadingTime is the end of the rss item's description with the reading time.
+	ContinueReadingTime Key = "continue_reading_time"
+
+	// VideoFallback is shown if the browser can't play the video.
+	VideoFallback Key = "video_fallback"
+	// AudioFallback is shown if the browser can't play the audio.
+	AudioFallback Key = "audio_fallback"
+	// ViewFootnote is the title of the footnote reference.
+	ViewFootnote Key = "view_footnote"
+	// PreviewAlt is the alternative text of the page's preview image.
+	PreviewAlt Key = "preview_alt"
+
+	// PaginationPrev is the link to the previous page of the listing.
+	PaginationPrev Key = "pagination_prev"
+	// PaginationNext is the link to the next page of the listing.
+	PaginationNext Key = "pagination_next"
+	// PaginationCurrent is the current page of the listing out of all pages.
+	PaginationCurrent Key = "pagination_current"
+
+	// Related is the title of the related pages.
+	Related Key = "related"
+	// Backlinks is the title of the pages linking here.
+	Backlinks Key = "backlinks"
+	// SeriesPart is the title of the series box.
+	SeriesPart Key = "series_part"
+	// Tags is the title of the tags index.
+	Tags Key = "tags"
+
+	// Search is the title of the search page.
+	Search Key = "search"
+	// SearchPlaceholder is the placeholder of the search input.
+	SearchPlaceholder Key = "search_placeholder"
+	// SearchSection is the label of the search section select.
+	SearchSection Key = "search_section"
+	// SearchEverywhere is the search section option to look everywhere.
+	SearchEverywhere Key = "search_everywhere"
+	// SearchNothingFound is shown if the search found nothing.
+	SearchNothingFound Key = "search_nothing_found"
+)
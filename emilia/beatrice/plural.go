@@ -0,0 +1,127 @@
+package beatrice
+
+// Plural is the plural category of a number, as defined by the unicode CLDR.
+type Plural string
+
+const (
+	// Zero is the plural category for zero, like in arabic.
+	Zero Plural = "zero"
+	// One is the plural category for one (and alike).
+	One Plural = "one"
+	// Two is the plural category for two, like in arabic.
+	Two Plural = "two"
+	// Few is the plural category for a few, like 2-4 in russian.
+	Few Plural = "few"
+	// Many is the plural category for many, like 5-20 in russian.
+	Many Plural = "many"
+	// Other is the plural category for everything else, every message has it.
+	Other Plural = "other"
+)
+
+// pluralRule returns the plural category of the non-negative number.
+type pluralRule func(n int) Plural
+
+var (
+	// pluralOne is the rule of languages where only the one is special, like english.
+	pluralOne pluralRule = func(n int) Plural {
+		if n == 1 {
+			return One
+		}
+		return Other
+	}
+
+	// pluralZeroOne is the rule of languages where zero is also one, like french.
+	pluralZeroOne pluralRule = func(n int) Plural {
+		if n == 0 || n == 1 {
+			return One
+		}
+		return Other
+	}
+
+	// pluralNone is the rule of languages without plurals, like japanese.
+	pluralNone pluralRule = func(int) Plural { return Other }
+
+	// pluralEastSlavic is the rule of russian and ukrainian.
+	pluralEastSlavic pluralRule = func(n int) Plural {
+		switch mod10, mod100 := n%10, n%100; {
+		case mod10 == 1 && mod100 != 11:
+			return One
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return Few
+		default:
+			return Many
+		}
+	}
+
+	// pluralPolish is the rule of polish.
+	pluralPolish pluralRule = func(n int) Plural {
+		switch mod10, mod100 := n%10, n%100; {
+		case n == 1:
+			return One
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return Few
+		default:
+			return Many
+		}
+	}
+
+	// pluralWestSlavic is the rule of czech and slovak.
+	pluralWestSlavic pluralRule = func(n int) Plural {
+		switch {
+		case n == 1:
+			return One
+		case n >= 2 && n <= 4:
+			return Few
+		default:
+			return Other
+		}
+	}
+
+	// pluralArabic is the rule of arabic.
+	pluralArabic pluralRule = func(n int) Plural {
+		switch mod100 := n % 100; {
+		case n == 0:
+			return Zero
+		case n == 1:
+			return One
+		case n == 2:
+			return Two
+		case mod100 >= 3 && mod100 <= 10:
+			return Few
+		case mod100 >= 11:
+			return Many
+		default:
+			return Other
+		}
+	}
+)
+
+// pluralRules are the plural rules by the primary language, the languages
+// not listed here follow the english rule.
+var pluralRules = map[string]pluralRule{
+	"ar": pluralArabic,
+	"cs": pluralWestSlavic,
+	"fr": pluralZeroOne,
+	"id": pluralNone,
+	"ja": pluralNone,
+	"ko": pluralNone,
+	"pl": pluralPolish,
+	"pt": pluralZeroOne,
+	"ru": pluralEastSlavic,
+	"sk": pluralWestSlavic,
+	"th": pluralNone,
+	"uk": pluralEastSlavic,
+	"vi": pluralNone,
+	"zh": pluralNone,
+}
+
+// PluralOf returns the plural category of the number in the language.
+func PluralOf(language string, n int) Plural {
+	if n < 0 {
+		n = -n
+	}
+	if rule, ok := pluralRules[primaryLanguage(language)]; ok {
+		return rule(n)
+	}
+	return pluralOne(n)
+}
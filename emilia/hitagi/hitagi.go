@@ -0,0 +1,160 @@
+package hitagi
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Class is the kind of a warning, the strict mode fails on the chosen classes.
+type Class string
+
+const (
+	// UnresolvedLinks are the internal links that lead nowhere.
+	UnresolvedLinks Class = "unresolved_links"
+	// MissingImages are the images that are not there.
+	MissingImages Class = "missing_images"
+	// UnknownOptions are the options in the pages that darkness doesn't know.
+	UnknownOptions Class = "unknown_options"
+	// MissingFonts are the fonts for the generated previews that couldn't be found.
+	MissingFonts Class = "missing_fonts"
+
+	// failure is the class of the problems that always fail the build.
+	failure Class = "failure"
+)
+
+// Classes are all the warning classes.
+var Classes = []Class{UnresolvedLinks, MissingImages, UnknownOptions, MissingFonts}
+
+// IsClass returns true if the class is one of the warning classes.
+func IsClass(class Class) bool {
+	for _, known := range Classes {
+		if class == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Problem is something that went wrong in a file.
+type Problem struct {
+	// Class is the warning class, or failure for the failures.
+	Class Class
+	// File is where it went wrong, empty if it's about the whole site.
+	File string
+	// Message is what went wrong.
+	Message string
+	// Fatal is true if the problem fails the build.
+	Fatal bool
+}
+
+// Report collects the problems of a build, it's safe to use from many
+// goroutines and a nil report ignores everything.
+type Report struct {
+	mutex sync.Mutex
+	// fatal are the warning classes that fail the build.
+	fatal map[Class]bool
+	// problems are the problems by their class, file, and message, so the
+	// same problem found twice is only reported once.
+	problems map[Problem]struct{}
+}
+
+// NewReport returns an empty report, where the warnings of the fatal classes
+// fail the build, just like the failures do.
+func NewReport(fatal []Class) *Report {
+	r := &Report{
+		fatal:    make(map[Class]bool, len(fatal)),
+		problems: make(map[Problem]struct{}),
+	}
+	for _, class := range fatal {
+		r.fatal[class] = true
+	}
+	return r
+}
+
+// Fail records that the file failed, like it couldn't be read or written.
+func (r *Report) Fail(file string, err error) {
+	r.add(Problem{Class: failure, File: file, Message: err.Error(), Fatal: true})
+}
+
+// Warn records the warning of the class about the file.
+func (r *Report) Warn(class Class, file string, format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.add(Problem{Class: class, File: file, Message: fmt.Sprintf(format, args...), Fatal: r.fatal[class]})
+}
+
+// add records the problem.
+func (r *Report) add(problem Problem) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.problems[problem] = struct{}{}
+}
+
+// Problems returns the problems sorted by their files, classes, and messages.
+func (r *Report) Problems() []*Problem {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	problems := make([]*Problem, 0, len(r.problems))
+	for problem := range r.problems {
+		problems = append(problems, &problem)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].File != problems[j].File {
+			return problems[i].File < problems[j].File
+		}
+		if problems[i].Class != problems[j].Class {
+			return problems[i].Class < problems[j].Class
+		}
+		return problems[i].Message < problems[j].Message
+	})
+	return problems
+}
+
+// Failed returns true if any of the problems fail the build.
+func (r *Report) Failed() bool {
+	for _, problem := range r.Problems() {
+		if problem.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes the problems grouped by their files, with the ones that
+// fail the build marked, and the totals at the end.
+func (r *Report) Write(w io.Writer) {
+	problems := r.Problems()
+	if len(problems) < 1 {
+		return
+	}
+	failures, warnings := 0, 0
+	lastFile := "\x00"
+	for _, problem := range problems {
+		if problem.File != lastFile {
+			if problem.File == "" {
+				fmt.Fprintln(w, "(site)")
+			} else {
+				fmt.Fprintln(w, problem.File)
+			}
+			lastFile = problem.File
+		}
+		mark := " "
+		if problem.Fatal {
+			mark = "✗"
+			failures++
+		} else {
+			warnings++
+		}
+		fmt.Fprintf(w, " %s [%s] %s\n", mark, problem.Class, problem.Message)
+	}
+	fmt.Fprintf(w, "Problems: %d failing the build, %d warnings\n", failures, warnings)
+}
@@ -0,0 +1,42 @@
+package hitagi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestReport tests that only the failures and the fatal warnings fail the build
+func TestReport(t *testing.T) {
+	var none *Report
+	none.Warn(UnresolvedLinks, "a.org", "no such page")
+	none.Fail("a.org", errors.New("oops"))
+	if none.Failed() || len(none.Problems()) > 0 {
+		t.Errorf("nil report should ignore everything")
+	}
+
+	report := NewReport([]Class{MissingImages})
+	report.Warn(UnresolvedLinks, "b.org", "link %s leads nowhere", "c")
+	report.Warn(UnresolvedLinks, "b.org", "link %s leads nowhere", "c")
+	if report.Failed() {
+		t.Errorf("unresolved links should only warn")
+	}
+	if got := len(report.Problems()); got != 1 {
+		t.Errorf("got %d problems, expected the duplicate to be dropped", got)
+	}
+	report.Warn(MissingImages, "a.org", "no cat.png")
+	if !report.Failed() {
+		t.Errorf("missing images should fail the build")
+	}
+
+	report = NewReport(nil)
+	report.Fail("a.org", errors.New("permission denied"))
+	if !report.Failed() {
+		t.Errorf("failures should always fail the build")
+	}
+	var out strings.Builder
+	report.Write(&out)
+	if expected := "a.org\n ✗ [failure] permission denied\nProblems: 1 failing the build, 0 warnings\n"; out.String() != expected {
+		t.Errorf("got %q, expected %q", out.String(), expected)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
 )
 
 const (
@@ -18,24 +17,29 @@ const (
 )
 
 // GetLfsMediaPath returns the path to the LFS media file.
-func GetLfsMediaPath(conf *alpha.DarknessConfig, path string) string {
-	if !kuroko.LfsEnabled {
-		conf.Runtime.Logger.Fatal("LFS images need to be enabled with --lfs flag")
+func GetLfsMediaPath(conf *alpha.DarknessConfig, path string) (string, error) {
+	if !conf.Runtime.Lfs {
+		return "", fmt.Errorf("LFS images need to be enabled with --lfs flag")
 	}
 	if conf.External.GitRemoteService == githubLink {
 		return fmt.Sprintf(githubLfsMediaPath,
 			strings.Trim(conf.External.GitRemotePath, "/"),
-			conf.External.GitBranch, path)
+			conf.External.GitBranch, path), nil
 	}
-	conf.Runtime.Logger.Fatalf("unsupported service for linking LFS: %s", conf.External.GitRemoteService)
-	return "" // unreachable
+	return "", fmt.Errorf("unsupported service for linking LFS: %s", conf.External.GitRemoteService)
 }
 
-// ConvertImageToLfsMediaLink takes a link and returns full remote path if it starts with lfs:
+// ConvertImageToLfsMediaLink takes a link and returns full remote path if it starts with lfs:,
+// failing the build and leaving the link as is if it can't be linked.
 func ConvertImageToLfsMediaLink(conf *alpha.DarknessConfig, link string) string {
 	if !strings.HasPrefix(link, lfsLinkPrefix) {
 		return link
 	}
 	cleanLink := strings.TrimPrefix(link, lfsLinkPrefix)
-	return GetLfsMediaPath(conf, strings.TrimLeft(cleanLink, "/"))
+	lfsLink, err := GetLfsMediaPath(conf, strings.TrimLeft(cleanLink, "/"))
+	if err != nil {
+		conf.Runtime.Problems.Fail("", fmt.Errorf("linking %s: %w", link, err))
+		return link
+	}
+	return lfsLink
 }
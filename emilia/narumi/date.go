@@ -1,16 +1,21 @@
 package narumi
 
 import (
-	"crypto/rand"
-	"encoding/binary"
 	"fmt"
+	"hash/fnv"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
+const (
+	// dateSectionId is the html id of the date paragraph.
+	dateSectionId = "date-section"
+)
+
 var (
 	// Some emojis are compound, like lime, so they don't fit in a single rune.
 	randomDateEmojis = []string{
@@ -20,40 +25,19 @@ var (
 	}
 )
 
-// secureRandIntn returns a cryptographically secure random integer in the range [0, n).
-// It panics if there's an error reading from the random source.
-func secureRandIntn(n int) int {
-	if n <= 0 {
-		panic("invalid argument to secureRandIntn")
-	}
-
-	// For small values of n, we can simply use a single byte
-	if n <= 256 {
-		buf := make([]byte, 1)
-		_, err := rand.Read(buf)
-		if err != nil {
-			panic(fmt.Sprintf("failed to generate random number: %v", err))
-		}
-		return int(buf[0]) % n
-	}
-
-	// For larger values, use 4 bytes
-	buf := make([]byte, 4)
-	_, err := rand.Read(buf)
-	if err != nil {
-		panic(fmt.Sprintf("failed to generate random number: %v", err))
-	}
-
-	// Use a more explicit approach to avoid the integer overflow
-	// Converting n to uint32 first to safely perform modulo
-	randomInt := binary.BigEndian.Uint32(buf)
-	// #nosec G115 - This is safe because n is positive and already verified to be > 0
-	result := randomInt % uint32(n)
-	return int(result) // Safe conversion from uint32 to int since result < n
+// dateEmoji picks the page's date emoji, it used to be random on every build,
+// which rewrote every dated page. Now it only looks random across the pages,
+// as it's picked by the page's file, so it stays the same between builds.
+func dateEmoji(page *yunyun.Page) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(page.File))
+	return randomDateEmojis[h.Sum32()%uint32(len(randomDateEmojis))]
 }
 
-// WithDate is a PageOption that adds the date to the page.
-func WithDate() yunyun.PageOption {
+// WithDate is a PageOption that adds the date to the page, with how
+// long ago it was in the page's language. That's counted in days, so
+// the dated pages are written again by the first build of every day.
+func WithDate(conf *alpha.DarknessConfig) yunyun.PageOption {
 	return func(page *yunyun.Page) {
 		if page == nil || page.Contents == nil || page.Accoutrement == nil {
 			return
@@ -77,13 +61,13 @@ func WithDate() yunyun.PageOption {
 		regular, isHoloscene := ConvertHoloscene(page.Date)
 		dateString := strings.TrimSpace(page.Date)
 		if isHoloscene {
-			dateString = fmt.Sprintf(`%s At least %s ago`,
-				randomDateEmojis[secureRandIntn(len(randomDateEmojis))],
-				formatSince(time.Since(regular)))
+			dateString = dateEmoji(page) + " " +
+				conf.Runtime.Messages.Text(page.Language, beatrice.DateSince,
+					formatSince(conf.Runtime.Messages, page.Language, time.Since(regular)))
 		}
 		dateContents[0] = &yunyun.Content{
-			CustomHtmlTags: fmt.Sprintf(`id="date-section" title="%s"`,
-				strings.TrimSpace(regular.Format(RfcEmily))),
+			CustomHtmlTags: fmt.Sprintf(`id="%s" title="%s"`,
+				dateSectionId, strings.TrimSpace(regular.Format(RfcEmily))),
 			Paragraph: dateString,
 			Type:      yunyun.TypeParagraph,
 			Options:   yunyun.NotADescriptionFlag,
@@ -92,53 +76,34 @@ func WithDate() yunyun.PageOption {
 	}
 }
 
-var strBuilderPool = sync.Pool{
-	New: func() any {
-		return new(strings.Builder)
-	},
-}
-
-// formatSince formats a time.Duration into a human-readable string.
-func formatSince(since time.Duration) string {
+// formatSince formats a time.Duration into a human-readable string
+// in the language, like "1 year, 2 months, and 3 days".
+func formatSince(messages *beatrice.Catalog, language string, since time.Duration) string {
 	months, days := sinceToMonthsAndDays(since)
 	years := months / 12
 	months = months % 12
 	if years == 0 && months == 0 && days == 0 {
-		return "today"
+		return messages.Text(language, beatrice.Today)
 	}
-	sb := strBuilderPool.Get().(*strings.Builder)
-	sb.Reset()
-	defer strBuilderPool.Put(sb)
-
+	parts := make([]any, 0, 3)
 	if years > 0 {
-		fmt.Fprintf(sb, "%d year", years)
-		if years > 1 {
-			sb.WriteString("s")
-		}
+		parts = append(parts, messages.Plural(language, beatrice.Years, int(years)))
 	}
 	if months > 0 {
-		if years > 0 {
-			sb.WriteString(", ")
-		}
-		fmt.Fprintf(sb, "%d month", months)
-		if months > 1 {
-			sb.WriteString("s")
-		}
+		parts = append(parts, messages.Plural(language, beatrice.Months, int(months)))
 	}
 	if days > 0 {
-		// We will use the Oxford comma if we have both years and months.
-		if years > 0 && months > 0 {
-			sb.WriteString(", and ")
-		} else if years > 0 || months > 0 {
-			// But, if we only have one of them, we will use "and".
-			sb.WriteString(" and ")
-		}
-		fmt.Fprintf(sb, "%d day", days)
-		if days > 1 {
-			sb.WriteString("s")
-		}
+		parts = append(parts, messages.Plural(language, beatrice.Days, int(days)))
+	}
+	switch len(parts) {
+	case 1:
+		return parts[0].(string)
+	case 2:
+		return messages.Text(language, beatrice.SinceTwo, parts...)
+	default:
+		// We will use the Oxford comma if we have all three, where the language has it.
+		return messages.Text(language, beatrice.SinceThree, parts...)
 	}
-	return sb.String()
 }
 
 // sinceToMonthsAndDays converts a time.Duration to months and days.
@@ -0,0 +1,133 @@
+package narumi
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// linkContextLength is the number of characters of the paragraph kept
+// as the context of a link.
+const linkContextLength = 280
+
+// InternalLink returns the link with the website's url trimmed off and
+// true if the link points to the website itself, false otherwise.
+func InternalLink(conf *alpha.DarknessConfig, target string) (string, bool) {
+	if len(conf.Url) > 0 && strings.HasPrefix(target, conf.Url) {
+		return "/" + strings.TrimPrefix(target, conf.Url), true
+	}
+	parsed, err := url.Parse(target)
+	return target, err != nil || len(parsed.Scheme) < 1
+}
+
+// ResolveLink returns the path relative to the website's root that the
+// internal link on the page at the location points to, and its fragment.
+// Relative links are resolved against the page's directory and false is
+// returned if the link points above the root.
+func ResolveLink(location yunyun.RelativePathDir, target string) (string, string, bool) {
+	target, fragment, _ := strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	target, _, _ = strings.Cut(target, "?")
+	resolved := string(location)
+	switch {
+	case len(target) < 1:
+		// Only the fragment, so it's the same page.
+	case strings.HasPrefix(target, "/"):
+		resolved = path.Clean(strings.TrimPrefix(target, "/"))
+	default:
+		resolved = path.Join(resolved, target)
+	}
+	if resolved == "" {
+		resolved = "."
+	}
+	// Anything above the root is not ours.
+	if resolved == ".." || strings.HasPrefix(resolved, "../") {
+		return "", "", false
+	}
+	return resolved, fragment, true
+}
+
+// LinkLocation returns the location of the page that the resolved link
+// points to, as links to the index files are links to their directories.
+func LinkLocation(conf *alpha.DarknessConfig, resolved string) yunyun.RelativePathDir {
+	indexFile := "index" + conf.Project.Output
+	if resolved == indexFile {
+		return "."
+	}
+	return yunyun.RelativePathDir(strings.TrimSuffix(resolved, "/"+indexFile))
+}
+
+// OutgoingLinks returns the internal links of the page with the text they
+// were found in, every target is only listed once.
+func OutgoingLinks(conf *alpha.DarknessConfig, page *yunyun.Page) []yunyun.SiteLink {
+	links := make([]yunyun.SiteLink, 0, 8)
+	seen := make(map[yunyun.RelativePathDir]bool)
+	addLink := func(target, context string) {
+		internal, ok := InternalLink(conf, strings.TrimSpace(target))
+		if !ok {
+			return
+		}
+		resolved, _, ok := ResolveLink(page.Location, internal)
+		if !ok {
+			return
+		}
+		location := LinkLocation(conf, resolved)
+		if seen[location] {
+			return
+		}
+		seen[location] = true
+		links = append(links, yunyun.SiteLink{Target: location, Context: linkContext(context)})
+	}
+	addInlineLinks := func(text string) {
+		for _, link := range yunyun.ExtractLinks(text) {
+			addLink(link.Link, text)
+		}
+	}
+	for _, content := range page.Contents {
+		switch {
+		case content.IsParagraph():
+			addInlineLinks(content.Paragraph)
+		case content.IsGallery():
+			continue
+		case content.IsList(), content.IsListNumbered():
+			for _, item := range content.List {
+				addInlineLinks(item.Text)
+			}
+		case content.IsLink():
+			addLink(content.Link, content.LinkTitle)
+		}
+	}
+	return links
+}
+
+// WithBacklinks is a PageOption that lists the pages linking to this one,
+// if the page is in one of the configured directories or forced on the page.
+func WithBacklinks(conf *alpha.DarknessConfig, site *yunyun.Site) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || page.Accoutrement == nil || conf == nil || site == nil {
+			return
+		}
+		if page.Accoutrement.Backlinks.IsDisabled() {
+			return
+		}
+		if page.Accoutrement.Backlinks.IsDefault() && !conf.Backlinks.Shows(page.Location) {
+			return
+		}
+		page.Backlinks = site.Backlinks(page.Location)
+	}
+}
+
+// linkContext returns the plain text of the link's paragraph, cut short
+// if it's too long.
+func linkContext(text string) string {
+	context := []rune(yunyun.RemoveFormatting(text))
+	if len(context) <= linkContextLength {
+		return string(context)
+	}
+	return strings.TrimSpace(string(context[:linkContextLength])) + "…"
+}
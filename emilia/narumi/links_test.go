@@ -0,0 +1,69 @@
+package narumi
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestResolveLink resolves the links relative to their pages, with the
+// fragments split off and the links escaping the site rejected
+func TestResolveLink(t *testing.T) {
+	tests := []struct {
+		location     yunyun.RelativePathDir
+		target       string
+		wantPath     string
+		wantFragment string
+		wantOk       bool
+	}{
+		{"notes/a", "../b", "notes/b", "", true},
+		{"notes/a", "cover.png", "notes/a/cover.png", "", true},
+		{"notes/a", "/tags#go", "tags", "go", true},
+		{"notes/a", "#top", "notes/a", "top", true},
+		{".", "/", ".", "", true},
+		{".", "my%20file.pdf?v=2", "my file.pdf", "", true},
+		{"notes", "../../etc", "", "", false},
+	}
+	for _, tt := range tests {
+		path, fragment, ok := ResolveLink(tt.location, tt.target)
+		if path != tt.wantPath || fragment != tt.wantFragment || ok != tt.wantOk {
+			t.Errorf("ResolveLink(%q, %q) = %q, %q, %v, want %q, %q, %v",
+				tt.location, tt.target, path, fragment, ok, tt.wantPath, tt.wantFragment, tt.wantOk)
+		}
+	}
+}
+
+// TestBacklinks tests that the pages linking to a page are found with the text around their links
+func TestBacklinks(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	conf := &alpha.DarknessConfig{Url: "https://example.com/"}
+	conf.Project.Output = ".html"
+	page := func(location yunyun.RelativePathDir, text string) *yunyun.SitePage {
+		p := yunyun.NewPage(
+			yunyun.WithLocation(location),
+			yunyun.WithContents(yunyun.Contents{{Type: yunyun.TypeParagraph, Paragraph: text}}),
+		)
+		return &yunyun.SitePage{Location: location, Title: string(location), Links: OutgoingLinks(conf, p)}
+	}
+	site := yunyun.NewSite([]*yunyun.SitePage{
+		page("notes/b", "Back to [[../a][a]] and [[/notes/a/index.html][again]]."),
+		page("notes/a", "Myself [[#top][here]] and [[mailto:me@example.com][mail]]."),
+		page("blog", "Read [[https://example.com/notes/a][the note]] and [[https://elsewhere.com][this]]."),
+		page("notes/c", "Nowhere [[/missing][gone]]."),
+	})
+
+	backlinks := site.Backlinks("notes/a")
+	if len(backlinks) != 2 {
+		t.Fatalf("got %d backlinks, want 2", len(backlinks))
+	}
+	if backlinks[0].Page.Location != "blog" || backlinks[0].Context != "Read the note and this." {
+		t.Errorf("first backlink = %s %q", backlinks[0].Page.Location, backlinks[0].Context)
+	}
+	if backlinks[1].Page.Location != "notes/b" || backlinks[1].Context != "Back to a and again." {
+		t.Errorf("second backlink = %s %q", backlinks[1].Page.Location, backlinks[1].Context)
+	}
+	if len(site.Backlinks("missing")) != 0 {
+		t.Error("links to missing pages shouldn't be backlinks")
+	}
+}
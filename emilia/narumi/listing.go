@@ -0,0 +1,113 @@
+package narumi
+
+import (
+	"sort"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// WithListings is a PageOption that fills the page's listings and tag
+// clouds with the pages of the site, drafts are never listed.
+func WithListings(site *yunyun.Site) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || page.Contents == nil || site == nil {
+			return
+		}
+		for _, content := range page.Contents {
+			switch {
+			case content.IsListing() && content.Listing != nil:
+				content.Listing.Items = ListPages(site, content.Listing)
+			case content.IsTagCloud():
+				content.TagCloud = SiteTags(site)
+			}
+		}
+	}
+}
+
+// ListPages returns the pages of the site that the listing asks for, sorted,
+// limited, and cut down to the listing's page if it has a page size.
+func ListPages(site *yunyun.Site, listing *yunyun.Listing) []*yunyun.SitePage {
+	pages := make([]*yunyun.SitePage, 0, 8)
+	for _, page := range site.Children(listing.Dir, listing.Recursive) {
+		if page.Draft {
+			continue
+		}
+		if listing.DatedOnly && !page.HasDate() {
+			continue
+		}
+		if len(listing.Tag) > 0 && !page.HasTag(listing.Tag) {
+			continue
+		}
+		pages = append(pages, page)
+	}
+	SortPages(pages, listing.Sort)
+	if listing.Limit > 0 && len(pages) > listing.Limit {
+		pages = pages[:listing.Limit]
+	}
+	if listing.PageSize > 0 {
+		start := min(max(listing.Page-1, 0)*listing.PageSize, len(pages))
+		pages = pages[start:min(start+listing.PageSize, len(pages))]
+	}
+	return pages
+}
+
+// CountListingPages returns the number of pages the listing is split
+// across, which is always at least one.
+func CountListingPages(site *yunyun.Site, listing *yunyun.Listing) int {
+	if listing.PageSize < 1 {
+		return 1
+	}
+	whole := *listing
+	whole.PageSize = 0
+	return max(1, (len(ListPages(site, &whole))+listing.PageSize-1)/listing.PageSize)
+}
+
+// SortPages sorts the pages in the given order, ties are broken by location,
+// so the order never changes between builds.
+func SortPages(pages []*yunyun.SitePage, order yunyun.ListingSort) {
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if order == yunyun.ListingSortTitle {
+			if a.Title != b.Title {
+				return a.Title < b.Title
+			}
+			return a.Location < b.Location
+		}
+		// Pages without dates go to the very end.
+		if a.HasDate() != b.HasDate() {
+			return a.HasDate()
+		}
+		if !a.Published.Equal(b.Published) {
+			return a.Published.After(b.Published)
+		}
+		return a.Location < b.Location
+	})
+}
+
+// TagListing returns the listing of all the pages with the tag, which
+// skips drafts and pages without dates the same way the feeds do.
+func TagListing(slug string) *yunyun.Listing {
+	return &yunyun.Listing{
+		Dir:       ".",
+		Sort:      yunyun.ListingSortDate,
+		Recursive: true,
+		Tag:       slug,
+		DatedOnly: true,
+	}
+}
+
+// SiteTags returns all the tags of the site sorted by their slugs, with
+// the number of pages that the tag's listing would have.
+func SiteTags(site *yunyun.Site) []*yunyun.SiteTag {
+	names := site.TagNames()
+	tags := make([]*yunyun.SiteTag, 0, len(names))
+	for slug, name := range names {
+		count := len(ListPages(site, TagListing(slug)))
+		if count < 1 {
+			continue
+		}
+		tags = append(tags, &yunyun.SiteTag{Name: name, Slug: slug, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Slug < tags[j].Slug })
+	return tags
+}
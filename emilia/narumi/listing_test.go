@@ -0,0 +1,104 @@
+package narumi
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestListPages tests that the listings pick, filter, and sort the pages of their directories
+func TestListPages(t *testing.T) {
+	date := func(day int) time.Time { return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC) }
+	site := yunyun.NewSite([]*yunyun.SitePage{
+		{Location: "blog", Title: "Blog"},
+		{Location: "blog/b", Title: "B", Published: date(2), Tags: []string{"Go"}},
+		{Location: "blog/a", Title: "A", Published: date(1), Tags: []string{"go", "web"}},
+		{Location: "blog/c", Title: "C", Published: date(2)},
+		{Location: "blog/undated", Title: "Undated", Tags: []string{"go"}},
+		{Location: "blog/draft", Title: "Draft", Published: date(9), Draft: true, Tags: []string{"go", "secret"}},
+		{Location: "blog/2024/nested", Title: "Nested", Published: date(5)},
+		{Location: "blogroll", Title: "Blogroll", Published: date(7)},
+	})
+	locations := func(pages []*yunyun.SitePage) []yunyun.RelativePathDir {
+		result := make([]yunyun.RelativePathDir, len(pages))
+		for i, page := range pages {
+			result[i] = page.Location
+		}
+		return result
+	}
+	tests := []struct {
+		name    string
+		listing *yunyun.Listing
+		want    []yunyun.RelativePathDir
+	}{
+		{"By date", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortDate},
+			[]yunyun.RelativePathDir{"blog/b", "blog/c", "blog/a", "blog/undated"}},
+		{"By title", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortTitle},
+			[]yunyun.RelativePathDir{"blog/a", "blog/b", "blog/c", "blog/undated"}},
+		{"Limited", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortDate, Limit: 2},
+			[]yunyun.RelativePathDir{"blog/b", "blog/c"}},
+		{"Recursive", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortDate, Limit: 1, Recursive: true},
+			[]yunyun.RelativePathDir{"blog/2024/nested"}},
+		{"Root", &yunyun.Listing{Dir: ".", Sort: yunyun.ListingSortTitle},
+			[]yunyun.RelativePathDir{"blog", "blogroll"}},
+		{"Second page", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortDate, PageSize: 3, Page: 2},
+			[]yunyun.RelativePathDir{"blog/undated"}},
+		{"Page past the end", &yunyun.Listing{Dir: "blog", Sort: yunyun.ListingSortDate, PageSize: 3, Page: 5},
+			[]yunyun.RelativePathDir{}},
+		{"Tagged", TagListing("go"),
+			[]yunyun.RelativePathDir{"blog/b", "blog/a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := locations(ListPages(site, tt.listing)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListPages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCountListingPages tests how many pages a listing is split into with its page size and limit
+func TestCountListingPages(t *testing.T) {
+	pages := make([]*yunyun.SitePage, 7)
+	for i := range pages {
+		pages[i] = &yunyun.SitePage{Location: yunyun.RelativePathDir(fmt.Sprintf("blog/%d", i))}
+	}
+	site := yunyun.NewSite(pages)
+	tests := []struct {
+		pageSize, limit, want int
+	}{
+		{0, 0, 1},
+		{3, 0, 3},
+		{7, 0, 1},
+		{3, 6, 2},
+		{10, 0, 1},
+	}
+	for _, tt := range tests {
+		listing := &yunyun.Listing{Dir: "blog", PageSize: tt.pageSize, Limit: tt.limit}
+		if got := CountListingPages(site, listing); got != tt.want {
+			t.Errorf("CountListingPages(%d, %d) = %d, want %d", tt.pageSize, tt.limit, got, tt.want)
+		}
+	}
+}
+
+// TestSiteTags tests that the tags of the dated pages are counted by their slugs
+func TestSiteTags(t *testing.T) {
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	site := yunyun.NewSite([]*yunyun.SitePage{
+		{Location: "b", Published: date, Tags: []string{"go", "Machine Learning"}},
+		{Location: "a", Published: date, Tags: []string{"Go"}},
+		{Location: "c", Tags: []string{"undated"}},
+		{Location: "d", Published: date, Draft: true, Tags: []string{"draft"}},
+	})
+	got := make([]string, 0, 2)
+	for _, tag := range SiteTags(site) {
+		got = append(got, fmt.Sprintf("%s/%s/%d", tag.Slug, tag.Name, tag.Count))
+	}
+	want := []string{"go/Go/2", "machine-learning/Machine Learning/1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SiteTags() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,130 @@
+package narumi
+
+import (
+	"math"
+	"sort"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// minTermLength is the shortest non-CJK word that counts as a term.
+	minTermLength = 3
+
+	// sharedTagBonus is added to the similarity for every shared tag,
+	// as tags are picked by hand, they tell more than words do.
+	sharedTagBonus = 0.25
+)
+
+// stopWords are the common english words that say nothing about the page.
+var stopWords = map[string]struct{}{
+	"the": {}, "and": {}, "for": {}, "are": {}, "but": {}, "not": {}, "you": {},
+	"all": {}, "any": {}, "can": {}, "had": {}, "her": {}, "was": {}, "one": {},
+	"our": {}, "out": {}, "has": {}, "his": {}, "how": {}, "its": {}, "may": {},
+	"new": {}, "now": {}, "see": {}, "who": {}, "did": {}, "get": {}, "him": {},
+	"she": {}, "too": {}, "use": {}, "that": {}, "with": {}, "have": {}, "this": {},
+	"will": {}, "your": {}, "from": {}, "they": {}, "been": {}, "were": {}, "what": {},
+	"when": {}, "which": {}, "their": {}, "there": {}, "would": {}, "about": {},
+	"into": {}, "than": {}, "them": {}, "then": {}, "these": {}, "some": {},
+	"just": {}, "like": {}, "also": {}, "only": {}, "very": {}, "more": {},
+	"because": {}, "could": {}, "should": {}, "where": {}, "while": {}, "here": {},
+}
+
+// TermFrequencies returns the frequencies of the meaningful terms of the page.
+func TermFrequencies(page *yunyun.Page) yunyun.Terms {
+	counts := make(map[string]int)
+	total := 0
+	for _, word := range Words(PlainText(page)) {
+		if !IsTerm(word) {
+			continue
+		}
+		counts[word]++
+		total++
+	}
+	terms := make(yunyun.Terms, 0, len(counts))
+	for term, count := range counts {
+		terms = append(terms, yunyun.TermWeight{Term: term, Weight: float64(count) / float64(total)})
+	}
+	sort.Slice(terms, func(i, j int) bool { return terms[i].Term < terms[j].Term })
+	return terms
+}
+
+// WeighTerms turns the term frequencies of all the pages into normalized
+// tf-idf vectors, so that terms used everywhere matter less.
+func WeighTerms(site *yunyun.Site) {
+	documentFrequencies := make(map[string]int)
+	for _, page := range site.Pages {
+		for _, term := range page.Terms {
+			documentFrequencies[term.Term]++
+		}
+	}
+	total := float64(len(site.Pages))
+	for _, page := range site.Pages {
+		for i := range page.Terms {
+			page.Terms[i].Weight *= math.Log(1 + total/float64(documentFrequencies[page.Terms[i].Term]))
+		}
+		page.Terms.Normalize()
+	}
+}
+
+// RelatedPages returns up to `count` pages most similar to the page at the
+// location, by the similarity of their texts and their shared tags. Only
+// dated pages that are not drafts can be related, same as in the feeds.
+func RelatedPages(site *yunyun.Site, location yunyun.RelativePathDir, count int) []*yunyun.SitePage {
+	page := site.Page(location)
+	if page == nil || count < 1 {
+		return nil
+	}
+	type scored struct {
+		page  *yunyun.SitePage
+		score float64
+	}
+	candidates := make([]scored, 0, 16)
+	for _, candidate := range site.Pages {
+		if candidate == page || candidate.Draft || !candidate.HasDate() {
+			continue
+		}
+		score := page.Terms.Dot(candidate.Terms)
+		for _, tag := range page.Tags {
+			if candidate.HasTag(yunyun.TagSlug(tag)) {
+				score += sharedTagBonus
+			}
+		}
+		if score <= 0 {
+			continue
+		}
+		candidates = append(candidates, scored{candidate, score})
+	}
+	// Ties are broken by location, so the order never changes between builds.
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].page.Location < candidates[j].page.Location
+	})
+	related := make([]*yunyun.SitePage, 0, count)
+	for _, candidate := range candidates[:min(count, len(candidates))] {
+		related = append(related, candidate.page)
+	}
+	return related
+}
+
+// WithRelatedPages is a PageOption that finds the related pages, if enabled
+// in the given settings or forced on the page. Only dated pages get related pages.
+func WithRelatedPages(site *yunyun.Site, related alpha.RelatedConfig) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || page.Accoutrement == nil || site == nil {
+			return
+		}
+		if page.Accoutrement.Related.IsDisabled() {
+			return
+		}
+		if page.Accoutrement.Related.IsDefault() {
+			if _, isDated := ConvertHoloscene(page.Date); !related.Enable || !isDated {
+				return
+			}
+		}
+		page.Related = RelatedPages(site, page.Location, related.Count)
+	}
+}
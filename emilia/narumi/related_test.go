@@ -0,0 +1,69 @@
+package narumi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestWords tests that the text is split into lowercase words, with every CJK character its own word
+func TestWords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"Hello, World!", []string{"hello", "world"}},
+		{"don't stop", []string{"dont", "stop"}},
+		{"日本語 text", []string{"日", "本", "語", "text"}},
+		{"  ", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Words(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Words(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestRelatedPages tests that the related pages are ordered by their similarity and shared tags
+func TestRelatedPages(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	page := func(location yunyun.RelativePathDir, text string, tags ...string) *yunyun.Page {
+		return yunyun.NewPage(
+			yunyun.WithLocation(location),
+			yunyun.WithContents(yunyun.Contents{{Type: yunyun.TypeParagraph, Paragraph: text}}),
+		).Options(func(p *yunyun.Page) { p.Tags = tags })
+	}
+	pages := []*yunyun.Page{
+		page("go", "goroutines channels concurrency servers", "go"),
+		page("go-again", "goroutines channels generics", "go"),
+		page("rust", "ownership borrowing lifetimes servers"),
+		page("cooking", "pasta tomatoes basil"),
+		page("tagged", "nothing in common at all", "go"),
+	}
+	summaries := make([]*yunyun.SitePage, len(pages))
+	for i, p := range pages {
+		summaries[i] = &yunyun.SitePage{
+			Location:  p.Location,
+			Published: date,
+			Tags:      p.Tags,
+			Terms:     TermFrequencies(p),
+		}
+	}
+	site := yunyun.NewSite(summaries)
+	WeighTerms(site)
+
+	got := make([]yunyun.RelativePathDir, 0, 3)
+	for _, related := range RelatedPages(site, "go", 5) {
+		got = append(got, related.Location)
+	}
+	want := []yunyun.RelativePathDir{"go-again", "tagged", "rust"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RelatedPages() = %v, want %v", got, want)
+	}
+	if limited := RelatedPages(site, "go", 1); len(limited) != 1 {
+		t.Errorf("RelatedPages() with count 1 returned %d pages", len(limited))
+	}
+}
@@ -0,0 +1,99 @@
+package narumi
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// SeriesParts returns all the parts of the named series, the parts with
+// explicit order go first, then the rest by date.
+func SeriesParts(site *yunyun.Site, name string) []*yunyun.SitePage {
+	parts := make([]*yunyun.SitePage, 0, 8)
+	for _, page := range site.Pages {
+		if !page.Draft && sameSeries(page.SeriesName, name) {
+			parts = append(parts, page)
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool {
+		a, b := parts[i], parts[j]
+		if (a.SeriesOrder > 0) != (b.SeriesOrder > 0) {
+			return a.SeriesOrder > 0
+		}
+		if a.SeriesOrder != b.SeriesOrder {
+			return a.SeriesOrder < b.SeriesOrder
+		}
+		return isEarlier(a, b)
+	})
+	return parts
+}
+
+// DirectorySeries returns the dated pages right inside of the directory
+// in the order they were published.
+func DirectorySeries(site *yunyun.Site, dir yunyun.RelativePathDir) []*yunyun.SitePage {
+	parts := make([]*yunyun.SitePage, 0, 16)
+	for _, page := range site.Children(dir, false) {
+		if !page.Draft && page.HasDate() {
+			parts = append(parts, page)
+		}
+	}
+	sort.SliceStable(parts, func(i, j int) bool { return isEarlier(parts[i], parts[j]) })
+	return parts
+}
+
+// WithSeries is a PageOption that finds the series of the page, either the
+// one declared on the page or all the dated pages of its directory, if the
+// directory is configured to have them.
+func WithSeries(conf *alpha.DarknessConfig, site *yunyun.Site) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || conf == nil || site == nil {
+			return
+		}
+		series := &yunyun.Series{Name: page.SeriesName}
+		switch {
+		case len(page.SeriesName) > 0:
+			series.Parts = SeriesParts(site, page.SeriesName)
+		case conf.Series.Shows(page.Location):
+			series.Parts = DirectorySeries(site, yunyun.RelativePathDir(filepath.Dir(string(page.Location))))
+		default:
+			return
+		}
+		// Drafts and undated pages are not part of their directories' series.
+		series.Current = -1
+		for i, part := range series.Parts {
+			if part.Location == page.Location {
+				series.Current = i
+				break
+			}
+		}
+		if series.Current < 0 || len(series.Parts) < 2 {
+			return
+		}
+		// Pages may spell the name differently, the first part decides.
+		if series.IsNamed() {
+			series.Name = strings.Join(strings.Fields(series.Parts[0].SeriesName), " ")
+		}
+		page.Series = series
+	}
+}
+
+// sameSeries returns true if both names are the same series, ignoring
+// the case and the extra spaces.
+func sameSeries(a, b string) bool {
+	return len(a) > 0 && strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
+
+// isEarlier returns true if the page was published before the other,
+// undated pages go last and the ties are broken by location.
+func isEarlier(a, b *yunyun.SitePage) bool {
+	if a.HasDate() != b.HasDate() {
+		return a.HasDate()
+	}
+	if !a.Published.Equal(b.Published) {
+		return a.Published.Before(b.Published)
+	}
+	return a.Location < b.Location
+}
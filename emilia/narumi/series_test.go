@@ -0,0 +1,70 @@
+package narumi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestWithSeries tests that the series are made of named parts and dated pages of the series directories
+func TestWithSeries(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+	site := yunyun.NewSite([]*yunyun.SitePage{
+		{Location: "go/intro", Published: day(5), SeriesName: "Learning  Go", SeriesOrder: 1},
+		{Location: "go/types", Published: day(1), SeriesName: "learning go"},
+		{Location: "go/channels", Published: day(3), SeriesName: "Learning Go"},
+		{Location: "go/draft", Published: day(2), SeriesName: "Learning Go", Draft: true},
+		{Location: "blog/first", Published: day(1)},
+		{Location: "blog/second", Published: day(2)},
+		{Location: "blog/undated"},
+		{Location: "blog/nested/deep", Published: day(3)},
+	})
+	conf := &alpha.DarknessConfig{Series: alpha.SeriesConfig{Dirs: []yunyun.RelativePathDir{"blog"}}}
+
+	locations := func(pages []*yunyun.SitePage) []yunyun.RelativePathDir {
+		got := make([]yunyun.RelativePathDir, len(pages))
+		for i, page := range pages {
+			got[i] = page.Location
+		}
+		return got
+	}
+	tests := []struct {
+		location yunyun.RelativePathDir
+		name     string
+		series   string
+		parts    []yunyun.RelativePathDir
+		current  int
+	}{
+		{"go/channels", "Learning Go", "Learning Go", []yunyun.RelativePathDir{"go/intro", "go/types", "go/channels"}, 2},
+		{"blog/second", "", "", []yunyun.RelativePathDir{"blog/first", "blog/second"}, 1},
+		{"blog/undated", "", "", nil, 0},
+		{"go/draft", "Learning Go", "", nil, 0},
+	}
+	for _, tt := range tests {
+		page := yunyun.NewPage(yunyun.WithLocation(tt.location))
+		page.SeriesName = tt.name
+		WithSeries(conf, site)(page)
+		if tt.parts == nil {
+			if page.Series != nil {
+				t.Errorf("%s shouldn't be in a series, got %v", tt.location, locations(page.Series.Parts))
+			}
+			continue
+		}
+		if page.Series == nil {
+			t.Fatalf("%s should be in a series", tt.location)
+		}
+		got := locations(page.Series.Parts)
+		if page.Series.Name != tt.series || len(got) != len(tt.parts) || page.Series.Current != tt.current {
+			t.Fatalf("%s series = %q %v at %d, want %q %v at %d", tt.location,
+				page.Series.Name, got, page.Series.Current, tt.series, tt.parts, tt.current)
+		}
+		for i := range got {
+			if got[i] != tt.parts[i] {
+				t.Errorf("%s series = %v, want %v", tt.location, got, tt.parts)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package narumi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// readingSectionId is the html id of the reading time paragraph.
+	readingSectionId = "reading-section"
+
+	// secondsPerImage is how long it takes to look at an image.
+	secondsPerImage = 10
+)
+
+// WithStatistics is a PageOption that counts the words, code blocks, and
+// images of the page and estimates its reading time. If shown, the reading
+// time goes right after the date section.
+func WithStatistics(conf *alpha.DarknessConfig, showReadingTime bool) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || page.Contents == nil || conf == nil {
+			return
+		}
+		stats := PageStatistics(conf, page)
+		page.Stats = &stats
+
+		// Only show the reading time under the date, if the page has it.
+		if !showReadingTime || page.Accoutrement.ReadingTime.IsDisabled() ||
+			stats.Words < 1 || !hasDateSection(page) {
+			return
+		}
+		reading := &yunyun.Content{
+			CustomHtmlTags: fmt.Sprintf(`id="%s"`, readingSectionId),
+			Paragraph:      ReadingTimeText(conf, page.Language, stats) + " · " + WordsText(conf, page.Language, stats),
+			Type:           yunyun.TypeParagraph,
+			Options:        yunyun.NotADescriptionFlag,
+		}
+		page.Contents = append(page.Contents[:1], append(yunyun.Contents{reading}, page.Contents[1:]...)...)
+	}
+}
+
+// PageStatistics returns the statistics of the page's contents, where
+// the CJK text is counted and read by characters, not words.
+func PageStatistics(conf *alpha.DarknessConfig, page *yunyun.Page) yunyun.PageStats {
+	stats := yunyun.PageStats{}
+	characters := 0
+	for _, word := range Words(ContentsText(page)) {
+		stats.Words++
+		if first := []rune(word)[0]; IsCJK(first) {
+			characters++
+		}
+	}
+	for _, content := range page.Contents {
+		switch {
+		case content.IsSourceCode():
+			stats.CodeBlocks++
+		case content.IsGallery() && content.IsList():
+			stats.Images += len(content.List)
+		case content.IsLink() && isImageLink(content):
+			stats.Images++
+		}
+	}
+	words := stats.Words - characters
+	stats.ReadingTime = time.Duration(words)*time.Minute/time.Duration(conf.Reading.WordsPerMinute) +
+		time.Duration(characters)*time.Minute/time.Duration(conf.Reading.CharactersPerMinute) +
+		time.Duration(stats.Images)*secondsPerImage*time.Second
+	return stats
+}
+
+// ReadingTimeText returns the human readable reading time in the
+// language, like "5 min read".
+func ReadingTimeText(conf *alpha.DarknessConfig, language string, stats yunyun.PageStats) string {
+	return conf.Runtime.Messages.Plural(language, beatrice.ReadingTime, stats.ReadingMinutes())
+}
+
+// WordsText returns the human readable word count in the language, like "1,024 words".
+func WordsText(conf *alpha.DarknessConfig, language string, stats yunyun.PageStats) string {
+	return conf.Runtime.Messages.Plural(language, beatrice.Words, stats.Words, humanize.Comma(int64(stats.Words)))
+}
+
+// isImageLink returns true if the link content is shown as an image.
+func isImageLink(content *yunyun.Content) bool {
+	return yunyun.ImageExtRegexp.MatchString(strings.TrimSpace(content.Link)) ||
+		strings.Contains(content.Attributes, "image")
+}
+
+// hasDateSection returns true if the page starts with the date section.
+func hasDateSection(page *yunyun.Page) bool {
+	return len(page.Contents) > 0 &&
+		strings.HasPrefix(page.Contents[0].CustomHtmlTags, fmt.Sprintf(`id="%s"`, dateSectionId))
+}
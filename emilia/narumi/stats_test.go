@@ -0,0 +1,37 @@
+package narumi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestPageStatistics tests the word count and reading time of a page with text, code, and images
+func TestPageStatistics(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	conf := &alpha.DarknessConfig{Reading: alpha.ReadingConfig{WordsPerMinute: 2, CharactersPerMinute: 4}}
+	page := yunyun.NewPage(yunyun.WithContents(yunyun.Contents{
+		{Type: yunyun.TypeHeading, Heading: "Two words"},
+		{Type: yunyun.TypeParagraph, Paragraph: "日本語です and more"},
+		{Type: yunyun.TypeParagraph, Paragraph: "At least a day ago", Options: yunyun.NotADescriptionFlag},
+		{Type: yunyun.TypeSourceCode, SourceCode: "fmt.Println(42)"},
+		{Type: yunyun.TypeLink, Link: "cat.png"},
+		{Type: yunyun.TypeLink, Link: "https://example.com"},
+	}))
+	got := PageStatistics(conf, page)
+	want := yunyun.PageStats{
+		Words: 9,
+		// 4 words at 2 per minute, 5 characters at 4 per minute, and an image.
+		ReadingTime: 2*time.Minute + 75*time.Second + secondsPerImage*time.Second,
+		CodeBlocks:  1,
+		Images:      1,
+	}
+	if got != want {
+		t.Errorf("PageStatistics() = %+v, want %+v", got, want)
+	}
+	if minutes := got.ReadingMinutes(); minutes != 4 {
+		t.Errorf("ReadingMinutes() = %d, want 4", minutes)
+	}
+}
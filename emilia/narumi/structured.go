@@ -0,0 +1,45 @@
+package narumi
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Breadcrumbs returns the pages of the directories above the location,
+// starting from the root, the directories without pages are skipped.
+func Breadcrumbs(site *yunyun.Site, location yunyun.RelativePathDir) []*yunyun.SitePage {
+	if location == "." {
+		return nil
+	}
+	breadcrumbs := make([]*yunyun.SitePage, 0, 4)
+	if root := site.Page("."); root != nil {
+		breadcrumbs = append(breadcrumbs, root)
+	}
+	parts := strings.Split(string(location), "/")
+	for i := 1; i < len(parts); i++ {
+		if page := site.Page(yunyun.RelativePathDir(filepath.Join(parts[:i]...))); page != nil {
+			breadcrumbs = append(breadcrumbs, page)
+		}
+	}
+	return breadcrumbs
+}
+
+// WithStructuredData is a PageOption that finds the breadcrumbs and the
+// modification date of the page for its JSON-LD, if enabled.
+func WithStructuredData(conf *alpha.DarknessConfig, site *yunyun.Site) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || conf == nil || site == nil || !conf.StructuredData.Enable {
+			return
+		}
+		if conf.StructuredData.Rule(page.Location).Exclude {
+			return
+		}
+		if sitePage := site.Page(page.Location); sitePage != nil {
+			page.Modified = sitePage.Modified
+		}
+		page.Breadcrumbs = Breadcrumbs(site, page.Location)
+	}
+}
@@ -0,0 +1,36 @@
+package narumi
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestBreadcrumbs tests that the breadcrumbs are the existing pages above the page
+func TestBreadcrumbs(t *testing.T) {
+	site := yunyun.NewSite([]*yunyun.SitePage{
+		{Location: "."},
+		{Location: "blog"},
+		{Location: "blog/2024/first"},
+		{Location: "about"},
+	})
+	tests := []struct {
+		location yunyun.RelativePathDir
+		expected []yunyun.RelativePathDir
+	}{
+		{".", nil},
+		{"about", []yunyun.RelativePathDir{"."}},
+		{"blog/2024/first", []yunyun.RelativePathDir{".", "blog"}},
+	}
+	for _, tt := range tests {
+		got := Breadcrumbs(site, tt.location)
+		if len(got) != len(tt.expected) {
+			t.Fatalf("%s has %d breadcrumbs, expected %v", tt.location, len(got), tt.expected)
+		}
+		for i, page := range got {
+			if page.Location != tt.expected[i] {
+				t.Errorf("%s breadcrumb %d = %s, expected %s", tt.location, i, page.Location, tt.expected[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package narumi
+
+import (
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TranslationKey returns the key shared by the page's translations, which
+// is `#+translation_key:` if set, otherwise the page's directory, so that
+// `index.org` and `index.ja.org` are translations of each other. Pages
+// without files have no translations.
+func TranslationKey(page *yunyun.Page) string {
+	if len(page.File) < 1 {
+		return ""
+	}
+	if len(page.TranslationKey) > 0 {
+		return "key:" + page.TranslationKey
+	}
+	return "dir:" + string(yunyun.RelativePathTrim(page.File))
+}
+
+// WithTranslations is a PageOption that finds the page in other languages.
+func WithTranslations(site *yunyun.Site) yunyun.PageOption {
+	return func(page *yunyun.Page) {
+		if page == nil || site == nil {
+			return
+		}
+		page.Translations = site.Translations(page.Location)
+	}
+}
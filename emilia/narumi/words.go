@@ -0,0 +1,88 @@
+package narumi
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// PlainText returns the plain text of the page's title, headings,
+// paragraphs, and lists, with all the formatting removed.
+func PlainText(page *yunyun.Page) string {
+	return yunyun.RemoveFormatting(page.Title) + "\n" + ContentsText(page)
+}
+
+// ContentsText returns the plain text of the page's headings,
+// paragraphs, and lists, with all the formatting removed.
+// Galleries and generated paragraphs, like the date, are skipped.
+func ContentsText(page *yunyun.Page) string {
+	var text strings.Builder
+	for _, content := range page.Contents {
+		switch {
+		case content.IsHeading():
+			text.WriteString(yunyun.RemoveFormatting(content.Heading) + "\n")
+		case content.IsParagraph():
+			// Skip the holoscene dates and alike, those are not the text.
+			if puck.HEregex.MatchString(strings.TrimSpace(content.Paragraph)) ||
+				yunyun.HasFlag(&content.Options, yunyun.NotADescriptionFlag) {
+				continue
+			}
+			text.WriteString(yunyun.RemoveFormatting(content.Paragraph) + "\n")
+		case content.IsGallery():
+			// Galleries are lists of images, not text.
+			continue
+		case content.IsList(), content.IsListNumbered():
+			for _, item := range content.List {
+				text.WriteString(yunyun.RemoveFormatting(item.Text) + "\n")
+			}
+		}
+	}
+	return strings.TrimSpace(text.String())
+}
+
+// Words splits the text into lowercased words, where every CJK character
+// is a word by itself, as those scripts don't separate words with spaces.
+func Words(text string) []string {
+	words := make([]string, 0, len(text)/6)
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		switch {
+		case IsCJK(r):
+			flush()
+			words = append(words, string(r))
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word.WriteRune(unicode.ToLower(r))
+		case r == '\'' || r == '’':
+			// Keep contractions like "don't" together.
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// IsCJK returns true if the rune is a Chinese, Japanese, or Korean character.
+func IsCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// IsTerm returns true if the word means something on its own, so it's
+// not too short (unless it's a CJK character) or a common stop word.
+func IsTerm(word string) bool {
+	first, _ := utf8.DecodeRuneInString(word)
+	if !IsCJK(first) && utf8.RuneCountInString(word) < minTermLength {
+		return false
+	}
+	_, isStopWord := stopWords[word]
+	return !isStopWord
+}
@@ -28,6 +28,9 @@ const (
 	// DefaultPreviewHeight is the default height of the gallery preview.
 	PagePreviewHeight = 700
 
+	// PagePreviewFilename is the filename of the generated page preview.
+	PagePreviewFilename yunyun.RelativePathFile = "preview.jpg"
+
 	// LastBuildTimestampFile is where we write the RFC3339 of last build.
 	LastBuildTimestampFile = "last_built.txt"
 )
@@ -1,6 +1,7 @@
 package rem
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"path/filepath"
@@ -72,8 +73,9 @@ func GalleryPreview(conf *alpha.DarknessConfig, item GalleryItem) yunyun.FullPat
 	return conf.Runtime.Join(yunyun.JoinRelativePaths(conf.Project.DarknessPreviewDirectory, galleryPreviewRelative(item)))
 }
 
-// GalleryItemToImage takes in a gallery item and returns an image object.
-func GalleryItemToImage(conf *alpha.DarknessConfig, item GalleryItem, authority, prefix string) (image.Image, error) {
+// GalleryItemToImage takes in a gallery item and returns an image object,
+// the download of a remote one stops if the context is done.
+func GalleryItemToImage(ctx context.Context, conf *alpha.DarknessConfig, item GalleryItem, authority, prefix string) (image.Image, error) {
 	// If it's a local file, simply open the os file.
 	if !item.IsExternal {
 		file := conf.Runtime.WorkDir.Join(yunyun.JoinRelativePaths(item.Path, item.Item))
@@ -89,5 +91,5 @@ func GalleryItemToImage(conf *alpha.DarknessConfig, item GalleryItem, authority,
 	}
 
 	// If it's a remote file, then ask Emilia to try and fetch it.
-	return reze.DownloadImage(string(item.Item), authority, prefix, string(galleryItemHash(item)))
+	return reze.DownloadImage(ctx, string(item.Item), authority, prefix, string(galleryItemHash(item)))
 }
@@ -1,6 +0,0 @@
-package rem
-
-import "github.com/thecsw/darkness/v3/emilia/puck"
-
-// logger is the logger for Rem.
-var logger = puck.NewLogger("Rem 🧹")
@@ -1,12 +1,14 @@
 package rem
 
 import (
-	"os"
+	"context"
+	"io"
 	"path/filepath"
 
 	"github.com/anthonynsimon/bild/imgio"
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/reze"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/rei"
 )
@@ -37,44 +39,43 @@ func galleryVendorItemFilenameLocalPath(conf *alpha.DarknessConfig, item Gallery
 // Only call this function on remote images, it's up to the user to make the
 // .IsExternal check before calling this. SLOW function because of network calls.
 //
-// If the vendoring fails at any point, fallback to the remote image path.
-func GalleryVendorItem(conf *alpha.DarknessConfig, item GalleryItem) (yunyun.FullPathFile, bool) {
+// If the vendoring fails at any point, fallback to the remote image path. The
+// vendored file is recorded in the manifest for the input page, even if it was
+// vendored before, so it's removed once no pages have the image anymore.
+func GalleryVendorItem(ctx context.Context, conf *alpha.DarknessConfig, input yunyun.RelativePathFile, item GalleryItem) (yunyun.FullPathFile, bool) {
 	// Create the two types of return.
 	fallbackReturn := yunyun.FullPathFile(item.Item)
 	localVendoredPath := galleryVendorItemFilenameLocalPath(conf, item)
 	expectedReturn := galleryVendorItemFilename(conf, item)
+	relativeVendoredPath := conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(localVendoredPath))
 
 	// Check if the image was already vendored, if it was, return it immediately.
 	if exists, err := rei.FileExists(localVendoredPath); exists {
+		if hash, err := uiharu.HashFile(yunyun.FullPathFile(localVendoredPath)); err == nil {
+			conf.Runtime.Manifest.Record(input, relativeVendoredPath, hash, false)
+		}
 		return expectedReturn, false
 	} else if err != nil {
-		logger.Error("checking for vendored path existence", "path", localVendoredPath, "err", err)
+		conf.Runtime.Logger.Error("checking for vendored path existence", "path", localVendoredPath, "err", err)
 		return fallbackReturn, false
 	}
 
-	img, err := reze.DownloadImage(string(item.Item), "vendor", "", string(galleryItemHash(item)))
+	img, err := reze.DownloadImage(ctx, string(item.Item), "vendor", "", string(galleryItemHash(item)))
 	if err != nil {
-		logger.Error("downloading vendored image", "item", item.Item, "err", err)
+		conf.Runtime.Logger.Error("downloading vendored image", "item", item.Item, "err", err)
 		return fallbackReturn, false
 	}
 
-	// Open the file writer and encode the image there.
-	imgFile, err := os.Create(filepath.Clean(localVendoredPath))
+	// Encode the image into the file, whole or not at all, as the existing
+	// vendored files are never downloaded again.
+	hash, written, err := uiharu.WriteFile(filepath.Clean(localVendoredPath), func(w io.Writer) error {
+		return imgio.JPEGEncoder(100)(w, img)
+	})
 	if err != nil {
-		logger.Error("creating vendored file", "path", localVendoredPath, "err", err)
-		return fallbackReturn, false
-	}
-	defer func() {
-		if err := imgFile.Close(); err != nil {
-			logger.Error("closing vendored file", "file", localVendoredPath, "err", err)
-		}
-	}()
-
-	// Decode the image into the file.
-	if err := imgio.JPEGEncoder(100)(imgFile, img); err != nil {
-		logger.Error("encoding vendored file", "file", localVendoredPath, "err", err)
+		conf.Runtime.Logger.Error("writing vendored file", "file", localVendoredPath, "err", err)
 		return fallbackReturn, false
 	}
+	conf.Runtime.Manifest.Record(input, relativeVendoredPath, hash, written)
 
 	// Finally.
 	return expectedReturn, true
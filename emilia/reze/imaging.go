@@ -2,6 +2,7 @@ package reze
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"io"
@@ -43,9 +44,9 @@ var (
 )
 
 // DownloadImage attempts to download an image and returns it
-// with any fatal errors (if occured).
-func DownloadImage(link string, authority, prefix, name string) (image.Image, error) {
-	resp, cancel, err := haruhi.URL(link).Client(vendorClient).Response()
+// with any fatal errors (if occured), stopping if the context is done.
+func DownloadImage(ctx context.Context, link string, authority, prefix, name string) (image.Image, error) {
+	resp, cancel, err := haruhi.URL(link).Context(ctx).Client(vendorClient).Response()
 	defer cancel()
 	if err != nil {
 		return nil, fmt.Errorf("downloading image: %v", err)
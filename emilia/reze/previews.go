@@ -2,6 +2,7 @@ package reze
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
@@ -14,8 +15,8 @@ import (
 	"github.com/anthonynsimon/bild/imgio"
 	"github.com/anthonynsimon/bild/transform"
 	"github.com/fogleman/gg"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/yunyun"
-	"github.com/thecsw/rei"
 	"golang.org/x/image/webp"
 )
 
@@ -47,7 +48,8 @@ type PreviewGenerator struct {
 	avatarReadableConverted string
 }
 
-// InitPreviewGenerator initializes a PreviewGenerator.
+// InitPreviewGenerator initializes a PreviewGenerator, it fails if the
+// avatar can't be read or resized.
 func InitPreviewGenerator(
 	TitleFont string,
 	NameFont string,
@@ -56,7 +58,7 @@ func InitPreviewGenerator(
 	Height int,
 	BackgroundColor string,
 	AvatarFile string,
-) PreviewGenerator {
+) (PreviewGenerator, error) {
 	// Create the preview generator.
 	p := PreviewGenerator{
 		titleFont:       TitleFont,
@@ -68,32 +70,45 @@ func InitPreviewGenerator(
 		avatarFile:      AvatarFile,
 	}
 
-	target := rei.Must(os.CreateTemp("", "reze_page_preview.png"))
-	defer func(target *os.File) {
-		err := target.Close()
-		if err != nil {
-			logger.Error("closing temporary file", "loc", target.Name(), "err", err)
-		}
-	}(target)
-	p.avatarProperlySizedFile = target.Name()
-	avatarFileReadable, shouldDelete := convertWebpToPNG(AvatarFile)
+	avatarFileReadable, shouldDelete, err := convertWebpToPNG(AvatarFile)
+	if err != nil {
+		return p, err
+	}
 	if shouldDelete {
 		p.avatarReadableConverted = avatarFileReadable
 	}
-	avatarOriginal := rei.Must(os.Open(filepath.Clean(avatarFileReadable)))
+	avatarOriginal, err := os.Open(filepath.Clean(avatarFileReadable))
+	if err != nil {
+		return p, errors.Join(fmt.Errorf("opening avatar: %v", err), p.Close())
+	}
 	defer func(avatarOriginal *os.File) {
 		err := avatarOriginal.Close()
 		if err != nil {
 			logger.Error("closing avatar file", "loc", AvatarFile, "err", err)
 		}
 	}(avatarOriginal)
-	originalDecoded, _ := rei.Must2(image.Decode(avatarOriginal))
+	originalDecoded, _, err := image.Decode(avatarOriginal)
+	if err != nil {
+		return p, errors.Join(fmt.Errorf("decoding avatar %s: %v", AvatarFile, err), p.Close())
+	}
+
+	// Rescale the avatar to the size on the previews.
 	newWidth := p.calculateAvatarSize()
 	newHeight := PreserveImageHeightRatio(originalDecoded, newWidth)
 	resized := transform.Resize(originalDecoded, newWidth, newHeight, transform.Linear)
-	rei.Try(imgio.PNGEncoder()(target, resized))
-	// Rescale the avatar if needed.
-	return p
+	target, err := os.CreateTemp("", "reze_page_preview.png")
+	if err != nil {
+		return p, errors.Join(fmt.Errorf("creating resized avatar: %v", err), p.Close())
+	}
+	p.avatarProperlySizedFile = target.Name()
+	err = imgio.PNGEncoder()(target, resized)
+	if closeErr := target.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return p, errors.Join(fmt.Errorf("writing resized avatar: %v", err), p.Close())
+	}
+	return p, nil
 }
 
 const (
@@ -161,19 +176,28 @@ func (p PreviewGenerator) Generate(
 	websiteCardTimeOffsetY := websiteCardAvatarOffsetY + websiteCartTimeOffsetDiffY
 
 	// Draw the title.
-	rei.Try(dc.LoadFontFace(p.titleFont, titleFontSize))
+	if err := dc.LoadFontFace(p.titleFont, titleFontSize); err != nil {
+		return nil, fmt.Errorf("loading title font: %v", err)
+	}
 	dc.DrawStringWrapped(yunyun.FancyText(Title), titleOffsetX, titleOffsetY, 0, 0, titleWidth, titleLineSpacing, titleAlign)
 
 	// Draw the website card.
-	rei.Try(dc.LoadFontFace(p.websiteNameFont, websiteCardTitleSize))
+	if err := dc.LoadFontFace(p.websiteNameFont, websiteCardTitleSize); err != nil {
+		return nil, fmt.Errorf("loading name font: %v", err)
+	}
 	dc.DrawStringAnchored(yunyun.FancyText(Name), websiteCardTitleOffsetX, websiteCardTitleOffsetY, 0, 0)
 
 	// Draw the timestamp of the page.
-	rei.Try(dc.LoadFontFace(p.websiteTimeFont, websiteCardTimeSize))
+	if err := dc.LoadFontFace(p.websiteTimeFont, websiteCardTimeSize); err != nil {
+		return nil, fmt.Errorf("loading time font: %v", err)
+	}
 	dc.DrawStringAnchored(Time, websiteCardTimeOffsetX, websiteCardTimeOffsetY, 0, 0)
 
 	// Draw the avatar.
-	im := rei.Must(gg.LoadPNG(p.avatarProperlySizedFile))
+	im, err := gg.LoadPNG(p.avatarProperlySizedFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading resized avatar: %v", err)
+	}
 	dc.DrawImageAnchored(im, int(websiteCardAvatarOffsetX), int(websiteCardAvatarOffsetY), 0, 0)
 
 	// Push the image to a buffer and return it.
@@ -196,44 +220,52 @@ func (p PreviewGenerator) Close() error {
 			return fmt.Errorf("removing readable avatar %s: %v", p.avatarReadableConverted, err)
 		}
 	}
+	if len(p.avatarProperlySizedFile) < 1 {
+		return nil
+	}
 	if err := os.Remove(p.avatarProperlySizedFile); err != nil {
 		return fmt.Errorf("removing properly sized avatar %s: %v", p.avatarProperlySizedFile, err)
 	}
 	return nil
 }
 
-// SaveJpg saves a jpg image from an io.Reader.
-func SaveJpg(reader io.Reader, filename string) error {
+// SaveJpg saves a jpg image from an io.Reader. Same as uiharu.WriteFile, it
+// returns the hash of the contents and true if the file was written.
+func SaveJpg(reader io.Reader, filename string) (string, bool, error) {
 	im, _, err := image.Decode(reader)
 	if err != nil {
-		return fmt.Errorf("decoding image reader: %v", err)
+		return "", false, fmt.Errorf("decoding image reader: %v", err)
 	}
-	target, err := os.Create(filepath.Clean(filename))
-	if err != nil {
-		return fmt.Errorf("creating file %s: %v", filename, err)
+	// Write it whole or not at all, so an interrupted build leaves no broken previews.
+	return uiharu.WriteFile(filepath.Clean(filename), func(w io.Writer) error {
+		if err := imgio.JPEGEncoder(100)(w, im); err != nil {
+			return fmt.Errorf("encoding to jpeg: %v", err)
+		}
+		return nil
+	})
+}
+
+// convertWebpToPNG converts a webp image to a png image, and tells if the
+// png was made, so it should be removed after.
+func convertWebpToPNG(filename string) (string, bool, error) {
+	if !strings.HasSuffix(filename, ".webp") {
+		return filename, false, nil
 	}
-	if err := imgio.JPEGEncoder(100)(target, im); err != nil {
-		return fmt.Errorf("encoding to jpeg: %v", err)
+	source, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return "", false, fmt.Errorf("opening webp avatar: %v", err)
 	}
-	if err := target.Close(); err != nil {
-		return fmt.Errorf("closing file %s: %v", filename, err)
+	defer source.Close()
+	img, err := webp.Decode(source)
+	if err != nil {
+		return "", false, fmt.Errorf("decoding webp avatar %s: %v", filename, err)
 	}
-	return nil
-}
-
-// convertWebpToPNG converts a webp image to a png image.
-func convertWebpToPNG(filename string) (string, bool) {
-	if strings.HasSuffix(filename, ".webp") {
-		source := rei.Must(os.Open(filepath.Clean(filename)))
-		img := rei.Must(webp.Decode(source))
-
-		targetFilename := strings.ReplaceAll(filename, ".webp", ".png")
-		targetFile := rei.Must(os.Create(filepath.Clean(targetFilename)))
-
-		rei.Try(png.Encode(targetFile, img))
-		rei.Try(targetFile.Close())
-		rei.Try(source.Close())
-		return targetFilename, true
+	targetFilename := strings.ReplaceAll(filename, ".webp", ".png")
+	_, _, err = uiharu.WriteFile(filepath.Clean(targetFilename), func(w io.Writer) error {
+		return png.Encode(w, img)
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("converting webp avatar %s: %v", filename, err)
 	}
-	return filename, false
+	return targetFilename, true, nil
 }
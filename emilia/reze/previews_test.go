@@ -0,0 +1,19 @@
+package reze
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestInitPreviewGeneratorMissingAvatar tests that a missing avatar is an
+// error instead of a panic
+func TestInitPreviewGeneratorMissingAvatar(t *testing.T) {
+	avatar := filepath.Join(t.TempDir(), "nowhere.webp")
+	if _, err := InitPreviewGenerator("", "", "", 100, 50, "#ffffff", avatar); err == nil {
+		t.Errorf("expected an error for the missing avatar %s", avatar)
+	}
+	avatar = filepath.Join(t.TempDir(), "nowhere.png")
+	if _, err := InitPreviewGenerator("", "", "", 100, 50, "#ffffff", avatar); err == nil {
+		t.Errorf("expected an error for the missing avatar %s", avatar)
+	}
+}
@@ -0,0 +1,305 @@
+package uiharu
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// ManifestFile is where the manifest of the last build is kept.
+	ManifestFile yunyun.RelativePathFile = ".darkness/manifest.json"
+
+	// SiteInput is the input of the outputs that belong to the whole site,
+	// like the sitemap and the search index.
+	SiteInput yunyun.RelativePathFile = "."
+)
+
+// ErrChanged is returned when the output was changed since it was written.
+var ErrChanged = errors.New("changed since it was written")
+
+// Manifest maps the inputs of a build to the outputs written for them. It is safe
+// for concurrent use, and a nil manifest records nothing.
+type Manifest struct {
+	// mutex guards the inputs.
+	mutex sync.Mutex
+	// Built is when the build happened.
+	Built time.Time `json:"built"`
+	// Inputs are the written outputs by their inputs, relative to the working directory.
+	Inputs map[yunyun.RelativePathFile][]*Output `json:"inputs"`
+	// counts are what happened to the outputs since they were last taken.
+	counts Counts
+}
+
+// Output is a single written file.
+type Output struct {
+	// File is the output file relative to the working directory.
+	File yunyun.RelativePathFile `json:"file"`
+	// Hash is the hex-encoded SHA-256 of the written contents.
+	Hash string `json:"hash"`
+}
+
+// NewManifest returns an empty manifest of a build starting now.
+func NewManifest() *Manifest {
+	return &Manifest{Built: time.Now().UTC(), Inputs: make(map[yunyun.RelativePathFile][]*Output)}
+}
+
+// Load reads the manifest, a missing manifest is an empty one.
+func Load(filename yunyun.FullPathFile) (*Manifest, error) {
+	manifest := NewManifest()
+	data, err := os.ReadFile(filepath.Clean(string(filename)))
+	if errors.Is(err, os.ErrNotExist) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, fmt.Errorf("reading manifest %s: %v", filename, err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return NewManifest(), fmt.Errorf("decoding manifest %s: %v", filename, err)
+	}
+	return manifest, nil
+}
+
+// Save writes the manifest, creating its directory if needed.
+func (m *Manifest) Save(filename yunyun.FullPathFile) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// The outputs are written concurrently, keep the manifest stable between builds.
+	for _, outputs := range m.Inputs {
+		slices.SortFunc(outputs, compareOutputs)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %v", err)
+	}
+	_, _, err = WriteFile(string(filename), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+	return err
+}
+
+// Record remembers that the output with the hash was written for the input, or
+// was already there if not written. Writing the same output again only updates its hash.
+func (m *Manifest) Record(input, output yunyun.RelativePathFile, hash string, written bool) {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if written {
+		m.counts.Written++
+	} else {
+		m.counts.Unchanged++
+	}
+	m.record(input, output, hash)
+}
+
+// record remembers the output with the hash for the input, the caller holds the mutex.
+func (m *Manifest) record(input, output yunyun.RelativePathFile, hash string) {
+	for _, recorded := range m.Inputs[input] {
+		if recorded.File == output {
+			recorded.Hash = hash
+			return
+		}
+	}
+	m.Inputs[input] = append(m.Inputs[input], &Output{File: output, Hash: hash})
+}
+
+// Merge records the outputs of the other manifest in this one, like the outputs
+// that a stopped build managed to write, keeping the other outputs of the inputs.
+func (m *Manifest) Merge(other *Manifest) {
+	if m == nil || other == nil {
+		return
+	}
+	inputs := other.snapshot()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for input, outputs := range inputs {
+		for _, output := range outputs {
+			m.record(input, output.File, output.Hash)
+		}
+	}
+}
+
+// snapshot returns a copy of the inputs and their outputs.
+func (m *Manifest) snapshot() map[yunyun.RelativePathFile][]Output {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	inputs := make(map[yunyun.RelativePathFile][]Output, len(m.Inputs))
+	for input, outputs := range m.Inputs {
+		for _, output := range outputs {
+			inputs[input] = append(inputs[input], *output)
+		}
+	}
+	return inputs
+}
+
+// Removed counts the removed output.
+func (m *Manifest) Removed() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.counts.Removed++
+}
+
+// TakeCounts returns what happened to the outputs since the counts were
+// last taken, and starts counting from zero.
+func (m *Manifest) TakeCounts() Counts {
+	if m == nil {
+		return Counts{}
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	counts := m.counts
+	m.counts = Counts{}
+	return counts
+}
+
+// Forget drops the input and returns the outputs that were written only for it,
+// the outputs shared with other inputs, like vendored gallery images, are kept.
+func (m *Manifest) Forget(input yunyun.RelativePathFile) []*Output {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	outputs := m.Inputs[input]
+	delete(m.Inputs, input)
+	shared := make(map[yunyun.RelativePathFile]struct{})
+	for _, others := range m.Inputs {
+		for _, output := range others {
+			shared[output.File] = struct{}{}
+		}
+	}
+	return slices.DeleteFunc(outputs, func(output *Output) bool {
+		_, found := shared[output.File]
+		return found
+	})
+}
+
+// Outputs returns all the written outputs sorted by their files.
+func (m *Manifest) Outputs() []*Output {
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	outputs := make([]*Output, 0, len(m.Inputs))
+	for _, written := range m.Inputs {
+		outputs = append(outputs, written...)
+	}
+	slices.SortFunc(outputs, compareOutputs)
+	return slices.CompactFunc(outputs, func(a, b *Output) bool { return a.File == b.File })
+}
+
+// Orphans returns the outputs of this manifest whose inputs are gone from the working
+// directory and that the current build didn't write again, sorted by their files. The
+// site-wide outputs are written by every build, so the ones not written are orphans.
+func (m *Manifest) Orphans(current *Manifest, workDir yunyun.FullPathDir) []*Output {
+	written := make(map[yunyun.RelativePathFile]struct{})
+	for _, output := range current.Outputs() {
+		written[output.File] = struct{}{}
+	}
+	if m == nil {
+		return nil
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	orphans := make([]*Output, 0, 4)
+	for input, outputs := range m.Inputs {
+		if input != SiteInput && exists(workDir, input) {
+			continue
+		}
+		for _, output := range outputs {
+			if _, found := written[output.File]; !found {
+				orphans = append(orphans, output)
+			}
+		}
+	}
+	slices.SortFunc(orphans, compareOutputs)
+	return orphans
+}
+
+// compareOutputs orders the outputs by their files.
+func compareOutputs(a, b *Output) int {
+	return strings.Compare(string(a.File), string(b.File))
+}
+
+// exists tells us if the input is still a file in the working directory.
+func exists(workDir yunyun.FullPathDir, input yunyun.RelativePathFile) bool {
+	info, err := os.Stat(filepath.Join(string(workDir), string(input)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// Remove deletes the output from the working directory along with the directories
+// it leaves empty, unless it was changed since, then `ErrChanged` is returned.
+// Outputs that are already gone are fine.
+func (o *Output) Remove(workDir yunyun.FullPathDir) error {
+	filename := filepath.Join(string(workDir), string(o.File))
+	hash, err := HashFile(yunyun.FullPathFile(filename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hash != o.Hash {
+		return ErrChanged
+	}
+	if err := os.Remove(filename); err != nil {
+		return fmt.Errorf("removing %s: %v", o.File, err)
+	}
+	// Generated pages get their own directories, don't leave them empty. Removing
+	// a directory that still has something in it fails, so stop right there.
+	for dir := filepath.Dir(filename); dir != string(workDir) && strings.HasPrefix(dir, string(workDir)); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// NewHash returns the hash that the manifest uses, for hashing while writing.
+func NewHash() hash.Hash {
+	return sha256.New()
+}
+
+// Sum returns the hex-encoded sum of the hash.
+func Sum(h hash.Hash) string {
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Hash returns the hex-encoded SHA-256 of the data.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// HashFile returns the hex-encoded SHA-256 of the file's contents.
+func HashFile(filename yunyun.FullPathFile) (string, error) {
+	file, err := os.Open(filepath.Clean(string(filename)))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := NewHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("hashing %s: %v", filename, err)
+	}
+	return Sum(h), nil
+}
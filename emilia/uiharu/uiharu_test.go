@@ -0,0 +1,172 @@
+package uiharu
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestOrphans tests that only the outputs of gone inputs are orphans and
+// that changed outputs survive their removal
+func TestOrphans(t *testing.T) {
+	workDir := t.TempDir()
+	write := func(file, data string) {
+		filename := filepath.Join(workDir, file)
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("kept/index.org", "kept")
+	write("kept/index.html", "kept")
+	write("gone/deep/index.html", "gone")
+	write("changed/index.html", "changed by hand")
+	write("search/index-gone.json", "{}")
+
+	previous := NewManifest()
+	previous.Record("kept/index.org", "kept/index.html", Hash([]byte("kept")), true)
+	previous.Record("gone/deep/index.org", "gone/deep/index.html", Hash([]byte("gone")), true)
+	previous.Record("changed/index.org", "changed/index.html", Hash([]byte("changed")), true)
+	previous.Record(SiteInput, "search/index-gone.json", Hash([]byte("{}")), true)
+	previous.Record(SiteInput, "sitemap.xml", Hash([]byte("<urlset/>")), true)
+
+	current := NewManifest()
+	current.Record("kept/index.org", "kept/index.html", Hash([]byte("kept")), true)
+	current.Record(SiteInput, "sitemap.xml", Hash([]byte("<urlset/>")), true)
+
+	orphans := previous.Orphans(current, yunyun.FullPathDir(workDir))
+	files := make([]yunyun.RelativePathFile, len(orphans))
+	for i, orphan := range orphans {
+		files[i] = orphan.File
+	}
+	expected := []yunyun.RelativePathFile{"changed/index.html", "gone/deep/index.html", "search/index-gone.json"}
+	if len(files) != len(expected) {
+		t.Fatalf("got orphans %v, expected %v", files, expected)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Fatalf("got orphans %v, expected %v", files, expected)
+		}
+	}
+
+	if err := orphans[0].Remove(yunyun.FullPathDir(workDir)); !errors.Is(err, ErrChanged) {
+		t.Errorf("removing a changed output: got %v, expected %v", err, ErrChanged)
+	}
+	if err := orphans[1].Remove(yunyun.FullPathDir(workDir)); err != nil {
+		t.Errorf("removing an orphan: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "gone")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the empty directories of the orphan to be removed")
+	}
+	if err := orphans[1].Remove(yunyun.FullPathDir(workDir)); err != nil {
+		t.Errorf("removing a removed orphan: %v", err)
+	}
+
+	// The manifest should survive the round trip.
+	filename := yunyun.FullPathFile(filepath.Join(workDir, string(ManifestFile)))
+	if err := previous.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(loaded.Outputs()); got != 5 {
+		t.Errorf("loaded %d outputs, expected 5", got)
+	}
+}
+
+// TestMerge tests that the merged outputs replace the hashes of the same
+// outputs and keep the rest
+func TestMerge(t *testing.T) {
+	previous := NewManifest()
+	previous.Record("a.org", "a.html", "old", true)
+	previous.Record("a.org", "a.json", "old", true)
+	previous.Record("b.org", "b.html", "old", true)
+	stopped := NewManifest()
+	stopped.Record("a.org", "a.html", "new", true)
+	stopped.Record("c.org", "c.html", "new", true)
+	previous.Merge(stopped)
+
+	expected := map[yunyun.RelativePathFile]string{"a.html": "new", "a.json": "old", "b.html": "old", "c.html": "new"}
+	outputs := previous.Outputs()
+	if len(outputs) != len(expected) {
+		t.Fatalf("got %d outputs, expected %d", len(outputs), len(expected))
+	}
+	for _, output := range outputs {
+		if output.Hash != expected[output.File] {
+			t.Errorf("%s has hash %q, expected %q", output.File, output.Hash, expected[output.File])
+		}
+	}
+}
+
+// TestForget tests that forgetting an input only returns the outputs that
+// no other input shares, like a gallery image vendored for two pages
+func TestForget(t *testing.T) {
+	manifest := NewManifest()
+	manifest.Record("a.org", "a.html", "a", true)
+	manifest.Record("a.org", "preview.jpg", "p", true)
+	manifest.Record("a.org", "vendor/cat.jpg", "c", true)
+	manifest.Record("b.org", "vendor/cat.jpg", "c", true)
+
+	forgotten := manifest.Forget("a.org")
+	if len(forgotten) != 2 || forgotten[0].File != "a.html" || forgotten[1].File != "preview.jpg" {
+		t.Errorf("forgot %v, expected a.html and preview.jpg", forgotten)
+	}
+	if outputs := manifest.Outputs(); len(outputs) != 1 || outputs[0].File != "vendor/cat.jpg" {
+		t.Errorf("kept %v, expected vendor/cat.jpg", outputs)
+	}
+}
+
+// TestWriteFile tests that the files are only written when their contents
+// change and that no temporary files are left behind
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "deep", "index.html")
+	write := func(data string) bool {
+		hash, written, err := WriteFile(filename, func(w io.Writer) error {
+			_, err := io.WriteString(w, data)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != Hash([]byte(data)) {
+			t.Errorf("got hash %s, expected the hash of %q", hash, data)
+		}
+		return written
+	}
+	if !write("hello") {
+		t.Errorf("expected a new file to be written")
+	}
+	if write("hello") {
+		t.Errorf("expected the same contents not to be written")
+	}
+	if !write("bye") {
+		t.Errorf("expected new contents to be written")
+	}
+	if data, _ := os.ReadFile(filename); string(data) != "bye" {
+		t.Errorf("got %q, expected %q", data, "bye")
+	}
+
+	// A failed write leaves the file as it was.
+	_, _, err := WriteFile(filename, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "half")
+		return errors.New("crashed")
+	})
+	if err == nil {
+		t.Errorf("expected the failed write to fail")
+	}
+	if data, _ := os.ReadFile(filename); string(data) != "bye" {
+		t.Errorf("got %q after a failed write, expected %q", data, "bye")
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(filename)); len(entries) != 1 {
+		t.Errorf("expected no temporary files left, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,64 @@
+package uiharu
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Counts are what the build did with its outputs.
+type Counts struct {
+	// Written are the outputs written with new contents.
+	Written int
+	// Unchanged are the outputs that already had the same contents.
+	Unchanged int
+	// Removed are the outputs removed after their pages were gone.
+	Removed int
+}
+
+// String returns the counts as the build summary shows them.
+func (c Counts) String() string {
+	return fmt.Sprintf("%d written, %d unchanged, %d removed", c.Written, c.Unchanged, c.Removed)
+}
+
+// WriteFile writes the file through a temporary file next to it that is renamed
+// over it, so nobody ever sees a half-written file. If the file already has the
+// same contents, it's left alone with its modification time, so that rsync and
+// the caches of static hosts don't see a change. It returns the hash of the
+// contents and true if the file was written.
+func WriteFile(filename string, write func(io.Writer) error) (string, bool, error) {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, fmt.Errorf("creating directory of %s: %v", filename, err)
+	}
+	// Dot files are ignored by the dev server's watcher.
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return "", false, fmt.Errorf("creating temporary file for %s: %v", filename, err)
+	}
+	// Nothing to remove once renamed.
+	defer os.Remove(temp.Name())
+
+	h := NewHash()
+	if err := write(io.MultiWriter(temp, h)); err != nil {
+		_ = temp.Close()
+		return "", false, fmt.Errorf("writing %s: %v", filename, err)
+	}
+	if err := temp.Close(); err != nil {
+		return "", false, fmt.Errorf("closing temporary file for %s: %v", filename, err)
+	}
+	hash := Sum(h)
+	if existing, err := HashFile(yunyun.FullPathFile(filename)); err == nil && existing == hash {
+		return hash, false, nil
+	}
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		return "", false, fmt.Errorf("setting permissions of %s: %v", filename, err)
+	}
+	if err := os.Rename(temp.Name(), filename); err != nil {
+		return "", false, fmt.Errorf("renaming temporary file to %s: %v", filename, err)
+	}
+	return hash, true, nil
+}
@@ -0,0 +1,38 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+)
+
+// backlinks builds the list of pages linking to this page, with the
+// paragraphs they link from, goes right after the related pages.
+func (e *state) backlinks() string {
+	if len(e.page.Backlinks) < 1 {
+		return ""
+	}
+	items := make([]string, len(e.page.Backlinks))
+	for i, backlink := range e.page.Backlinks {
+		context := ""
+		if len(backlink.Context) > 0 {
+			context = fmt.Sprintf("\n"+`<p class="backlinks-context">%s</p>`, escapeText(backlink.Context))
+		}
+		items[i] = fmt.Sprintf(`<li><a href="%s">%s</a>%s</li>`,
+			escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(backlink.Page.Location))),
+			processTitle(backlink.Page.Title), context)
+	}
+	title := e.conf.Backlinks.Title
+	if len(title) < 1 {
+		title = e.text(beatrice.Backlinks)
+	}
+	return fmt.Sprintf(`
+<div class="writing backlinks">
+<h4 class="backlinks-title">%s</h4>
+<ul>
+%s
+</ul>
+</div>
+`, escapeText(title), strings.Join(items, "\n"))
+}
@@ -56,11 +56,11 @@ func (e *state) resolveDivTags(built string) string {
 func (e *state) heading(content *yunyun.Content) string {
 	toReturn := fmt.Sprintf(`
 <h%d id="%s" class="section-%d">%s</h%d>`,
-		content.HeadingLevelAdjusted, // HTML open tag
-		ExtractID(content.Heading),   // ID
-		content.HeadingLevel,         // section class
-		processText(content.Heading), // Actual title
-		content.HeadingLevelAdjusted, // HTML close tag
+		content.HeadingLevelAdjusted,   // HTML open tag
+		ExtractID(content.Heading),     // ID
+		content.HeadingLevel,           // section class
+		e.processText(content.Heading), // Actual title
+		content.HeadingLevelAdjusted,   // HTML close tag
 	)
 	e.inHeading = true
 	return toReturn
@@ -88,18 +88,18 @@ func (e *state) paragraph(content *yunyun.Content) string {
 %s
 </p>
 </div>`,
-		paragraphClass(content), content.CustomHtmlTags, processText(content.Paragraph),
+		paragraphClass(content), content.CustomHtmlTags, e.processText(content.Paragraph),
 	)
 }
 
 // makeListItem makes an html item
-func makeListItem(item yunyun.ListItem) string {
+func (e *state) makeListItem(item yunyun.ListItem) string {
 	return fmt.Sprintf(`
 <li class="l%d">
 <p>
 %s
 </p>
-</li>`, item.Level, processText(item.Text))
+</li>`, item.Level, e.processText(item.Text))
 }
 
 // list gives us a list html representation
@@ -116,8 +116,8 @@ func (e *state) list(content *yunyun.Content) string {
 </div>
 `,
 		content.CustomHtmlTags,
-		content.Summary, // overloaded summary to store list class
-		strings.Join(gana.Map(makeListItem, content.List), "\n"))
+		escapeAttr(content.Summary), // overloaded summary to store list class
+		strings.Join(gana.Map(e.makeListItem, content.List), "\n"))
 }
 
 // listNumbered gives us a numbered list html representation
@@ -134,8 +134,8 @@ func (e *state) listNumbered(content *yunyun.Content) string {
 </div>
 `,
 		content.CustomHtmlTags,
-		content.Summary, // overloaded summary to store list class
-		strings.Join(gana.Map(makeListItem, content.List), "\n"))
+		escapeAttr(content.Summary), // overloaded summary to store list class
+		strings.Join(gana.Map(e.makeListItem, content.List), "\n"))
 }
 
 // sourceCode gives us a source code html representation
@@ -148,8 +148,8 @@ func (e *state) sourceCode(content *yunyun.Content) string {
 </div>
 `,
 		content.CustomHtmlTags,
-		narumi.MapSourceCodeLang(content.SourceCodeLang),
-		content.SourceCodeLang,
+		escapeAttr(narumi.MapSourceCodeLang(content.SourceCodeLang)),
+		escapeAttr(content.SourceCodeLang),
 		func() string {
 			// Remove the nested parser blockers
 			s := strings.ReplaceAll(content.SourceCode, ",#", "#")
@@ -162,15 +162,21 @@ func (e *state) sourceCode(content *yunyun.Content) string {
 
 // rawHTML gives us a raw html representation
 func (e *state) rawHtml(content *yunyun.Content) string {
+	rawHtml := content.RawHtml
+	// Run it through the sanitizer if the policy asks for it, blocks
+	// marked as unsafe are trusted, unless the policy is strict.
+	if e.conf.Sanitize.ShouldSanitize(e.page.Location, content.IsRawHtmlUnsafe()) {
+		rawHtml = sanitizeHtml(e.conf, rawHtml)
+	}
 	// If the unsafe flag is enabled, don't even wrap it in `mediablock`
 	if content.IsRawHtmlUnsafe() {
-		return content.RawHtml
+		return rawHtml
 	}
 	// If responsive enabled, wrap the inner iframe (*probably*) in it.
 	if content.IsRawHtmlResponsive() {
-		return fmt.Sprintf(responsiveIFrameHtmlTemplate, content.CustomHtmlTags, content.RawHtml)
+		return fmt.Sprintf(responsiveIFrameHtmlTemplate, content.CustomHtmlTags, rawHtml)
 	}
-	return fmt.Sprintf(rawHtmlTemplate, content.CustomHtmlTags, content.RawHtml, content.Caption)
+	return fmt.Sprintf(rawHtmlTemplate, content.CustomHtmlTags, rawHtml, e.processText(content.Caption))
 }
 
 // horizontalLine gives us a horizontal line html representation
@@ -186,7 +192,7 @@ func (e *state) attentionBlock(content *yunyun.Content) string {
 <div class="admonitionblock note">
 <div class="admonition-label">%s</div>
 <div class="admonition-content">%s</div>
-</div>`, content.AttentionTitle, processText(content.AttentionText))
+</div>`, escapeText(content.AttentionTitle), e.processText(content.AttentionText))
 }
 
 // table gives an HTML formatted table
@@ -199,7 +205,7 @@ func (e *state) table(content *yunyun.Content) string {
 		headers = make([]string, len(content.Table[0]))
 		numRows--
 		for j, header := range content.Table[0] {
-			headers[j] = fmt.Sprintf("<th>%s</th>", processTableCell(header))
+			headers[j] = fmt.Sprintf("<th>%s</th>", e.processTableCell(header))
 		}
 	}
 
@@ -213,7 +219,7 @@ func (e *state) table(content *yunyun.Content) string {
 		// Make the rows.
 		for i, row := range content.Table {
 			for j, v := range row {
-				content.Table[i][j] = fmt.Sprintf("<td>%s</td>", processTableCell(v))
+				content.Table[i][j] = fmt.Sprintf("<td>%s</td>", e.processTableCell(v))
 			}
 			rows[i] = fmt.Sprintf("<tr>\n%s</tr>", strings.Join(content.Table[i], "\n"))
 		}
@@ -234,15 +240,15 @@ func (e *state) table(content *yunyun.Content) string {
 		strings.Join(headers, "\n"),
 		strings.Join(rows, "\n"),
 	)
-	return fmt.Sprintf(tableTemplate, content.CustomHtmlTags, content.Caption, tableHtml)
+	return fmt.Sprintf(tableTemplate, content.CustomHtmlTags, e.processText(content.Caption), tableHtml)
 }
 
 // processTableCell returns the HTML representation of a table cell given its content.
-func processTableCell(what string) string {
-	if insideCell, isSpecial := tableSpecialCell(what); isSpecial {
+func (e *state) processTableCell(what string) string {
+	if insideCell, isSpecial := e.tableSpecialCell(what); isSpecial {
 		return insideCell
 	}
-	return processText(what)
+	return e.processText(what)
 }
 
 const (
@@ -254,15 +260,15 @@ const (
 // representation of it, and a boolean indicating that it was indeed a special cell.
 // for example, if the cell is "#+image: [link][text "description"]
 // it will return the HTML representation of the image, and true.
-func tableSpecialCell(what string) (string, bool) {
+func (e *state) tableSpecialCell(what string) (string, bool) {
 	if link := yunyun.ExtractLink(what); link != nil {
 		// If the link is an image, return the HTML representation of it.
 		if after, ok := strings.CutPrefix(link.Link, tableSpecialImagePrefix); ok {
 			return fmt.Sprintf(
 				`<img class="image" src="%s" title="%s" alt="%s">`,
-				after,
-				yunyun.RemoveFormatting(link.Description),
-				yunyun.RemoveFormatting(link.Text),
+				escapeUrl(e.conf, after),
+				escapeAttr(yunyun.RemoveFormatting(link.Description)),
+				escapeAttr(yunyun.RemoveFormatting(link.Text)),
 			), true
 		}
 	}
@@ -272,7 +278,7 @@ func tableSpecialCell(what string) (string, bool) {
 // table gives an HTML formatted table
 func (e *state) details(content *yunyun.Content) string {
 	if content.IsDetails() {
-		return fmt.Sprintf("<details>\n<summary>%s</summary>\n<hr>", content.Summary)
+		return fmt.Sprintf("<details>\n<summary>%s</summary>\n<hr>", e.processText(content.Summary))
 	}
 	return "</details>"
 }
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
 	"github.com/thecsw/darkness/v3/emilia/kowloon"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
@@ -30,7 +30,7 @@ const (
 	// audioEmbedTemplate is the template for audio embeds.
 	audioEmbedTemplate = `
 <div class="media" %s>
-<audio controls><source src="%s" type="audio/mpeg">music is good for the soul</audio>
+<audio controls><source src="%s" type="audio/mpeg">%s</audio>
 </div>`
 
 	// videoEmbedTemplate is the template for video embeds.
@@ -38,7 +38,7 @@ const (
 <div class="media" %s>
 <video controls class="responsive-iframe">
 <source src="%s" type="video/%s">
-Sorry, your browser doesn't support embedded videos.
+%s
 </video>
 <div class="title">%s</div>
 <hr>
@@ -110,73 +110,76 @@ func (e *state) link(content *yunyun.Content) string {
 	switch {
 	case yunyun.ImageExtRegexp.MatchString(cleanLink) || strings.Contains(content.Attributes, "image"):
 		// Put imageblocks.
-		return linkImage(e.conf, content, e.conf.Website.ClickableImages)
+		return e.linkImage(content, e.conf.Website.ClickableImages)
 	case yunyun.AudioFileExtRegexp.MatchString(cleanLink):
 		// Audiofiles
 		return fmt.Sprintf(audioEmbedTemplate,
 			content.CustomHtmlTags,
-			cleanLink,
+			escapeUrl(e.conf, cleanLink),
+			escapeText(e.text(beatrice.AudioFallback)),
 		)
 	case yunyun.VideoFileExtRegexp.MatchString(cleanLink):
 		// Raw videofiles
 		return fmt.Sprintf(videoEmbedTemplate,
 			content.CustomHtmlTags,
-			cleanLink, func(v string) string {
+			escapeUrl(e.conf, cleanLink), func(v string) string {
 				return yunyun.VideoFileExtRegexp.FindAllStringSubmatch(v, 1)[0][1]
 			}(cleanLink),
-			processText(content.LinkTitle),
+			escapeText(e.text(beatrice.VideoFallback)),
+			e.processText(content.LinkTitle),
 		)
 	case yunyun.PdfFileExtRegexp.MatchString(cleanLink):
 		return fmt.Sprintf(pdfEmbedTemplate,
 			content.CustomHtmlTags,
-			cleanLink,
+			escapeUrl(e.conf, cleanLink),
 		)
 	case strings.HasPrefix(cleanLink, youtubeEmbedPrefix):
 		// Youtube videos
 		return fmt.Sprintf(youtubeEmbedTemplate,
 			content.CustomHtmlTags,
-			gana.SkipString(uint(len(youtubeEmbedPrefix)), cleanLink),
+			escapeAttr(gana.SkipString(uint(len(youtubeEmbedPrefix)), cleanLink)),
 		)
 	case strings.HasPrefix(cleanLink, spotifyTrackEmbedPrefix):
 		// Spotify songs
 		return fmt.Sprintf(spotifyTrackEmbedTemplate,
 			content.CustomHtmlTags,
-			gana.SkipString(uint(len(spotifyTrackEmbedPrefix)), cleanLink),
+			escapeAttr(gana.SkipString(uint(len(spotifyTrackEmbedPrefix)), cleanLink)),
 		)
 	case strings.HasPrefix(cleanLink, spotifyPlaylistEmbedPrefix):
 		return fmt.Sprintf(spotifyPlaylistEmbedTemplate,
 			content.CustomHtmlTags,
-			gana.SkipString(uint(len(spotifyPlaylistEmbedPrefix)), cleanLink),
+			escapeAttr(gana.SkipString(uint(len(spotifyPlaylistEmbedPrefix)), cleanLink)),
 		)
 	default:
 		yunyun.AddFlag(&content.Options, linkWasNotSpecialFlag)
 		return fmt.Sprintf(`<a href="%s" title="%s">%s</a>`,
-			cleanLink,
-			yunyun.RemoveFormatting(content.LinkDescription),
-			processText(content.LinkTitle),
+			escapeUrl(e.conf, cleanLink),
+			escapeAttr(yunyun.RemoveFormatting(content.LinkDescription)),
+			e.processText(content.LinkTitle),
 		)
 	}
 }
 
-func linkImage(conf *alpha.DarknessConfig, content *yunyun.Content, isClickable bool) string {
-	loadableLink := kowloon.ConvertImageToLfsMediaLink(conf, content.Link)
+// linkImage returns an html representation of an image embed.
+func (e *state) linkImage(content *yunyun.Content, isClickable bool) string {
+	loadableLink := escapeUrl(e.conf, kowloon.ConvertImageToLfsMediaLink(e.conf, content.Link))
 	// User can elect in darkness.toml to make images clickable.
 	if isClickable {
 		return fmt.Sprintf(imageEmbedTemplateWithHref,
 			content.CustomHtmlTags,
 			loadableLink,
 			loadableLink,
-			yunyun.RemoveFormatting(content.LinkDescription),
-			yunyun.RemoveFormatting(content.LinkTitle),
-			processText(content.LinkTitle),
+			escapeAttr(yunyun.RemoveFormatting(content.LinkDescription)),
+			escapeAttr(yunyun.RemoveFormatting(content.LinkTitle)),
+			e.processText(content.LinkTitle),
 		)
 	}
 	// Send the embed with no clickable images. IsDefault behavior.
 	return fmt.Sprintf(imageEmbedTemplateNoHref,
 		content.CustomHtmlTags,
 		loadableLink,
-		yunyun.RemoveFormatting(content.LinkDescription),
-		yunyun.RemoveFormatting(content.LinkTitle),
-		processText(content.LinkTitle),
+		escapeAttr(yunyun.RemoveFormatting(content.LinkDescription)),
+		escapeAttr(yunyun.RemoveFormatting(content.LinkTitle)),
+		e.processText(content.LinkTitle),
 	)
 }
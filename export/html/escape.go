@@ -0,0 +1,54 @@
+package html
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+)
+
+const (
+	// unsafeUrlReplacement is what we put instead of urls with bad schemes.
+	unsafeUrlReplacement = "#"
+)
+
+// escapeText escapes text that goes between html tags.
+func escapeText(what string) string {
+	return html.EscapeString(what)
+}
+
+// escapeAttr escapes text that goes inside of a quoted html attribute.
+func escapeAttr(what string) string {
+	return html.EscapeString(what)
+}
+
+// escapeUrl escapes a url that goes into an href or src attribute. If the
+// url has a scheme that is not allowed by the sanitize policy (like the
+// infamous `javascript:`), then it's replaced with a harmless anchor.
+func escapeUrl(conf *alpha.DarknessConfig, what string) string {
+	what = strings.TrimSpace(what)
+	if !isUrlSchemeAllowed(conf, what) {
+		return unsafeUrlReplacement
+	}
+	return html.EscapeString(what)
+}
+
+// isUrlSchemeAllowed returns true if the url is relative or its scheme is
+// allowed by the sanitize policy.
+func isUrlSchemeAllowed(conf *alpha.DarknessConfig, what string) bool {
+	// Browsers ignore whitespace and control characters inside of schemes,
+	// so `java\tscript:` is still a script. Strip them before looking.
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(what))
+	colon := strings.IndexByte(cleaned, ':')
+	// No colon or the colon is in the path/query/fragment: relative url.
+	if colon < 0 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return true
+	}
+	return conf.Sanitize.AllowsScheme(strings.ToLower(cleaned[:colon]))
+}
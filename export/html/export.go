@@ -5,10 +5,10 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
 	"github.com/thecsw/darkness/v3/emilia/puck"
 	"github.com/thecsw/darkness/v3/ichika/akane"
 	"github.com/thecsw/darkness/v3/yunyun"
@@ -46,6 +46,9 @@ func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
 		s.table,
 		s.details,
 		s.toc,
+		s.listing,
+		s.tagCloud,
+		s.search,
 	}
 	return s.export()
 }
@@ -53,19 +56,7 @@ func (e ExporterHtml) Do(page *yunyun.Page) io.Reader {
 // Export runs the process of exporting
 func (e *state) export() io.Reader {
 	// Initialize the html mapping after yunyun built regexes.
-	markupHtmlMappingSetOnce.Do(func() {
-		markupHtmlMapping = map[*regexp.Regexp]string{
-			yunyun.BoldItalicText:    `$l<strong><em>$text</em></strong>$r`,
-			yunyun.ItalicBoldText:    `$l<em><strong>$text</strong></em>$r`,
-			yunyun.ItalicText:        `$l<em>$text</em>$r`,
-			yunyun.BoldText:          `$l<strong>$text</strong>$r`,
-			yunyun.VerbatimText:      `$l<code>$text</code>$r`,
-			yunyun.StrikethroughText: `$l<s>$text</s>$r`,
-			yunyun.UnderlineText:     `$l<u>$text</u>$r`,
-			yunyun.SuperscriptText:   `$l<sup>$text</sup>$r`,
-			yunyun.SubscriptText:     `$l<sub>$text</sub>$r`,
-		}
-	})
+	setupMarkupHtml()
 
 	// Add the red tomb to the last paragraph on given directories.
 	// Only trigger if the tombs were manually flipped.
@@ -92,7 +83,7 @@ func (e *state) export() io.Reader {
 		e.page.Accoutrement.PreviewHeight = puck.PagePreviewHeightString
 
 		// Send the page to the preview generator.
-		akane.RequestPagePreview(e.page.Location, e.page.Title, e.page.Date,
+		akane.RequestPagePreview(e.conf, e.page.File, e.page.Location, e.page.Title, e.page.Date,
 			e.page.Accoutrement.PreviewGenerateBg, e.page.Accoutrement.PreviewGenerateFg)
 	}
 
@@ -105,22 +96,27 @@ func (e *state) export() io.Reader {
 	}
 
 	output := fmt.Sprintf(`%s<!DOCTYPE html>
-<html lang="en">
+<html lang="%s">
 <head>
 %s
 <title>%s</title>
 </head>
 <body class="article">
 %s
-%s
-%s
+%s%s%s
+%s%s%s
 </body>
 </html>`,
 		darknessBanner,
+		escapeAttr(e.language()),
 		e.combineAndFilterHtmlHead(),
 		processTitle(flattenFormatting(e.page.Title)),
 		e.authorHeader(),
+		e.seriesBox(),
 		strings.Join(content, ""),
+		e.seriesNav(),
+		e.relatedPages(),
+		e.backlinks(),
 		e.addFootnotes(),
 	)
 
@@ -142,7 +138,7 @@ func (e *state) buildContent() string {
 
 func (e *state) combineAndFilterHtmlHead() string {
 	// Build the array of all head elements (except page's specific head options).
-	allHead := [][]string{e.linkTags(), e.metaTags(), e.styleTags(), e.scriptTags()}
+	allHead := [][]string{e.linkTags(), e.metaTags(), e.structuredData(), e.styleTags(), e.scriptTags()}
 
 	// Go through all the head elements and filter them out depending on page's specific exclusion rules.
 	var finalHead strings.Builder
@@ -246,8 +242,8 @@ func (e *state) authorHeader() string {
 		// Otherwise, join against the relative path of this page.
 		navLinks = append(navLinks,
 			fmt.Sprintf(`<a href="%s">%s</a>`,
-				e.conf.Runtime.Join(yunyun.RelativePathFile(whatToJoin)),
-				v.Title,
+				escapeAttr(string(e.conf.Runtime.Join(yunyun.RelativePathFile(whatToJoin)))),
+				escapeText(v.Title),
 			))
 
 	}
@@ -255,10 +251,10 @@ func (e *state) authorHeader() string {
 	// Close the navigation links span.
 	content += strings.Join(navLinks, " | ") + `</span>`
 
-	// Add the Holoscene time element.
+	// Add the Holoscene time element and the page's tags.
 	content += `
 </div>
-<div id="hetime" class="menu"></div>
+<div id="hetime" class="menu"></div>` + e.pageTags() + e.languageSwitcher() + `
 </div>`
 	// Return the website header.
 	return content
@@ -297,7 +293,7 @@ func (e *state) tocAsContent() []*yunyun.Content {
 		// First, add the table of contents header.
 		{
 			Type:                 yunyun.TypeHeading,
-			Heading:              "table of Contents",
+			Heading:              e.text(beatrice.TableOfContents),
 			HeadingLevel:         3,
 			HeadingLevelAdjusted: 1,
 		},
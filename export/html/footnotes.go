@@ -20,7 +20,7 @@ func (e *state) addFootnotes() string {
 %s
 </div>
 `,
-			i+1, i+1, narumi.FootnoteLabeler(i+1), processText(footnote))
+			i+1, i+1, narumi.FootnoteLabeler(i+1), e.processText(footnote))
 	}
 	return fmt.Sprintf(`
 <div id="footnotes">
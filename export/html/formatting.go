@@ -2,47 +2,75 @@ package html
 
 import (
 	"fmt"
-	"html"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
 	"github.com/thecsw/darkness/v3/emilia/narumi"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
-// markupHtmlMapping maps the regex markup to html replacements
+// boldHtml is the html replacement of the bold markup.
+const boldHtml = `$l<strong>$text</strong>$r`
+
+// markupReplacement is the html replacement of a markup regex.
+type markupReplacement struct {
+	source      *regexp.Regexp
+	replacement string
+}
+
+// markupHtmlMapping maps the regex markup to html replacements in the order they
+// are applied, which must not change between builds. Verbatim goes last, as the
+// other markup doesn't match right inside of its equal signs, but would inside
+// of the code tags.
 var (
-	markupHtmlMapping        map[*regexp.Regexp]string
+	markupHtmlMapping        []markupReplacement
 	markupHtmlMappingSetOnce sync.Once
 )
 
+// setupMarkupHtml sets up the markup html mapping once yunyun built the regexes.
+func setupMarkupHtml() {
+	markupHtmlMappingSetOnce.Do(func() {
+		markupHtmlMapping = []markupReplacement{
+			{yunyun.BoldItalicText, `$l<strong><em>$text</em></strong>$r`},
+			{yunyun.ItalicBoldText, `$l<em><strong>$text</strong></em>$r`},
+			{yunyun.ItalicText, `$l<em>$text</em>$r`},
+			{yunyun.BoldText, boldHtml},
+			{yunyun.StrikethroughText, `$l<s>$text</s>$r`},
+			{yunyun.UnderlineText, `$l<u>$text</u>$r`},
+			{yunyun.SuperscriptText, `$l<sup>$text</sup>$r`},
+			{yunyun.SubscriptText, `$l<sub>$text</sub>$r`},
+			{yunyun.VerbatimText, `$l<code>$text</code>$r`},
+		}
+	})
+}
+
 // markupHtml replaces the markup regexes defined in internal with HTML tags
 func markupHtml(text string) string {
-	for source, replacement := range markupHtmlMapping {
-		text = source.ReplaceAllString(text, replacement)
+	for _, markup := range markupHtmlMapping {
+		text = markup.source.ReplaceAllString(text, markup.replacement)
 	}
 	// We only need to run bold text repacement again
-	text = yunyun.BoldText.ReplaceAllString(text, markupHtmlMapping[yunyun.BoldText])
+	text = yunyun.BoldText.ReplaceAllString(text, boldHtml)
 	text = yunyun.KeyboardRegexp.ReplaceAllString(text, `<kbd>$1</kbd>`)
 	text = yunyun.NewLineRegexp.ReplaceAllString(text, `$1<br>`)
 	return text
 }
 
 // processText returns a properly formatted HTML of a text
-func processText(text string) string {
-	text = markupHtml(html.EscapeString(yunyun.FancyText(text)))
+func (e *state) processText(text string) string {
+	text = markupHtml(escapeText(yunyun.FancyText(text)))
 	text = strings.ReplaceAll(text, "◼", `<b style="color:var(--color-tomb)">◼︎</b>`)
-	text = yunyun.LinkRegexp.ReplaceAllString(text,
-		fmt.Sprintf(`<a href="%s" title="%s">%s</a>`, `$link`, `$desc`, `$text`))
+	text = yunyun.LinkRegexp.ReplaceAllStringFunc(text, e.inlineLink)
 	text = yunyun.MathRegexp.ReplaceAllString(text, `$l\($text\)$r`)
 	text = yunyun.FootnotePostProcessingRegexp.ReplaceAllStringFunc(text, func(what string) string {
 		num, _ := strconv.Atoi(strings.ReplaceAll(what, "!", ""))
 		// get the footnote HTML body
 		footnote := fmt.Sprintf(
-			`<a id="_footnoteref_%d" class="footnote" href="#_footnotedef_%d" title="View footnote.">%s</a>`,
-			num, num, narumi.FootnoteLabeler(num))
+			`<a id="_footnoteref_%d" class="footnote" href="#_footnotedef_%d" title="%s">%s</a>`,
+			num, num, escapeAttr(e.text(beatrice.ViewFootnote)), narumi.FootnoteLabeler(num))
 		return `
 <sup class="footnote">` + footnote + `</sup>
 `
@@ -50,9 +78,21 @@ func processText(text string) string {
 	return strings.TrimSpace(text)
 }
 
+// inlineLink returns the html link of an inline org link. The text has
+// already been escaped by this point, we only need to check the scheme.
+func (e *state) inlineLink(match string) string {
+	link := yunyun.LinkRegexp.ReplaceAllString(match, `$link`)
+	if !isUrlSchemeAllowed(e.conf, link) {
+		link = unsafeUrlReplacement
+	}
+	return fmt.Sprintf(`<a href="%s" title="%s">%s</a>`, link,
+		yunyun.LinkRegexp.ReplaceAllString(match, `$desc`),
+		yunyun.LinkRegexp.ReplaceAllString(match, `$text`))
+}
+
 // processTitle returns a properly formatted HTML of a title
 func processTitle(title string) string {
-	return yunyun.MathRegexp.ReplaceAllString(markupHtml(yunyun.FancyText(title)), `$l\($text\)$r`)
+	return yunyun.MathRegexp.ReplaceAllString(markupHtml(escapeText(yunyun.FancyText(title))), `$l\($text\)$r`)
 }
 
 // flattenFormatting returns a plain-text to be fit into the description
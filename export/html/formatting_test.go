@@ -0,0 +1,29 @@
+package html
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestMarkupHtml tests that the markup inside of verbatim is left as is,
+// the same way every time
+func TestMarkupHtml(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	setupMarkupHtml()
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"some *bold* and /italics/", "some <strong>bold</strong> and <em>italics</em>"},
+		{"for =/italics/=, slashes", "for <code>/italics/</code>, slashes"},
+		{"and =_underline_=.", "and <code>_underline_</code>."},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			if got := markupHtml(tt.input); got != tt.expected {
+				t.Fatalf("markupHtml(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		}
+	}
+}
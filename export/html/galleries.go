@@ -41,11 +41,11 @@ func extractCustomFlex(s string) uint {
 
 // hrefGalleryTagIfLinkGiven returns an href tag if gallery link is found,
 // an empty string otherwise.
-func hrefGalleryTagIfLinkGiven(item rem.GalleryItem) string {
+func hrefGalleryTagIfLinkGiven(conf *alpha.DarknessConfig, item rem.GalleryItem) string {
 	if item.Link == "" {
 		return ""
 	}
-	return fmt.Sprintf(` href="%s"`, item.Link)
+	return fmt.Sprintf(` href="%s"`, escapeUrl(conf, item.Link))
 }
 
 // resolveCustomFlexItemClasses searches for custom support flex item classes.
@@ -58,7 +58,7 @@ func resolveCustomFlexItemClasses(wholeLine string) string {
 }
 
 // makeFlexItem will make an item of the flexbox .gallery with 1/3 width
-func makeFlexItem(conf *alpha.DarknessConfig, item rem.GalleryItem, width uint) string {
+func makeFlexItem(conf *alpha.DarknessConfig, input yunyun.RelativePathFile, item rem.GalleryItem, width uint) string {
 	// See if there is a custom flex width requested for the item.
 	if customFlex := extractCustomFlex(item.OriginalLine); customFlex != 0 {
 		width = customFlex
@@ -74,26 +74,26 @@ func makeFlexItem(conf *alpha.DarknessConfig, item rem.GalleryItem, width uint)
 		// The percentage (or flex class) of the page's width to occupy.
 		width,
 		// Optionally link the gallery image to something.
-		hrefGalleryTagIfLinkGiven(item),
+		hrefGalleryTagIfLinkGiven(conf, item),
 		// Additionally-enabled options, like no-zoom.
 		resolveCustomFlexItemClasses(item.OriginalLine),
 		// Path to the gallery image's preview.
-		rem.GalleryPreview(conf, item),
+		escapeUrl(conf, string(rem.GalleryPreview(conf, item))),
 		// Path to the image (either external, local, or vendored).
-		processGalleryItem(conf, item),
+		escapeUrl(conf, string(processGalleryItem(conf, input, item))),
 		// The text to show on the image hover.
-		item.Description,
+		escapeAttr(item.Description),
 		// The alt descriptino of the image.
-		item.Text,
+		escapeAttr(item.Text),
 	)
 }
 
-// processGalleryItem takes a gallery item and returns the full path, while also submitting an
-// akane request to download the gallery image.
-func processGalleryItem(conf *alpha.DarknessConfig, item rem.GalleryItem) yunyun.FullPathFile {
+// processGalleryItem takes a gallery item of the input page and returns the full path, while
+// also submitting an akane request to download the gallery image.
+func processGalleryItem(conf *alpha.DarknessConfig, input yunyun.RelativePathFile, item rem.GalleryItem) yunyun.FullPathFile {
 	path, shouldBeVendored := rem.GalleryImage(conf, item)
 	if shouldBeVendored {
-		akane.RequestGalleryVendor(item)
+		akane.RequestGalleryVendor(conf, input, item)
 	}
 	return path
 }
@@ -101,7 +101,7 @@ func processGalleryItem(conf *alpha.DarknessConfig, item rem.GalleryItem) yunyun
 // gallery will create a flexbox gallery as defined in .gallery css class
 func (e *state) gallery(content *yunyun.Content) string {
 	makeFlexItemWithFolder := func(s yunyun.ListItem) string {
-		return makeFlexItem(e.conf, rem.NewGalleryItem(e.conf, e.page, content, s.Text), content.GalleryImagesPerRow)
+		return makeFlexItem(e.conf, e.page.File, rem.NewGalleryItem(e.conf, e.page, content, s.Text), content.GalleryImagesPerRow)
 	}
 	return fmt.Sprintf(`
 <div class="gallery-container">
@@ -0,0 +1,115 @@
+package html
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/darkness/v3/yunyun/jsonld"
+)
+
+// structuredData returns the JSON-LD script describing the page, with the
+// article for dated pages, the breadcrumbs, and the website on the root page.
+func (e *state) structuredData() []string {
+	if !e.conf.StructuredData.Enable {
+		return nil
+	}
+	rule := e.conf.StructuredData.Rule(e.page.Location)
+	if rule.Exclude {
+		return nil
+	}
+	nodes := make([]any, 0, 3)
+	if e.page.Location == "." {
+		nodes = append(nodes, e.jsonLdWebSite())
+	}
+	if article := e.jsonLdArticle(rule.Type); article != nil {
+		nodes = append(nodes, article)
+	}
+	if breadcrumbs := e.jsonLdBreadcrumbs(); breadcrumbs != nil {
+		nodes = append(nodes, breadcrumbs)
+	}
+	if len(nodes) < 1 {
+		return nil
+	}
+	// The encoder escapes `<`, `>`, and `&`, so the script can't be closed early.
+	data, err := json.Marshal(&jsonld.Graph{Context: jsonld.JsonLdContext, Nodes: nodes})
+	if err != nil {
+		e.conf.Runtime.Logger.Warn("Encoding structured data", "page", e.page.Location, "err", err)
+		return nil
+	}
+	return []string{fmt.Sprintf(`<script type="%s">%s</script>`, jsonld.JsonLdMimeType, data)}
+}
+
+// jsonLdWebSite describes the whole website.
+func (e *state) jsonLdWebSite() *jsonld.WebSite {
+	return &jsonld.WebSite{
+		Type:        "WebSite",
+		Name:        e.conf.Title,
+		Url:         e.conf.Url,
+		Description: e.description(),
+		InLanguage:  e.language(),
+	}
+}
+
+// jsonLdArticle describes the page as an article of the type, nil if
+// the page has no date, as those are not articles.
+func (e *state) jsonLdArticle(articleType string) *jsonld.Article {
+	published, isDated := narumi.ConvertHoloscene(e.page.Date)
+	if !isDated {
+		return nil
+	}
+	url := string(e.conf.Runtime.JoinDir(e.page.Location))
+	article := &jsonld.Article{
+		Type:             articleType,
+		Headline:         flattenFormatting(e.page.Title),
+		Description:      e.description(),
+		Url:              url,
+		MainEntityOfPage: url,
+		DatePublished:    published.Format(jsonld.JsonLdFormat),
+		InLanguage:       e.language(),
+		Keywords:         e.page.Tags,
+	}
+	if !e.page.Modified.IsZero() {
+		article.DateModified = e.page.Modified.Format(jsonld.JsonLdFormat)
+	}
+	// The page's author wins over the one from the config.
+	author := e.page.Author
+	if len(author) < 1 {
+		author = e.conf.Author.Name
+	}
+	if len(author) > 0 {
+		article.Author = &jsonld.Person{Type: "Person", Name: author, Url: e.conf.Url}
+	}
+	if len(e.page.Accoutrement.Preview) > 0 {
+		article.Image = string(e.conf.Runtime.Join(
+			yunyun.JoinRelativePaths(e.page.Location, yunyun.RelativePathFile(e.page.Accoutrement.Preview))))
+	}
+	if e.page.Stats != nil {
+		article.WordCount = e.page.Stats.Words
+	}
+	return article
+}
+
+// jsonLdBreadcrumbs lists the pages leading to this one, nil on the root page.
+func (e *state) jsonLdBreadcrumbs() *jsonld.BreadcrumbList {
+	if len(e.page.Breadcrumbs) < 1 {
+		return nil
+	}
+	items := make([]*jsonld.ListItem, 0, len(e.page.Breadcrumbs)+1)
+	for _, breadcrumb := range e.page.Breadcrumbs {
+		items = append(items, &jsonld.ListItem{
+			Type:     "ListItem",
+			Position: len(items) + 1,
+			Name:     flattenFormatting(breadcrumb.Title),
+			Item:     string(e.conf.Runtime.JoinDir(breadcrumb.Location)),
+		})
+	}
+	items = append(items, &jsonld.ListItem{
+		Type:     "ListItem",
+		Position: len(items) + 1,
+		Name:     flattenFormatting(e.page.Title),
+		Item:     string(e.conf.Runtime.JoinDir(e.page.Location)),
+	})
+	return &jsonld.BreadcrumbList{Type: "BreadcrumbList", Items: items}
+}
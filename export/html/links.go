@@ -3,6 +3,7 @@ package html
 import (
 	"fmt"
 
+	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
 )
@@ -16,16 +17,31 @@ type rel struct {
 
 // linkTag returns a string of the form <link rel="..." href="..." />
 func linkTag(val rel) string {
-	return fmt.Sprintf(`<link rel="%s" href="%s" type="%s"/>`, val.Rel, val.Href, val.Type)
+	return fmt.Sprintf(`<link rel="%s" href="%s" type="%s"/>`, val.Rel, escapeAttr(string(val.Href)), val.Type)
 }
 
 // linkTags returns a string of the form <link rel="..." href="..." /> for an entire page
 func (e *state) linkTags() []string {
-	return gana.Map(linkTag, []rel{
+	rels := []rel{
 		{"canonical", e.conf.Runtime.Join(yunyun.RelativePathFile(e.page.Location)), ""},
 		{"shortcut icon", e.conf.Runtime.Join("assets/favicon.ico"), "image/x-icon"},
 		{"apple-touch-icon", e.conf.Runtime.Join("assets/apple-touch-icon.png"), "image/png"},
 		{"image_src", e.conf.Runtime.Join("assets/android-chrome-512x512.png"), "image/png"},
 		{"icon", e.conf.Runtime.Join("assets/favicon.ico"), ""},
-	})
+	}
+	// Let the feed readers discover all the feeds we generate in the page's language.
+	for _, feed := range e.conf.Feeds {
+		if feed.Language != e.language() {
+			continue
+		}
+		rels = append(rels, rel{"alternate", e.conf.Runtime.Join(feed.Path), alpha.FeedMimeTypes[feed.Format]})
+	}
+	// Let the crawlers know that the listing goes on.
+	if e.page.Pagination.HasPrev() {
+		rels = append(rels, rel{"prev", e.conf.Runtime.JoinDir(e.page.Pagination.Prev), ""})
+	}
+	if e.page.Pagination.HasNext() {
+		rels = append(rels, rel{"next", e.conf.Runtime.JoinDir(e.page.Pagination.Next), ""})
+	}
+	return append(gana.Map(linkTag, rels), e.hreflangTags()...)
 }
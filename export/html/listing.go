@@ -0,0 +1,87 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// listing builds the list of pages with their titles, dates,
+// descriptions, and previews.
+func (e *state) listing(content *yunyun.Content) string {
+	if content.Listing == nil || len(content.Listing.Items) < 1 {
+		return `<div class="listing listing-empty"></div>`
+	}
+	items := make([]string, len(content.Listing.Items))
+	for i, item := range content.Listing.Items {
+		items[i] = e.listingItem(item)
+	}
+	return fmt.Sprintf("<div class=\"listing\">\n%s</div>%s", strings.Join(items, ""), e.paginationNav())
+}
+
+// paginationNav builds the links to the previous and next pages of the
+// listing, empty if the listing fits on one page.
+func (e *state) paginationNav() string {
+	pagination := e.page.Pagination
+	if pagination == nil {
+		return ""
+	}
+	prev, next := "", ""
+	if pagination.HasPrev() {
+		prev = fmt.Sprintf(`<a href="%s" rel="prev" class="pagination-prev">%s</a>`,
+			escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(pagination.Prev))), escapeText(e.text(beatrice.PaginationPrev)))
+	}
+	if pagination.HasNext() {
+		next = fmt.Sprintf(`<a href="%s" rel="next" class="pagination-next">%s</a>`,
+			escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(pagination.Next))), escapeText(e.text(beatrice.PaginationNext)))
+	}
+	return fmt.Sprintf(`
+<nav class="pagination">
+%s
+<span class="pagination-current">%s</span>
+%s
+</nav>`, prev, escapeText(e.text(beatrice.PaginationCurrent, pagination.Current, pagination.Total)), next)
+}
+
+// listingItem builds a single page of the listing.
+func (e *state) listingItem(item *yunyun.SitePage) string {
+	link := escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(item.Location)))
+	title := processTitle(item.Title)
+
+	preview := ""
+	if len(item.Preview) > 0 {
+		preview = fmt.Sprintf(
+			`<a href="%s" class="listing-preview"><img src="%s" alt="%s" loading="lazy"></a>`+"\n",
+			link,
+			escapeUrl(e.conf, string(e.conf.Runtime.Join(yunyun.JoinRelativePaths(item.Location, item.Preview)))),
+			escapeAttr(flattenFormatting(item.Title)),
+		)
+	}
+
+	date := ""
+	if item.HasDate() {
+		date = fmt.Sprintf(`<span class="listing-date" title="%s">%s</span>`+"\n",
+			escapeAttr(item.Date), item.Published.Format(narumi.RfcEmily))
+	}
+	if e.conf.Reading.Enable && item.Stats.Words > 0 {
+		date += fmt.Sprintf(`<span class="listing-reading-time" title="%s">%s</span>`+"\n",
+			escapeAttr(narumi.WordsText(e.conf, e.language(), item.Stats)),
+			escapeText(narumi.ReadingTimeText(e.conf, e.language(), item.Stats)))
+	}
+
+	description := ""
+	if len(item.Description) > 0 {
+		description = fmt.Sprintf(`<p class="listing-description">%s</p>`+"\n",
+			escapeText(yunyun.FancyText(item.Description)))
+	}
+
+	return fmt.Sprintf(`<div class="listing-item">
+%s<div class="listing-text">
+<a href="%s" class="listing-title">%s</a>
+%s%s</div>
+</div>
+`, preview, link, title, date, description)
+}
@@ -2,11 +2,11 @@ package html
 
 import (
 	"fmt"
-	"html"
 	"path/filepath"
 	"strings"
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
 	"github.com/thecsw/darkness/v3/emilia/puck"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
@@ -14,8 +14,22 @@ import (
 
 // metaTopTag is the top tag for all meta tags
 func (e *state) metaTags() []string {
+	description := e.description()
+	basic := addBasic(e.conf, e.page, description)
+	openGraph := addOpenGraph(e.conf, e.page, description)
+	twitter := addTwitterMeta(e.conf, e.page, description)
+
+	metas := make([]string, 0, len(basic)+len(openGraph)+len(twitter))
+	metas = append(metas, basic...)
+	metas = append(metas, openGraph...)
+	metas = append(metas, twitter...)
+
+	return metas
+}
+
+// description returns the start of the first paragraph that describes the page.
+func (e *state) description() string {
 	// Find the first paragraph for description
-	description := ""
 	for _, content := range e.page.Contents {
 		// We are only looking for paragraphs
 		if !content.IsParagraph() {
@@ -28,20 +42,10 @@ func (e *state) metaTags() []string {
 			yunyun.HasFlag(&content.Options, yunyun.NotADescriptionFlag) {
 			continue
 		}
-		description = flattenFormatting(
+		return flattenFormatting(
 			paragraph[:gana.Min(len(paragraph), e.conf.Website.DescriptionLength)]) + "..."
-		break
 	}
-	basic := addBasic(e.conf, e.page, description)
-	openGraph := addOpenGraph(e.conf, e.page, description)
-	twitter := addTwitterMeta(e.conf, e.page, description)
-
-	metas := make([]string, 0, len(basic)+len(openGraph)+len(twitter))
-	metas = append(metas, basic...)
-	metas = append(metas, openGraph...)
-	metas = append(metas, twitter...)
-
-	return metas
+	return ""
 }
 
 // meta is a struct for meta tags
@@ -51,17 +55,18 @@ type meta struct {
 	Content  string
 }
 
-// metaTag returns a string of the form <meta name="..." content="..." />
+// metaTag returns a string of the form <meta name="..." content="..." />,
+// the content is escaped here and only here, so callers pass raw text.
 func metaTag(val meta) string {
 	if len(val.Property) < 1 {
 		return fmt.Sprintf(
 			`<meta name="%s" content="%s">`,
-			val.Name, html.EscapeString(val.Content),
+			val.Name, escapeAttr(val.Content),
 		)
 	}
 	return fmt.Sprintf(
 		`<meta name="%s" property="%s" content="%s">`,
-		val.Name, val.Property, html.EscapeString(val.Content),
+		val.Name, val.Property, escapeAttr(val.Content),
 	)
 }
 
@@ -88,7 +93,7 @@ func addBasic(conf *alpha.DarknessConfig, page *yunyun.Page, description string)
 		{"author", "author", conf.Author.Name},
 		{"date", "date", page.Date},
 		{"theme-color", "theme-color", conf.Website.Color},
-		{"description", "description", html.EscapeString(description)},
+		{"description", "description", description},
 		{"claude-go-away", "claude-go-away", AnthropicClaudeMagicRefusalString},
 	}...)
 	if len(conf.Website.RobotsMeta) > 0 {
@@ -100,18 +105,18 @@ func addBasic(conf *alpha.DarknessConfig, page *yunyun.Page, description string)
 // addOpenGraph adds the opengraph preview meta tags
 func addOpenGraph(conf *alpha.DarknessConfig, page *yunyun.Page, description string) []string {
 	return gana.Map(metaTag, []meta{
-		{"og:title", "og:title", html.EscapeString(flattenFormatting(page.Title))},
-		{"og:site_name", "og:site_name", html.EscapeString(conf.Title)},
+		{"og:title", "og:title", flattenFormatting(page.Title)},
+		{"og:site_name", "og:site_name", conf.Title},
 		{"og:url", "og:url", string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location)))},
-		{"og:locale", "og:locale", conf.Website.Locale},
+		{"og:locale", "og:locale", pageLocale(conf, page)},
 		{"og:type", "og:type", "website"},
 		{"og:image", "og:image",
 			string(conf.Runtime.Join(yunyun.JoinRelativePaths(page.Location, yunyun.RelativePathFile(page.Accoutrement.Preview))))},
-		{"og:image:alt", "og:image:alt", "Preview"},
+		{"og:image:alt", "og:image:alt", conf.Runtime.Messages.Text(page.Language, beatrice.PreviewAlt)},
 		{"og:image:type", "og:image:type", "image/" + strings.TrimLeft(filepath.Ext(page.Accoutrement.Preview), ".")},
 		{"og:image:width", "og:image:width", page.Accoutrement.PreviewWidth},    // default: "1200"
 		{"og:image:height", "og:image:height", page.Accoutrement.PreviewHeight}, // default: "700"
-		{"og:description", "og:description", html.EscapeString(description)},
+		{"og:description", "og:description", description},
 	})
 }
 
@@ -119,12 +124,21 @@ func addOpenGraph(conf *alpha.DarknessConfig, page *yunyun.Page, description str
 func addTwitterMeta(conf *alpha.DarknessConfig, page *yunyun.Page, description string) []string {
 	return gana.Map(metaTag, []meta{
 		{"twitter:card", "twitter:card", "summary_large_image"},
-		{"twitter:site", "twitter:site", html.EscapeString(conf.Title)},
+		{"twitter:site", "twitter:site", conf.Title},
 		{"twitter:creator", "twitter:creator", conf.Website.Twitter},
 		{"twitter:image:src", "twitter:image:src",
 			string(conf.Runtime.Join(yunyun.JoinRelativePaths(page.Location, yunyun.RelativePathFile(page.Accoutrement.Preview))))},
 		{"twitter:url", "twitter:url", string(conf.Runtime.Join(yunyun.RelativePathFile(page.Location)))},
-		{"twitter:title", "twitter:title", html.EscapeString(flattenFormatting(page.Title))},
-		{"twitter:description", "twitter:description", html.EscapeString(description)},
+		{"twitter:title", "twitter:title", flattenFormatting(page.Title)},
+		{"twitter:description", "twitter:description", description},
 	})
 }
+
+// pageLocale returns the site's locale for the pages in the default language,
+// otherwise the page's language written as a locale.
+func pageLocale(conf *alpha.DarknessConfig, page *yunyun.Page) string {
+	if len(page.Language) < 1 || page.Language == conf.Website.Language() {
+		return conf.Website.Locale
+	}
+	return strings.ReplaceAll(page.Language, "-", "_")
+}
@@ -0,0 +1,35 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+)
+
+// relatedPages builds the list of related pages that goes after
+// the contents and before the footnotes.
+func (e *state) relatedPages() string {
+	if len(e.page.Related) < 1 {
+		return ""
+	}
+	items := make([]string, len(e.page.Related))
+	for i, related := range e.page.Related {
+		date := ""
+		if related.HasDate() {
+			date = fmt.Sprintf(` <span class="related-date">%s</span>`, related.Published.Format(narumi.RfcEmily))
+		}
+		items[i] = fmt.Sprintf(`<li><a href="%s">%s</a>%s</li>`,
+			escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(related.Location))),
+			processTitle(related.Title), date)
+	}
+	return fmt.Sprintf(`
+<div class="writing related">
+<h4 class="related-title">%s</h4>
+<ul>
+%s
+</ul>
+</div>
+`, escapeText(e.text(beatrice.Related)), strings.Join(items, "\n"))
+}
@@ -0,0 +1,265 @@
+package html
+
+import (
+	"html"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+)
+
+var (
+	// droppedWithContentTags are the tags that, if not allowed, are removed
+	// together with everything inside of them, as their contents is not text.
+	droppedWithContentTags = map[string]struct{}{
+		"script": {}, "style": {}, "noscript": {}, "template": {},
+		"textarea": {}, "title": {}, "xmp": {}, "object": {}, "svg": {}, "math": {},
+	}
+
+	// voidTags are the tags that never have a closing tag.
+	voidTags = map[string]struct{}{
+		"area": {}, "base": {}, "br": {}, "col": {}, "embed": {}, "hr": {}, "img": {},
+		"input": {}, "link": {}, "meta": {}, "source": {}, "track": {}, "wbr": {},
+	}
+
+	// urlAttributes are the attributes that hold urls, which have their
+	// schemes checked against the policy.
+	urlAttributes = map[string]struct{}{
+		"href": {}, "src": {}, "poster": {}, "cite": {}, "action": {},
+		"formaction": {}, "background": {}, "longdesc": {}, "usemap": {},
+	}
+)
+
+// sanitizeHtml runs the raw html through the sanitize policy of the config,
+// removing the tags and attributes that are not allowed, dropping scripts
+// with their contents, and closing whatever tags were left open, so a broken
+// block can't break the rest of the page.
+func sanitizeHtml(conf *alpha.DarknessConfig, raw string) string {
+	s := &sanitizer{conf: conf, raw: raw}
+	s.run()
+	return s.out.String()
+}
+
+// sanitizer is the state of a single sanitization run.
+type sanitizer struct {
+	// conf is where we get the policy from.
+	conf *alpha.DarknessConfig
+	// raw is the input html.
+	raw string
+	// pos is the current position in `raw`.
+	pos int
+	// open is the stack of currently open allowed tags.
+	open []string
+	// out is the sanitized html.
+	out strings.Builder
+}
+
+// run goes through the whole input.
+func (s *sanitizer) run() {
+	for s.pos < len(s.raw) {
+		lt := strings.IndexByte(s.raw[s.pos:], '<')
+		if lt < 0 {
+			s.out.WriteString(s.raw[s.pos:])
+			break
+		}
+		s.out.WriteString(s.raw[s.pos : s.pos+lt])
+		s.pos += lt
+		rest := s.raw[s.pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			// Comments are dropped, they can hide conditional comments.
+			s.skipPast("-->")
+		case strings.HasPrefix(rest, "</"):
+			s.endTag()
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			// Doctypes, cdata, and processing instructions.
+			s.skipPast(">")
+		case len(rest) > 1 && isAsciiLetter(rest[1]):
+			s.startTag()
+		default:
+			// A lone `<` is just text.
+			s.out.WriteString("&lt;")
+			s.pos++
+		}
+	}
+	// Close everything that was left open.
+	for i := len(s.open) - 1; i >= 0; i-- {
+		s.out.WriteString("</" + s.open[i] + ">")
+	}
+}
+
+// skipPast moves the position to right after `what`, or to the end.
+func (s *sanitizer) skipPast(what string) {
+	end := strings.Index(s.raw[s.pos:], what)
+	if end < 0 {
+		s.pos = len(s.raw)
+		return
+	}
+	s.pos += end + len(what)
+}
+
+// readName reads a tag or attribute name at the current position.
+func (s *sanitizer) readName() string {
+	start := s.pos
+	for s.pos < len(s.raw) && !strings.ContainsRune(" \t\n\r\f/>=", rune(s.raw[s.pos])) {
+		s.pos++
+	}
+	return strings.ToLower(s.raw[start:s.pos])
+}
+
+// skipSpaces moves the position past whitespace.
+func (s *sanitizer) skipSpaces() {
+	for s.pos < len(s.raw) && strings.ContainsRune(" \t\n\r\f", rune(s.raw[s.pos])) {
+		s.pos++
+	}
+}
+
+// endTag handles `</name>`.
+func (s *sanitizer) endTag() {
+	s.pos += len("</")
+	name := s.readName()
+	s.skipPast(">")
+	// Only close the tags that we opened, searching from the top
+	// of the stack, so nested same tags work.
+	for i := len(s.open) - 1; i >= 0; i-- {
+		if s.open[i] != name {
+			continue
+		}
+		for j := len(s.open) - 1; j >= i; j-- {
+			s.out.WriteString("</" + s.open[j] + ">")
+		}
+		s.open = s.open[:i]
+		return
+	}
+}
+
+// attribute is a single parsed attribute.
+type attribute struct {
+	name  string
+	value string
+}
+
+// startTag handles `<name attr="value" ...>`.
+func (s *sanitizer) startTag() {
+	s.pos++
+	name := s.readName()
+	attributes, selfClosing := s.readAttributes()
+
+	if !s.conf.Sanitize.AllowsTag(name) {
+		// Some tags are removed with everything inside of them.
+		if _, dropContent := droppedWithContentTags[name]; dropContent && !selfClosing {
+			s.skipPastEndTag(name)
+		}
+		return
+	}
+
+	s.out.WriteString("<" + name)
+	for _, attr := range attributes {
+		if !s.conf.Sanitize.AllowsAttribute(attr.name) || !s.isAttributeValueSafe(attr) {
+			continue
+		}
+		s.out.WriteString(" " + attr.name + `="` + escapeAttr(attr.value) + `"`)
+	}
+	s.out.WriteString(">")
+
+	if _, isVoid := voidTags[name]; !isVoid && !selfClosing {
+		s.open = append(s.open, name)
+	}
+}
+
+// readAttributes reads the attributes until the end of the tag, returns
+// them and whether the tag was self-closing.
+func (s *sanitizer) readAttributes() ([]attribute, bool) {
+	attributes := make([]attribute, 0, 4)
+	for s.pos < len(s.raw) {
+		s.skipSpaces()
+		if s.pos >= len(s.raw) {
+			break
+		}
+		switch s.raw[s.pos] {
+		case '>':
+			s.pos++
+			return attributes, false
+		case '/':
+			s.pos++
+			if s.pos < len(s.raw) && s.raw[s.pos] == '>' {
+				s.pos++
+				return attributes, true
+			}
+			continue
+		}
+		name := s.readName()
+		if name == "" {
+			// Stray `=` or alike, skip it.
+			s.pos++
+			continue
+		}
+		s.skipSpaces()
+		value := ""
+		if s.pos < len(s.raw) && s.raw[s.pos] == '=' {
+			s.pos++
+			s.skipSpaces()
+			value = s.readValue()
+		}
+		attributes = append(attributes, attribute{name: name, value: html.UnescapeString(value)})
+	}
+	return attributes, false
+}
+
+// readValue reads a quoted or unquoted attribute value.
+func (s *sanitizer) readValue() string {
+	if s.pos >= len(s.raw) {
+		return ""
+	}
+	if quote := s.raw[s.pos]; quote == '"' || quote == '\'' {
+		s.pos++
+		end := strings.IndexByte(s.raw[s.pos:], quote)
+		if end < 0 {
+			value := s.raw[s.pos:]
+			s.pos = len(s.raw)
+			return value
+		}
+		value := s.raw[s.pos : s.pos+end]
+		s.pos += end + 1
+		return value
+	}
+	start := s.pos
+	for s.pos < len(s.raw) && !strings.ContainsRune(" \t\n\r\f>", rune(s.raw[s.pos])) {
+		s.pos++
+	}
+	return s.raw[start:s.pos]
+}
+
+// skipPastEndTag moves the position right after `</name>`.
+func (s *sanitizer) skipPastEndTag(name string) {
+	closing := "</" + name
+	for i := s.pos; i+len(closing) <= len(s.raw); i++ {
+		if strings.EqualFold(s.raw[i:i+len(closing)], closing) {
+			s.pos = i
+			s.skipPast(">")
+			return
+		}
+	}
+	s.pos = len(s.raw)
+}
+
+// isAttributeValueSafe checks the urls inside of attributes.
+func (s *sanitizer) isAttributeValueSafe(attr attribute) bool {
+	if _, isUrl := urlAttributes[attr.name]; isUrl {
+		return isUrlSchemeAllowed(s.conf, attr.value)
+	}
+	// srcset is a list of candidates, `url [descriptor], ...`
+	if attr.name == "srcset" {
+		for candidate := range strings.SplitSeq(attr.value, ",") {
+			if fields := strings.Fields(candidate); len(fields) > 0 &&
+				!isUrlSchemeAllowed(s.conf, fields[0]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isAsciiLetter returns true if the byte is an ascii letter.
+func isAsciiLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
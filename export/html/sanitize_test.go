@@ -0,0 +1,104 @@
+package html
+
+import (
+	"testing"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestSanitizeHtml tests the raw html sanitizer with the default policy
+func TestSanitizeHtml(t *testing.T) {
+	conf := &alpha.DarknessConfig{}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "hello & world", "hello & world"},
+		{"allowed tags", `<p class="x">hi</p>`, `<p class="x">hi</p>`},
+		{"script dropped with contents", `a<script>alert(1)</script>b`, "ab"},
+		{"uppercase script", `a<SCRIPT src="x.js"></SCRIPT>b`, "ab"},
+		{"unknown tag keeps text", `<blink>hi</blink>`, "hi"},
+		{"event handlers", `<img src="a.png" onerror="alert(1)">`, `<img src="a.png">`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{"obfuscated scheme", `<a href="java&#x09;script:alert(1)">x</a>`, `<a>x</a>`},
+		{"relative href", `<a href="/blog/?a=1&amp;b=2">x</a>`, `<a href="/blog/?a=1&amp;b=2">x</a>`},
+		{"style attribute", `<span style="color:red">x</span>`, `<span>x</span>`},
+		{"data attributes", `<div data-id='5'>x</div>`, `<div data-id="5">x</div>`},
+		{"unclosed tags", `<div><p>x`, `<div><p>x</p></div>`},
+		{"stray closing tags", `x</div></p>`, `x`},
+		{"comments", `a<!-- <script>alert(1)</script> -->b`, "ab"},
+		{"lone bracket", `1 < 2`, `1 &lt; 2`},
+		{"void tags", `<br/><hr>`, `<br><hr>`},
+		{"quoted greater than", `<img alt="a>b" src="a.png">`, `<img alt="a&gt;b" src="a.png">`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeHtml(conf, tt.input); got != tt.expected {
+				t.Errorf("sanitizeHtml(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestEscapeUrl tests that urls are escaped and bad schemes are dropped
+func TestEscapeUrl(t *testing.T) {
+	conf := &alpha.DarknessConfig{}
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://example.com/?a=1&b=2", "https://example.com/?a=1&amp;b=2"},
+		{"/relative/path", "/relative/path"},
+		{"page.html#anchor:colon", "page.html#anchor:colon"},
+		{"mailto:someone@example.com", "mailto:someone@example.com"},
+		{"javascript:alert(1)", unsafeUrlReplacement},
+		{" JavaScript:alert(1)", unsafeUrlReplacement},
+		{"data:text/html;base64,AAAA", unsafeUrlReplacement},
+		{`"><script>`, "&#34;&gt;&lt;script&gt;"},
+	}
+	for _, tt := range tests {
+		if got := escapeUrl(conf, tt.input); got != tt.expected {
+			t.Errorf("escapeUrl(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// TestSanitizeIframes tests that the iframes are only kept once they're enabled
+func TestSanitizeIframes(t *testing.T) {
+	conf := &alpha.DarknessConfig{}
+	iframe := `<iframe src="https://example.com/embed"></iframe>`
+	if got := sanitizeHtml(conf, iframe); got != "" {
+		t.Errorf("iframes should be dropped by default, got %q", got)
+	}
+	conf.Sanitize.Iframes = true
+	if got := sanitizeHtml(conf, iframe); got != iframe {
+		t.Errorf("sanitizeHtml(%q) = %q with iframes enabled", iframe, got)
+	}
+}
+
+// TestShouldSanitize tests that only the trusted directories and their
+// subdirectories skip the sanitizer
+func TestShouldSanitize(t *testing.T) {
+	conf := &alpha.DarknessConfig{}
+	conf.Sanitize.Enable = true
+	conf.Sanitize.Trusted = []yunyun.RelativePathDir{"blog"}
+	tests := []struct {
+		location yunyun.RelativePathDir
+		unsafe   bool
+		expected bool
+	}{
+		{"blog", false, false},
+		{"blog/post", false, false},
+		{"blogroll", false, true},
+		{"blog-guests/post", false, true},
+		{"notes", false, true},
+		{"notes", true, false},
+	}
+	for _, tt := range tests {
+		if got := conf.Sanitize.ShouldSanitize(tt.location, tt.unsafe); got != tt.expected {
+			t.Errorf("ShouldSanitize(%q, %v) = %v, expected %v", tt.location, tt.unsafe, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package html
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+var (
+	//go:embed search.js
+	searchScript string
+)
+
+// search builds the search box, which loads the index shards listed in the
+// manifest and looks them up right in the browser.
+func (e *state) search(_ *yunyun.Content) string {
+	return fmt.Sprintf(`<div class="search" data-manifest="%s" data-empty="%s">
+<input type="search" class="search-input" placeholder="%s" aria-label="%s">
+<select class="search-section" aria-label="%s"><option value="">%s</option></select>
+<div class="search-results"></div>
+</div>
+<script>%s</script>`,
+		escapeUrl(e.conf, string(e.conf.Runtime.Join(e.conf.Search.ManifestPath()))),
+		escapeAttr(e.text(beatrice.SearchNothingFound)),
+		escapeAttr(e.text(beatrice.SearchPlaceholder)),
+		escapeAttr(e.text(beatrice.Search)),
+		escapeAttr(e.text(beatrice.SearchSection)),
+		escapeText(e.text(beatrice.SearchEverywhere)),
+		searchScript,
+	)
+}
@@ -0,0 +1,117 @@
+(() => {
+  const root = document.currentScript.previousElementSibling;
+  const input = root.querySelector(".search-input");
+  const section = root.querySelector(".search-section");
+  const results = root.querySelector(".search-results");
+  const manifestUrl = root.dataset.manifest;
+  const shards = new Map();
+  let manifest = null;
+
+  const isCJK = (c) => /[\p{Script=Han}\p{Script=Hiragana}\p{Script=Katakana}\p{Script=Hangul}]/u.test(c);
+  const words = (text) => {
+    const found = [];
+    let word = "";
+    for (const c of text.toLowerCase()) {
+      if (isCJK(c)) {
+        if (word) found.push(word);
+        found.push(c);
+        word = "";
+      } else if (/[\p{L}\p{N}\p{M}]/u.test(c)) {
+        word += c;
+      } else if (c !== "'" && c !== "’") {
+        if (word) found.push(word);
+        word = "";
+      }
+    }
+    if (word) found.push(word);
+    return found;
+  };
+
+  const loadManifest = () => {
+    if (!manifest) {
+      manifest = fetch(manifestUrl)
+        .then((r) => r.json())
+        .then((loaded) => {
+          for (const shard of loaded.shards) {
+            const option = document.createElement("option");
+            option.value = shard.name;
+            option.textContent = shard.name;
+            section.appendChild(option);
+          }
+          return loaded;
+        });
+    }
+    return manifest;
+  };
+
+  const loadShard = (shard) => {
+    if (!shards.has(shard.name)) {
+      shards.set(shard.name, fetch(shard.url).then((r) => r.json()));
+    }
+    return shards.get(shard.name);
+  };
+
+  const searchShard = (shard, query) => {
+    let scores = null;
+    for (const word of query) {
+      const matched = new Map();
+      for (const term in shard.terms) {
+        if (!term.startsWith(word)) continue;
+        for (const [doc, score] of shard.terms[term]) {
+          matched.set(doc, (matched.get(doc) || 0) + (term === word ? score * 2 : score));
+        }
+      }
+      if (scores === null) {
+        scores = matched;
+        continue;
+      }
+      for (const doc of scores.keys()) {
+        if (!matched.has(doc)) scores.delete(doc);
+        else scores.set(doc, scores.get(doc) + matched.get(doc));
+      }
+    }
+    return [...(scores || new Map())].map(([doc, score]) => ({ doc: shard.documents[doc], score }));
+  };
+
+  const render = (found) => {
+    results.replaceChildren();
+    if (found.length === 0) {
+      const empty = document.createElement("p");
+      empty.className = "search-empty";
+      empty.textContent = root.dataset.empty;
+      results.appendChild(empty);
+      return;
+    }
+    for (const { doc } of found.slice(0, 50)) {
+      const item = document.createElement("div");
+      item.className = "search-result";
+      const link = document.createElement("a");
+      link.href = doc.url;
+      link.textContent = doc.title;
+      const text = document.createElement("p");
+      text.textContent = doc.text;
+      item.append(link, text);
+      results.appendChild(item);
+    }
+  };
+
+  let latest = 0;
+  const run = async () => {
+    const current = ++latest;
+    const query = words(input.value).filter((w) => w.length > 1 || isCJK(w));
+    if (query.length === 0) {
+      results.replaceChildren();
+      return;
+    }
+    const { shards: all } = await loadManifest();
+    const picked = all.filter((s) => !section.value || s.name === section.value);
+    const found = (await Promise.all(picked.map(loadShard))).flatMap((s) => searchShard(s, query));
+    if (current !== latest) return;
+    found.sort((a, b) => b.score - a.score || a.doc.url.localeCompare(b.doc.url));
+    render(found);
+  };
+
+  input.addEventListener("input", run);
+  section.addEventListener("change", run);
+  input.addEventListener("focus", loadManifest, { once: true });
+})();
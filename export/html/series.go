@@ -0,0 +1,62 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// seriesBox builds the box listing all the parts of the named series,
+// goes right before the contents.
+func (e *state) seriesBox() string {
+	series := e.page.Series
+	if !series.IsNamed() {
+		return ""
+	}
+	items := make([]string, len(series.Parts))
+	for i, part := range series.Parts {
+		if i == series.Current {
+			items[i] = fmt.Sprintf(`<li class="series-current">%s</li>`, processTitle(part.Title))
+			continue
+		}
+		items[i] = fmt.Sprintf(`<li><a href="%s">%s</a></li>`, e.pageUrl(part), processTitle(part.Title))
+	}
+	return fmt.Sprintf(`
+<div class="writing series">
+<h4 class="series-title">%s</h4>
+<ol>
+%s
+</ol>
+</div>
+`, escapeText(e.text(beatrice.SeriesPart, series.Current+1, len(series.Parts), series.Name)), strings.Join(items, "\n"))
+}
+
+// seriesNav builds the links to the previous and the next parts of the
+// series, goes right after the contents.
+func (e *state) seriesNav() string {
+	prev, next := e.page.Series.Prev(), e.page.Series.Next()
+	if prev == nil && next == nil {
+		return ""
+	}
+	links := make([]string, 0, 2)
+	if prev != nil {
+		links = append(links, fmt.Sprintf(`<a href="%s" rel="prev" class="series-prev">← %s</a>`,
+			e.pageUrl(prev), processTitle(prev.Title)))
+	}
+	if next != nil {
+		links = append(links, fmt.Sprintf(`<a href="%s" rel="next" class="series-next">%s →</a>`,
+			e.pageUrl(next), processTitle(next.Title)))
+	}
+	return fmt.Sprintf(`
+<nav class="writing series-nav">
+%s
+</nav>
+`, strings.Join(links, "\n"))
+}
+
+// pageUrl returns the escaped url of the page.
+func (e *state) pageUrl(page *yunyun.SitePage) string {
+	return escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(page.Location)))
+}
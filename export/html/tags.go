@@ -0,0 +1,59 @@
+package html
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// tagCloudSizes is the number of different tag sizes in the cloud.
+const tagCloudSizes = 5
+
+// tagCloud builds the list of all the tags, sized by how often they're used.
+func (e *state) tagCloud(content *yunyun.Content) string {
+	if len(content.TagCloud) < 1 {
+		return `<div class="tag-cloud tag-cloud-empty"></div>`
+	}
+	mostUsed := 1
+	for _, tag := range content.TagCloud {
+		mostUsed = max(mostUsed, tag.Count)
+	}
+	tags := make([]string, len(content.TagCloud))
+	for i, tag := range content.TagCloud {
+		tags[i] = fmt.Sprintf(
+			`<a href="%s" class="tag tag-size-%d">%s <span class="tag-count">%d</span></a>`,
+			e.tagUrl(tag.Slug), tagCloudSize(tag.Count, mostUsed), escapeText(tag.Name), tag.Count,
+		)
+	}
+	return fmt.Sprintf("<div class=\"tag-cloud\">\n%s\n</div>", strings.Join(tags, "\n"))
+}
+
+// tagCloudSize returns the size of the tag from 1 to `tagCloudSizes` on a
+// logarithmic scale, so a few popular tags don't make the rest tiny.
+func tagCloudSize(count, mostUsed int) int {
+	if mostUsed <= 1 || count <= 1 {
+		return 1
+	}
+	return 1 + int(math.Round(float64(tagCloudSizes-1)*math.Log(float64(count))/math.Log(float64(mostUsed))))
+}
+
+// pageTags returns the links to the page's tags for the header, empty
+// if the page has no tags or tag pages are disabled.
+func (e *state) pageTags() string {
+	if !e.conf.Tags.Enable || len(e.page.Tags) < 1 {
+		return ""
+	}
+	tags := make([]string, 0, len(e.page.Tags))
+	for _, tag := range e.page.Tags {
+		tags = append(tags, fmt.Sprintf(`<a href="%s" class="tag">%s</a>`,
+			e.tagUrl(yunyun.TagSlug(tag)), escapeText(tag)))
+	}
+	return "\n<div class=\"page-tags\">" + strings.Join(tags, " ") + "</div>"
+}
+
+// tagUrl returns the escaped url of the tag's page.
+func (e *state) tagUrl(slug string) string {
+	return escapeUrl(e.conf, string(e.conf.Runtime.JoinDir(e.conf.Tags.TagLocation(slug))))
+}
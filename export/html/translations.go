@@ -0,0 +1,66 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/beatrice"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// language returns the language of the page, or the site's default.
+func (e *state) language() string {
+	if len(e.page.Language) > 0 {
+		return e.page.Language
+	}
+	return e.conf.Website.Language()
+}
+
+// text returns the message in the page's language.
+func (e *state) text(key beatrice.Key, args ...any) string {
+	return e.conf.Runtime.Messages.Text(e.language(), key, args...)
+}
+
+// hreflangTags returns the alternate links to all the translations of the
+// page, including itself, and the default language as the `x-default`.
+func (e *state) hreflangTags() []string {
+	if len(e.page.Translations) < 2 {
+		return nil
+	}
+	tags := make([]string, 0, len(e.page.Translations)+1)
+	for _, translation := range e.page.Translations {
+		tags = append(tags, hreflangTag(translation.Language, e.pageUrl(translation)))
+	}
+	for _, translation := range e.page.Translations {
+		if translation.Language == e.conf.Website.Language() {
+			tags = append(tags, hreflangTag("x-default", e.pageUrl(translation)))
+			break
+		}
+	}
+	return tags
+}
+
+// hreflangTag returns `<link rel="alternate" hreflang="..." href="...">`.
+func hreflangTag(language, href string) string {
+	return fmt.Sprintf(`<link rel="alternate" hreflang="%s" href="%s"/>`, escapeAttr(language), href)
+}
+
+// languageSwitcher builds the links to the page in its other languages,
+// goes in the header.
+func (e *state) languageSwitcher() string {
+	if len(e.page.Translations) < 2 {
+		return ""
+	}
+	links := make([]string, len(e.page.Translations))
+	for i, translation := range e.page.Translations {
+		name := escapeText(yunyun.LanguageName(translation.Language))
+		if translation.Location == e.page.Location {
+			links[i] = fmt.Sprintf(`<span class="language-current" lang="%s">%s</span>`,
+				escapeAttr(translation.Language), name)
+			continue
+		}
+		links[i] = fmt.Sprintf(`<a href="%s" hreflang="%s" lang="%s">%s</a>`,
+			e.pageUrl(translation), escapeAttr(translation.Language), escapeAttr(translation.Language), name)
+	}
+	return "\n<div class=\"language-switcher\">" + strings.Join(links, " | ") + "</div>"
+}
@@ -34,6 +34,9 @@ var divTypes = []divType{
 	divOutside, // yunyun.TypeTable
 	divWriting, // yunyun.TypeDetails
 	divWriting, // yunyun.TypeTableOfContents (since it's just a list).
+	divWriting, // yunyun.TypeListing
+	divWriting, // yunyun.TypeTagCloud
+	divWriting, // yunyun.TypeSearch
 }
 
 func whatDivType(content *yunyun.Content) divType {
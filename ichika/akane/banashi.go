@@ -1,22 +1,59 @@
 package akane
 
 import (
+	"context"
+	"sync"
+
 	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
 )
 
-// Do starts going through the requests and processes them.
-func Do(conf *alpha.DarknessConfig) {
-	logger.Info("Starting to process requests...")
+// requests are the post-processing requests of a site's build.
+type requests struct {
+	mutex sync.Mutex
+
+	// pagePreviews are the page previews to generate by their locations.
+	pagePreviews map[yunyun.RelativePathDir]pagePreviewRequest
+
+	// galleryVendors are the gallery images to download.
+	galleryVendors []galleryVendorRequest
+}
+
+// queueKey is the key of the requests in the build state of the site.
+type queueKey struct{}
+
+// queue returns the requests of the site's build, which have to be locked.
+func queue(conf *alpha.DarknessConfig) *requests {
+	return conf.Runtime.State.Load(queueKey{}, func() any {
+		return &requests{
+			pagePreviews: make(map[yunyun.RelativePathDir]pagePreviewRequest),
+		}
+	}).(*requests)
+}
+
+// Do takes the requests of the site and processes them, unless the post-processing
+// is turned off, which only drops them. Once the context is done, the requests not
+// started yet are dropped too.
+func Do(ctx context.Context, conf *alpha.DarknessConfig) {
+	taken, found := conf.Runtime.State.Take(queueKey{})
+	if !found || conf.Runtime.Akaneless {
+		return
+	}
+	q := taken.(*requests)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	conf.Runtime.Logger.Info("Starting to process requests...")
 
-	if pagePreviewsToGenerateCount.Load() > 0 {
+	if len(q.pagePreviews) > 0 {
 		// Do page previews generation.
-		logger.Info("Generating page previews...", "page_previews", pagePreviewsToGenerateCount.Load())
-		doPagePreviews(conf)
+		conf.Runtime.Logger.Info("Generating page previews...", "page_previews", len(q.pagePreviews))
+		doPagePreviews(ctx, conf, q.pagePreviews)
 	}
 
 	if conf.Runtime.VendorGalleries {
 		// Do the gallery vendoring.
-		logger.Info("Generating gallery vendors...", "gallery_vendors", len(galleryVendorsToDownload))
-		doGalleryVendors(conf)
+		conf.Runtime.Logger.Info("Generating gallery vendors...", "gallery_vendors", len(q.galleryVendors))
+		doGalleryVendors(ctx, conf, q.galleryVendors)
 	}
 }
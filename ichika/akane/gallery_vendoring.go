@@ -1,41 +1,45 @@
 package akane
 
 import (
+	"context"
+
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/rem"
+	"github.com/thecsw/darkness/v3/yunyun"
 )
 
 // galleryVendorRequest is a request to download a gallery vendor.
 type galleryVendorRequest struct {
-	Item rem.GalleryItem
+	// Input is the page with the gallery.
+	Input yunyun.RelativePathFile
+	Item  rem.GalleryItem
 }
 
-// galleryVendorsToDownload is a list of vendors to download.
-var galleryVendorsToDownload = make([]galleryVendorRequest, 0, 16)
-
-// RequestGalleryVendor adds a gallery vendor to the list of vendors to download.
-func RequestGalleryVendor(item rem.GalleryItem) {
-	galleryVendorsToDownload = append(galleryVendorsToDownload, galleryVendorRequest{
-		Item: item,
+// RequestGalleryVendor adds a gallery vendor of the input page to the list of vendors to download.
+func RequestGalleryVendor(conf *alpha.DarknessConfig, input yunyun.RelativePathFile, item rem.GalleryItem) {
+	q := queue(conf)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.galleryVendors = append(q.galleryVendors, galleryVendorRequest{
+		Input: input,
+		Item:  item,
 	})
 }
 
 // Go through gallery requests and download the images.
-func doGalleryVendors(conf *alpha.DarknessConfig) {
-	// Clear the request list when we're done.
-	defer func() {
-		galleryVendorsToDownload = galleryVendorsToDownload[:0]
-	}()
-
+func doGalleryVendors(ctx context.Context, conf *alpha.DarknessConfig, galleryVendors []galleryVendorRequest) {
 	// Go through each gallery vendor request.
-	for _, galleryVendorRequestItem := range galleryVendorsToDownload {
+	for _, galleryVendorRequestItem := range galleryVendors {
+		if ctx.Err() != nil {
+			return
+		}
 		item := galleryVendorRequestItem.Item
-		path, downloaded := rem.GalleryVendorItem(conf, item)
+		path, downloaded := rem.GalleryVendorItem(ctx, conf, galleryVendorRequestItem.Input, item)
 		if downloaded {
 			// // Clear the progressbar.
 			// fmt.Print("\r\033[2K")
 			// Log the thing.
-			logger.Info("Vendored item", "path", conf.Runtime.Rel(path), "dir", item.Path)
+			conf.Runtime.Logger.Info("Vendored item", "path", conf.Runtime.Rel(path), "dir", item.Path)
 		}
 	}
 }
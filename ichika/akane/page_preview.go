@@ -1,7 +1,9 @@
 package akane
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -11,9 +13,10 @@ import (
 	"unicode"
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
 	"github.com/thecsw/darkness/v3/emilia/puck"
 	"github.com/thecsw/darkness/v3/emilia/reze"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/komi"
 	"github.com/thecsw/rei"
@@ -21,6 +24,8 @@ import (
 
 // pagePreviewRequest is a request to generate a page preview.
 type pagePreviewRequest struct {
+	// Inputs are the pages at the location, which own the preview.
+	Inputs   []yunyun.RelativePathFile
 	Location yunyun.RelativePathDir
 	Title    string
 	Time     string
@@ -28,40 +33,37 @@ type pagePreviewRequest struct {
 	ColorFg  string
 }
 
-// pagePreviewsToGenerate is a set of page previews to generate.
-var (
-	pagePreviewsToGenerate      = sync.Map{}
-	pagePreviewsToGenerateCount = atomic.Uint32{}
-)
-
-// RequestPagePreview requests a page preview to be generated.
-func RequestPagePreview(location yunyun.RelativePathDir, title string,
-	time string, colorBg string, colorFg string) {
-	pagePreviewsToGenerate.Store(location, pagePreviewRequest{
+// RequestPagePreview requests a page preview to be generated for the input page.
+func RequestPagePreview(conf *alpha.DarknessConfig, input yunyun.RelativePathFile, location yunyun.RelativePathDir,
+	title string, time string, colorBg string, colorFg string) {
+	q := queue(conf)
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.pagePreviews[location] = pagePreviewRequest{
+		Inputs:   append(q.pagePreviews[location].Inputs, input),
 		Location: location,
 		Title:    title,
 		Time:     time,
 		ColorBg:  colorBg,
 		ColorFg:  colorFg,
-	})
-	pagePreviewsToGenerateCount.Add(1)
+	}
 }
 
 const (
 	pagePreviewWidth  = puck.PagePreviewWidth
 	pagePreviewHeight = puck.PagePreviewHeight
 
-	pagePreviewFilename = "preview.jpg"
+	pagePreviewFilename = puck.PagePreviewFilename
 )
 
 // doPagePreviews generates page previews.
-func doPagePreviews(conf *alpha.DarknessConfig) {
+func doPagePreviews(ctx context.Context, conf *alpha.DarknessConfig, pagePreviews map[yunyun.RelativePathDir]pagePreviewRequest) {
 	if !checkFontFiles(conf) {
-		logger.Error("Preview generation skipped, missing font files")
+		conf.Runtime.Logger.Error("Preview generation skipped, missing font files")
 		return
 	}
 	// Let's initialize the page preview generator.
-	generator := reze.InitPreviewGenerator(
+	generator, err := reze.InitPreviewGenerator(
 		string(conf.Website.PreviewGenTitleFont),
 		string(conf.Website.PreviewGenNameFont),
 		string(conf.Website.PrevietGenTimeFont),
@@ -70,27 +72,43 @@ func doPagePreviews(conf *alpha.DarknessConfig) {
 		conf.Website.Color,
 		string(conf.Author.Image),
 	)
+	if err != nil {
+		conf.Runtime.Logger.Error("Preview generation skipped, couldn't prepare the avatar", "err", err)
+		conf.Runtime.Problems.Warn(hitagi.MissingImages, string(conf.Author.Image), "page previews skipped: %v", err)
+		return
+	}
 	// Let's make sure we close the generator when we're done.
 	defer func(generator reze.PreviewGenerator) {
 		err := generator.Close()
 		if err != nil {
-			logger.Error("Closing reze page preview generator", "err", err)
+			conf.Runtime.Logger.Error("Closing reze page preview generator", "err", err)
 		}
 	}(generator)
 
 	waiting := sync.WaitGroup{}
-	waiting.Add(int(pagePreviewsToGenerateCount.Load()))
+	waiting.Add(len(pagePreviews))
 	skipped := atomic.Int32{}
 
 	processPagePreviewRequest := func(pagePreview pagePreviewRequest) {
+		// Drop the previews not started yet if the build is stopping.
+		if ctx.Err() != nil {
+			waiting.Done()
+			return
+		}
 		start := time.Now()
 
 		// Find the path to save the preview to.
-		relativeTarget := yunyun.RelativePathFile(filepath.Join(string(pagePreview.Location), pagePreviewFilename))
+		relativeTarget := yunyun.RelativePathFile(filepath.Join(string(pagePreview.Location), string(pagePreviewFilename)))
 
-		// Skip if exists, unless forced.
-		if !kuroko.Force {
-			if exists, _ := rei.FileExists(string(relativeTarget)); exists {
+		// Get it with the output directory, as it's a part of the built site.
+		target := conf.Runtime.OutputDir.Join(relativeTarget)
+
+		// Skip if exists, unless forced, but still keep it in the manifest.
+		if !conf.Runtime.Force {
+			if exists, _ := rei.FileExists(string(target)); exists {
+				if hash, err := uiharu.HashFile(target); err == nil {
+					recordPagePreview(conf, pagePreview, target, hash, false)
+				}
 				skipped.Add(1)
 				waiting.Done()
 				return
@@ -99,22 +117,28 @@ func doPagePreviews(conf *alpha.DarknessConfig) {
 
 		// Get the reader for the generated preview.
 		titleP, nameP, timeP := removeNonPrintables(pagePreview.Title, conf.Title, pagePreview.Time)
-		reader := rei.Must(generator.Generate(titleP, nameP, timeP, pagePreview.ColorBg, pagePreview.ColorFg))
-
-		// Get it with the work directory.
-		target := conf.Runtime.WorkDir.Join(relativeTarget)
+		reader, err := generator.Generate(titleP, nameP, timeP, pagePreview.ColorBg, pagePreview.ColorFg)
+		if err != nil {
+			conf.Runtime.Logger.Error("Generating page preview", "loc", target, "err", err)
+			conf.Runtime.Problems.Fail(string(conf.Runtime.WorkDir.Rel(target)), fmt.Errorf("generating page preview: %w", err))
+			waiting.Done()
+			return
+		}
 
 		// Save the preview as a jpg.
-		if err := reze.SaveJpg(reader, string(target)); err != nil {
-			logger.Error(
+		hash, written, err := reze.SaveJpg(reader, string(target))
+		if err != nil {
+			conf.Runtime.Logger.Error(
 				"Saving page preview",
 				"loc", target,
 				"err", err,
 			)
+			conf.Runtime.Problems.Fail(string(conf.Runtime.WorkDir.Rel(target)), fmt.Errorf("saving page preview: %w", err))
 			waiting.Done() // Ensure waiting.Done() is called before returning
 			return
 		}
-		logger.Info(
+		recordPagePreview(conf, pagePreview, target, hash, written)
+		conf.Runtime.Logger.Info(
 			"Generated page preview",
 			"loc", conf.Runtime.WorkDir.Rel(target),
 			"elapsed", time.Since(start),
@@ -127,10 +151,9 @@ func doPagePreviews(conf *alpha.DarknessConfig) {
 		Laborers: runtime.NumCPU(),
 	})
 
-	pagePreviewsToGenerate.Range(func(key, value any) bool {
-		rei.Try(pageGeneratorPool.Submit(value.(pagePreviewRequest)))
-		return true
-	})
+	for _, pagePreview := range pagePreviews {
+		rei.Try(pageGeneratorPool.Submit(pagePreview))
+	}
 
 	waiting.Wait()
 
@@ -139,7 +162,16 @@ func doPagePreviews(conf *alpha.DarknessConfig) {
 
 	// Write a notice if we skipped any preview generations.
 	if numSkipped := skipped.Load(); numSkipped > 0 {
-		logger.Warn("Some previews already existed, use -force to overwrite", "skipped", numSkipped)
+		conf.Runtime.Logger.Warn("Some previews already existed, use -force to overwrite", "skipped", numSkipped)
+	}
+}
+
+// recordPagePreview records the preview in the manifest for every page it belongs
+// to, so it's removed with the last of them.
+func recordPagePreview(conf *alpha.DarknessConfig, pagePreview pagePreviewRequest,
+	target yunyun.FullPathFile, hash string, written bool) {
+	for _, input := range pagePreview.Inputs {
+		conf.Runtime.Manifest.Record(input, conf.Runtime.WorkDir.Rel(target), hash, written)
 	}
 }
 
@@ -157,20 +189,22 @@ func onlyKeepPrint(k string) string {
 	return result.String()
 }
 
+// checkFontFiles returns true if all the preview fonts are there, and
+// warns about the ones that are missing.
 func checkFontFiles(conf *alpha.DarknessConfig) bool {
-	if err := checkFontFile(conf.Website.PreviewGenTitleFont); err != nil {
-		logger.Error("importing font file, skipping preview generation", "err", err)
-		return false
-	}
-	if err := checkFontFile(conf.Website.PreviewGenNameFont); err != nil {
-		logger.Error("importing font file, skipping preview generation", "err", err)
-		return false
-	}
-	if err := checkFontFile(conf.Website.PrevietGenTimeFont); err != nil {
-		logger.Error("importing font file, skipping preview generation", "err", err)
-		return false
+	found := true
+	for _, font := range []yunyun.RelativePathFile{
+		conf.Website.PreviewGenTitleFont,
+		conf.Website.PreviewGenNameFont,
+		conf.Website.PrevietGenTimeFont,
+	} {
+		if err := checkFontFile(font); err != nil {
+			conf.Runtime.Logger.Error("importing font file, skipping preview generation", "err", err)
+			conf.Runtime.Problems.Warn(hitagi.MissingFonts, string(font), "page previews skipped: %v", err)
+			found = false
+		}
 	}
-	return true
+	return found
 }
 
 func checkFontFile(path yunyun.RelativePathFile) error {
@@ -1,143 +1,779 @@
 package ichika
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"slices"
+	"sort"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
 	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/export"
 	"github.com/thecsw/darkness/v3/ichika/akane"
+	"github.com/thecsw/darkness/v3/ichika/chiho"
 	"github.com/thecsw/darkness/v3/ichika/himeno"
 	"github.com/thecsw/darkness/v3/ichika/hizuru"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
+	"github.com/thecsw/darkness/v3/ichika/kazuma"
 	"github.com/thecsw/darkness/v3/ichika/makima"
+	"github.com/thecsw/darkness/v3/ichika/misa"
 	"github.com/thecsw/darkness/v3/ichika/misaka"
+	"github.com/thecsw/darkness/v3/ichika/subaru"
+	"github.com/thecsw/darkness/v3/ichika/tohru"
+	"github.com/thecsw/darkness/v3/ichika/yor"
 	"github.com/thecsw/darkness/v3/parse"
+	"github.com/thecsw/darkness/v3/parse/orgmode"
 	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/gana"
 	"github.com/thecsw/komi"
 	"github.com/thecsw/rei"
 )
 
+// ErrBuildFailed is returned when the build had problems that fail it.
+var ErrBuildFailed = errors.New("build failed")
+
+// Summary is what a build did.
+type Summary struct {
+	// Pages is the number of pages exported, generated ones included.
+	Pages int64
+	// Elapsed is how long it took to build the pages.
+	Elapsed time.Duration
+	// Outputs are what the build did with its outputs.
+	Outputs uiharu.Counts
+}
+
 // BuildCommandFunc builds the entire directory.
 func BuildCommandFunc() {
 	cmd := darknessFlagset(buildCommand)
 	conf := alpha.BuildConfig(getAlphaOptions(cmd))
-	build(conf)
+
+	ctx, stop := interruptible()
+	defer stop()
+	_, err := Build(ctx, conf)
+	conf.Runtime.Problems.Write(os.Stdout)
+	if ctx.Err() != nil {
+		puck.Logger.Fatal("Build interrupted, the pages written so far are kept", "err", ctx.Err())
+	}
+	if err != nil {
+		puck.Logger.Fatal("Build failed, see the problems above", "err", err)
+	}
 	fmt.Println("farewell")
 }
 
-// build uses set flags and emilia data to build the local directory.
-func build(conf *alpha.DarknessConfig) {
-	parser := parse.BuildParser(conf)
-	exporter := export.BuildExporter(conf)
+// interruptible returns a context that is done on the first Ctrl-C, so that the
+// work stops cleanly, and the next Ctrl-C kills darkness right away.
+func interruptible() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	return ctx, stop
+}
+
+// Build builds the site with the hooks around it, the same way `darkness build`
+// does. The problems are left in the config's report, and if any of them fail
+// the build, ErrBuildFailed is returned with the summary of what was done. Once
+// the context is done, the build stops and returns its error.
+func Build(ctx context.Context, conf *alpha.DarknessConfig) (*Summary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Don't keep what the build remembered once it's done.
+	defer func() { conf.Runtime.State.Reset() }()
+
+	// Let the hooks prepare the pages before we go looking for them.
+	if err := yor.Run(ctx, conf, yor.PreBuild, conf.Hooks.PreBuild); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		conf.Runtime.Logger.Error("Running the pre-build hooks", "err", err)
+		conf.Runtime.Problems.Fail("", err)
+	}
+	b, err := build(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a good build gets minified or deployed.
+	if !conf.Runtime.Problems.Failed() && ctx.Err() == nil {
+		if err := yor.Run(ctx, conf, yor.PostBuild, conf.Hooks.PostBuild); err != nil {
+			if ctx.Err() != nil {
+				return &b.summary, ctx.Err()
+			}
+			conf.Runtime.Logger.Error("Running the post-build hooks", "err", err)
+			conf.Runtime.Problems.Fail("", err)
+		}
+	}
+	if conf.Runtime.Problems.Failed() {
+		return &b.summary, ErrBuildFailed
+	}
+	return &b.summary, ctx.Err()
+}
+
+// builder remembers the sources, summaries, and dependencies of the pages
+// between builds, so that the dev server can rebuild only what changed.
+type builder struct {
+	// conf is the configuration for the site.
+	conf *alpha.DarknessConfig
+	// parser is the parser to use for the site.
+	parser parse.Parser
+	// exporter is the exporter to use for the site.
+	exporter export.Exporter
+	// sources are the contents of the input files.
+	sources map[yunyun.RelativePathFile]string
+	// summaries are the summaries of the pages before they get weighed into a site.
+	summaries map[yunyun.RelativePathFile]*yunyun.SitePage
+	// graph is what the pages pulled in and which other pages they show.
+	graph *subaru.Graph
+	// generated are the pages that darkness generated in the last export.
+	generated map[yunyun.RelativePathFile]struct{}
+	// weighed are the weighed terms of the pages in the last site, which only
+	// change if any page came, went, or changed its terms.
+	weighed map[yunyun.RelativePathFile]yunyun.Terms
+	// modified are when the sources were last committed, read from the whole
+	// git history once and then only for the pages parsed again, nil if not read.
+	modified map[yunyun.RelativePathFile]time.Time
+	// assets are the static files the pages use, if copied into the output directory.
+	assets *tohru.Assets
+	// summary is what the last full build did.
+	summary Summary
+}
+
+// newBuilder returns a builder that doesn't remember anything yet.
+func newBuilder(conf *alpha.DarknessConfig) (*builder, error) {
+	if err := chiho.CheckEnrichment(conf); err != nil {
+		return nil, fmt.Errorf("checking the enrichment steps: %w", err)
+	}
+	return &builder{
+		conf:      conf,
+		parser:    parse.BuildParser(conf),
+		exporter:  export.BuildExporter(conf),
+		sources:   make(map[yunyun.RelativePathFile]string),
+		summaries: make(map[yunyun.RelativePathFile]*yunyun.SitePage),
+		graph:     subaru.NewGraph(),
+		generated: make(map[yunyun.RelativePathFile]struct{}),
+		assets:    tohru.NewAssets(conf),
+	}, nil
+}
 
-	// Let's complete the akane requests when done building.
-	if !kuroko.Akaneless {
-		defer akane.Do(conf)
+// build uses set flags and emilia data to build the local directory, the
+// returned builder can then rebuild the parts of the site that change.
+//
+// The pools are connected between each other, so the relationship is as follows,
+//
+//	          Reading 📚                      Parsing 🧹
+//	  path  ┌───────────┐   file handler   ┌─────────────┐
+//	──────> │ filesPool │ ───────────────> │  parserPool │ ──────────────┐
+//	        └───────────┘                  └─────────────┘               │
+//	         log errors                     gathers pages                │  all the
+//	                                                                     │   pages
+//	                                                                     │
+//	  file  ┌────────────┐  exported data  ┌──────────────┐               │
+//	 <───── │ writerPool │ <────────────── │ exporterPool │ <─────────────┘
+//	        └────────────┘                 └──────────────┘
+//	          Writing 🎸                     Exporting 🥂
+//
+// Once the context is done, the pages not written yet are dropped and the build
+// returns the context's error, without removing the orphans.
+func build(ctx context.Context, conf *alpha.DarknessConfig) (*builder, error) {
+	b, err := newBuilder(conf)
+	if err != nil {
+		return nil, err
 	}
 
+	// Start with nothing remembered from the builds before.
+	conf.Runtime.State = &alpha.BuildState{}
+
 	// Before we kick off the entire parsing loop, let's see if we have global macros defined.
 	himeno.RegisterGlobalMacros(conf)
 
+	// Remember what the previous build wrote, so we know what's orphaned after this one.
+	previous, err := uiharu.Load(conf.Runtime.WorkDir.Join(uiharu.ManifestFile))
+	if err != nil {
+		conf.Runtime.Logger.Warn("Couldn't load the previous build manifest", "err", err)
+	}
+	conf.Runtime.Manifest = uiharu.NewManifest()
+
+	// Find all the files that need to be parsed.
+	inputFilenames := make(chan yunyun.FullPathFile, 8)
+	go hizuru.FindFilesByExt(ctx, conf, inputFilenames)
+
+	// Record the start time.
+	start := time.Now()
+
+	// Submit all the files to the pool.
+	inputs := make(chan *makima.Control, 8)
+	go func() {
+		for inputFilename := range inputFilenames {
+			inputs <- b.control(ctx, inputFilename)
+		}
+		close(inputs)
+	}()
+
+	// Parse all the pages and summarize them before any of them get enriched,
+	// so pages can safely look at each other during exporting.
+	parsed := b.parse(inputs)
+	if ctx.Err() != nil {
+		return nil, b.stopped(ctx, previous)
+	}
+	site := b.remember(parsed)
+
+	// Now that we have every page, kick off the exporting.
+	exported, generated := b.export(ctx, site, parsed, true)
+	if ctx.Err() != nil {
+		return nil, b.stopped(ctx, previous)
+	}
+
+	// Write the sitemap if the user wants it built with the site.
+	if conf.Sitemap.Enable {
+		pages := append(gana.Map(makima.Woof.ParsedPage, parsed), generated...)
+		if err := misa.WriteSitemap(conf, pages, false); err != nil {
+			conf.Runtime.Logger.Errorf("couldn't write the sitemap: %v", err)
+			conf.Runtime.Problems.Fail(string(conf.Sitemap.Path), err)
+		}
+	}
+	b.writeSearchIndex(site)
+
+	// Now that every page is known, see where their links lead.
+	b.checkLinks(parsed, generated)
+
+	// Record the time it took to finish.
+	finish := time.Now()
+
+	// Clear the download progress bar if present by wiping out the line.
+	fmt.Fprint(conf.Runtime.Stdout, "\r\033[2K")
+
+	fmt.Fprintf(conf.Runtime.Stdout, "Processed %d files in %d ms\n", exported, finish.Sub(start).Milliseconds())
+	b.summary = Summary{Pages: exported, Elapsed: finish.Sub(start)}
+
+	// Let's process the misaka report if user wants to see it.
+	if conf.Runtime.BuildReport {
+		misaka.WriteReport(conf)
+	}
+
+	// Let's complete the akane requests, the previews it makes may need copying.
+	akane.Do(ctx, conf)
+	if ctx.Err() != nil {
+		return nil, b.stopped(ctx, previous)
+	}
+	b.copyAssets()
+
+	// Let's write the report time to a special file, last_built.txt
+	nowUtc := time.Now().UTC().Format(time.RFC3339)
+	lastBuilt := conf.Runtime.OutputDir.Join(puck.LastBuildTimestampFile)
+	hash, written, err := uiharu.WriteFile(string(lastBuilt), func(w io.Writer) error {
+		_, err := io.WriteString(w, nowUtc)
+		return err
+	})
+	if err != nil {
+		conf.Runtime.Logger.Warnf("couldn't write the last_built.txt: %v", err)
+		conf.Runtime.Problems.Fail(puck.LastBuildTimestampFile, err)
+	} else {
+		conf.Runtime.Manifest.Record(uiharu.SiteInput, conf.Runtime.WorkDir.Rel(lastBuilt), hash, written)
+	}
+
+	// Clean up after the pages that are gone and remember what we wrote.
+	b.removeOutputs(previous.Orphans(conf.Runtime.Manifest, yunyun.FullPathDir(conf.Runtime.WorkDir)))
+	b.saveManifest()
+	b.summary.Outputs = conf.Runtime.Manifest.TakeCounts()
+	fmt.Fprintf(conf.Runtime.Stdout, "Outputs: %s\n", b.summary.Outputs)
+	return b, nil
+}
+
+// rebuild builds only the pages affected by the changed files, which are relative
+// to the working directory, everything else is remembered from the previous build.
+//
+// A page is rebuilt if its source changed, if it pulled in a changed setupfile or
+// gallery image, if it shows a page whose summary changed (listings, backlinks,
+// series, translations, breadcrumbs, and related pages), or if its related pages
+// are different with the changed summaries weighed in.
+//
+// Once the context is done, the rebuild stops and returns its error. The builder
+// then goes back to the summaries it had, so rebuilding the same changed files
+// again finds the same affected pages and finishes what this rebuild started.
+func (b *builder) rebuild(ctx context.Context, changed []yunyun.RelativePathFile) error {
+	start := time.Now()
+	defer b.saveManifest()
+	before := maps.Clone(b.summaries)
+	stopped := func(err error) error {
+		b.summaries = before
+		return err
+	}
+
+	// Only count what this rebuild does with the outputs.
+	b.conf.Runtime.Manifest.TakeCounts()
+
+	// Find the pages to parse again, because either their sources or what they
+	// pulled in changed, and forget the pages that are gone.
+	reparse := make(map[yunyun.RelativePathFile]struct{})
+	removed := make([]*yunyun.SitePage, 0, 1)
+	for _, file := range changed {
+		orgmode.ForgetSetupFile(b.conf, file)
+		if b.assets.Has(file) {
+			b.conf.Runtime.Logger.Warn("A file was modified", "path", file)
+			b.copyAssets()
+			continue
+		}
+		dependents := b.graph.Dependents(file)
+		isInput := hizuru.IsInputFile(b.conf, b.conf.Runtime.WorkDir.Join(file))
+		// Nothing depends on the file, like on the files we write ourselves.
+		if len(dependents) < 1 && !isInput {
+			continue
+		}
+		b.conf.Runtime.Logger.Warn("A file was modified", "path", file)
+		for _, dependent := range dependents {
+			reparse[dependent] = struct{}{}
+		}
+		if !isInput {
+			continue
+		}
+		// Make sure the changed source is read again.
+		delete(b.sources, file)
+		if exists, _ := rei.FileExists(string(b.conf.Runtime.WorkDir.Join(file))); exists {
+			reparse[file] = struct{}{}
+			continue
+		}
+		if summary, found := b.summaries[file]; found {
+			removed = append(removed, summary)
+			delete(b.summaries, file)
+			b.graph.Forget(file)
+		}
+		b.removeOutputs(b.conf.Runtime.Manifest.Forget(file))
+	}
+	if len(reparse) < 1 && len(removed) < 1 {
+		return nil
+	}
+
+	// Parse the pages again and see whose summaries changed.
+	parsed := b.parse(b.inputs(ctx, slices.Sorted(maps.Keys(reparse))))
+	if err := ctx.Err(); err != nil {
+		return stopped(err)
+	}
+	site := b.remember(parsed)
+
+	// Pages that show the pages with changed summaries have to be exported again.
+	affected := make(map[yunyun.RelativePathFile]struct{})
+	textChanged, summariesChanged := false, len(removed) > 0
+	for _, summary := range removed {
+		b.affect(affected, site, summary, true)
+	}
+	for _, woof := range parsed {
+		file := woof.ParsedPage().File
+		previous, current := before[file], b.summaries[file]
+		if previous != nil && sameSummary(previous, current) {
+			textChanged = textChanged || !sameText(previous, current)
+			continue
+		}
+		summariesChanged = true
+		reshaped := previous == nil || !sameShape(previous, current)
+		if previous != nil {
+			b.affect(affected, site, previous, reshaped)
+		}
+		b.affect(affected, site, current, reshaped)
+	}
+	// The related pages are found by the terms weighed against the whole site, so a
+	// changed text can change the related pages of the pages that didn't show it before.
+	if summariesChanged || textChanged {
+		relating := b.graph.Relating(site, func(location yunyun.RelativePathDir) (int, bool) {
+			return chiho.RelatedCount(b.conf, location)
+		})
+		for _, file := range relating {
+			affected[file] = struct{}{}
+		}
+	}
+	for _, woof := range parsed {
+		delete(affected, woof.ParsedPage().File)
+	}
+	parsed = append(parsed, b.parse(b.inputs(ctx, slices.Sorted(maps.Keys(affected))))...)
+
+	// The sitemap only matters for the deployed site, so it waits for a full build.
+	exported, _ := b.export(ctx, site, parsed, summariesChanged)
+	if err := ctx.Err(); err != nil {
+		return stopped(err)
+	}
+	if summariesChanged || textChanged {
+		b.writeSearchIndex(site)
+	}
+	b.copyAssets()
+	fmt.Fprintf(b.conf.Runtime.Stdout, "Rebuilt %d files in %d ms (%s)\n",
+		exported, time.Since(start).Milliseconds(), b.conf.Runtime.Manifest.TakeCounts())
+	return nil
+}
+
+// removeOutputs removes the outputs of the pages that are gone, or only reports
+// them if the user wants to keep them. Outputs changed since they were written
+// are always kept, as those changes are not ours.
+func (b *builder) removeOutputs(orphans []*uiharu.Output) {
+	for _, orphan := range orphans {
+		if b.conf.Project.KeepOrphans {
+			b.conf.Runtime.Logger.Warn("Output of a removed page", "path", orphan.File)
+			continue
+		}
+		if err := orphan.Remove(yunyun.FullPathDir(b.conf.Runtime.WorkDir)); err != nil {
+			b.conf.Runtime.Logger.Warn("Keeping the output of a removed page", "path", orphan.File, "err", err)
+			continue
+		}
+		b.conf.Runtime.Manifest.Removed()
+		b.conf.Runtime.Logger.Warn("Removed the output of a removed page", "path", orphan.File)
+	}
+}
+
+// copyAssets copies the static files the pages use into the output directory.
+func (b *builder) copyAssets() {
+	copied, err := b.assets.Copy(b.conf)
+	if err != nil {
+		b.conf.Runtime.Logger.Error("Copying the static files", "err", err)
+	}
+	if copied > 0 {
+		b.conf.Runtime.Logger.Info("Copied the static files", "num", copied, "dir", b.conf.Project.OutputDirectory)
+	}
+}
+
+// stopped saves the previous manifest with what the stopped build managed to write,
+// so that the next build and clean still know those outputs, and returns why it stopped.
+func (b *builder) stopped(ctx context.Context, previous *uiharu.Manifest) error {
+	previous.Merge(b.conf.Runtime.Manifest)
+	b.conf.Runtime.Manifest = previous
+	b.saveManifest()
+	return ctx.Err()
+}
+
+// saveManifest writes the manifest of the build, so the next build and clean
+// know what was written.
+func (b *builder) saveManifest() {
+	if err := b.conf.Runtime.Manifest.Save(b.conf.Runtime.WorkDir.Join(uiharu.ManifestFile)); err != nil {
+		b.conf.Runtime.Logger.Warn("Couldn't save the build manifest", "err", err)
+	}
+}
+
+// affect adds the pages that show the summarized page to the affected ones, and if
+// the page got reshaped, also the pages that may start or stop showing it.
+func (b *builder) affect(
+	affected map[yunyun.RelativePathFile]struct{},
+	site *yunyun.Site,
+	summary *yunyun.SitePage,
+	reshaped bool,
+) {
+	add := func(pages ...*yunyun.SitePage) {
+		for _, page := range pages {
+			affected[page.File] = struct{}{}
+		}
+	}
+	for _, file := range b.graph.Showing(summary.Location) {
+		affected[file] = struct{}{}
+	}
+	if !reshaped {
+		return
+	}
+	// The graph only knows what the pages showed before, so also find the
+	// pages that will show this one now, starting with the linked ones.
+	for _, link := range summary.Links {
+		if target := site.Page(link.Target); target != nil {
+			add(target)
+		}
+	}
+	add(site.Translations(summary.Location)...)
+	add(narumi.DirectorySeries(site, yunyun.RelativePathDir(filepath.Dir(string(summary.Location))))...)
+	if len(summary.SeriesName) > 0 {
+		add(narumi.SeriesParts(site, summary.SeriesName)...)
+	}
+}
+
+// parse reads and parses the inputs, and returns the parsed pages sorted by their
+// sources. Inputs with the contents already there are not read again, and the
+// inputs whose context is done are dropped.
+func (b *builder) parse(inputs <-chan *makima.Control) []makima.Woof {
+	// Closing the pools drops the jobs still queued in them, so count the jobs
+	// ourselves and only close once every job either failed or got gathered.
+	pending := &sync.WaitGroup{}
+
 	// Create the pool that reads files and returns their handles.
-	filesPool := komi.NewWithSettings(komi.WorkWithErrors(makima.Woof.Read), &komi.Settings{
+	filesPool := komi.NewWithSettings(komi.WorkWithErrors(func(w makima.Woof) (makima.Woof, error) {
+		woof, err := w.Read()
+		if err != nil {
+			pending.Done()
+		}
+		return woof, err
+	}), &komi.Settings{
 		Name:     "Komi Reading 📚 ",
 		Laborers: runtime.NumCPU(),
-		Debug:    kuroko.DebugEnabled,
+		Debug:    b.conf.Runtime.Debug,
 	})
-	go logErrors("reading", rei.Must(filesPool.Errors()))
+	go logErrors(b.conf.Runtime.Logger, "reading", rei.Must(filesPool.Errors()))
 
-	// Create a pool that take a files handle and parses it out into yunyun pages.
-	parserPool := komi.NewWithSettings(komi.Work(makima.Woof.Parse), &komi.Settings{
+	// Create a pool that take a files handle and parses it out into yunyun pages,
+	// gathering them, so we can see the whole site before exporting any of the
+	// pages. The pages that fail to parse are dropped, so the rest of the site
+	// still builds.
+	parsed := make([]makima.Woof, 0, 64)
+	parsedMutex := &sync.Mutex{}
+	parserPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(func(w makima.Woof) error {
+		defer pending.Done()
+		woof, err := w.Parse()
+		if err != nil {
+			return err
+		}
+		parsedMutex.Lock()
+		defer parsedMutex.Unlock()
+		parsed = append(parsed, woof)
+		return nil
+	}), &komi.Settings{
 		Name:     "Komi Parsing 🧹 ",
-		Laborers: kuroko.CustomNumWorkers,
-		Debug:    kuroko.DebugEnabled,
+		Laborers: b.conf.Runtime.Workers,
+		Debug:    b.conf.Runtime.Debug,
 	})
+	go logErrors(b.conf.Runtime.Logger, "parsing", rei.Must(parserPool.Errors()))
+
+	// Only chain two pools, as the connector of a pool watches the one it's
+	// connected to without locking, which races with that one's own connector.
+	rei.Try(filesPool.Connect(parserPool))
+
+	for input := range inputs {
+		pending.Add(1)
+		rei.Try(filesPool.Submit(input))
+	}
+
+	// Wait for all the pages to be parsed.
+	pending.Wait()
+	parserPool.Close()
+
+	// Keep the order stable, so that site-wide generation is deterministic.
+	sort.Slice(parsed, func(i, j int) bool {
+		return parsed[i].ParsedPage().File < parsed[j].ParsedPage().File
+	})
+	return parsed
+}
+
+// inputs returns the controls of the input files, which are relative to
+// the working directory, with the remembered sources filled in.
+func (b *builder) inputs(ctx context.Context, files []yunyun.RelativePathFile) <-chan *makima.Control {
+	inputs := make(chan *makima.Control, len(files))
+	for _, file := range files {
+		inputs <- b.control(ctx, b.conf.Runtime.WorkDir.Join(file))
+	}
+	close(inputs)
+	return inputs
+}
+
+// remember keeps the sources, summaries, and dependencies of the parsed pages for
+// the next builds, and returns the site with all the pages the builder knows about.
+func (b *builder) remember(parsed []makima.Woof) *yunyun.Site {
+	// The terms are weighed against all the other pages, so only weigh
+	// them again if a page came, went, or changed its terms.
+	reweigh := false
+	files := make([]yunyun.RelativePathFile, 0, len(parsed))
+	for _, woof := range parsed {
+		page := woof.ParsedPage()
+		summary := chiho.SummarizePage(b.conf, page)
+		if previous := b.summaries[page.File]; previous == nil || !slices.Equal(previous.Terms, summary.Terms) {
+			reweigh = true
+		}
+		b.sources[page.File] = woof.Source()
+		b.summaries[page.File] = summary
+		b.graph.Depend(b.conf, page)
+		files = append(files, page.File)
+	}
+	reweigh = reweigh || len(b.weighed) != len(b.summaries)
+
+	// The site weighs the summaries, so give it copies to keep ours intact.
+	summaries := make([]*yunyun.SitePage, 0, len(b.summaries))
+	for _, file := range slices.Sorted(maps.Keys(b.summaries)) {
+		summaries = append(summaries, b.summaries[file].Clone())
+	}
+	site := yunyun.NewSite(summaries)
+	if reweigh {
+		narumi.WeighTerms(site)
+		b.weighed = make(map[yunyun.RelativePathFile]yunyun.Terms, len(site.Pages))
+		for _, page := range site.Pages {
+			b.weighed[page.File] = page.Terms
+		}
+	} else {
+		for _, page := range site.Pages {
+			page.Terms = b.weighed[page.File]
+		}
+	}
+
+	// Only the structured data needs the modification dates, so
+	// don't walk the git history if it's not enabled.
+	if b.conf.StructuredData.Enable {
+		b.readModified(files)
+		for _, page := range site.Pages {
+			page.Modified = b.modified[page.File]
+		}
+	}
+	return site
+}
+
+// readModified reads when the sources of the parsed pages were last committed,
+// walking the whole git history only the first time.
+func (b *builder) readModified(files []yunyun.RelativePathFile) {
+	if b.modified == nil {
+		modified, err := alpha.ExtractGitLastModifiedAll(b.conf)
+		if err != nil {
+			b.conf.Runtime.Logger.Warn("Couldn't read git history, pages won't have modification dates", "err", err)
+			modified = make(map[yunyun.RelativePathFile]time.Time)
+		}
+		b.modified = modified
+		return
+	}
+	for _, file := range files {
+		// The sources that were never committed have no modification date.
+		modified, err := alpha.ExtractGitLastModified(b.conf, file)
+		if err != nil {
+			delete(b.modified, file)
+			continue
+		}
+		b.modified[file] = modified
+	}
+}
+
+// export exports the parsed pages with the site, along with the pages that darkness
+// generates if asked, and records which other pages the exported pages show. It returns
+// the number of exported pages and the generated pages. The parsed pages are exported
+// with their own context, and the generated ones get the given one.
+func (b *builder) export(ctx context.Context, site *yunyun.Site, parsed []makima.Woof, withGenerated bool) (int64, []*yunyun.Page) {
+	// Same as with parsing, count the jobs so none get dropped on closing.
+	pending := &sync.WaitGroup{}
 
 	// Create a pool that that takes yunyun pages and exports them into request format.
-	exporterPool := komi.NewWithSettings(komi.Work(makima.Woof.Export), &komi.Settings{
+	exporterPool := komi.NewWithSettings(komi.WorkWithErrors(func(w makima.Woof) (makima.Woof, error) {
+		woof, err := w.Export()
+		if err != nil {
+			pending.Done()
+		}
+		return woof, err
+	}), &komi.Settings{
 		Name:     "Komi Exporting 🥂 ",
-		Laborers: kuroko.CustomNumWorkers,
-		Debug:    kuroko.DebugEnabled,
+		Laborers: b.conf.Runtime.Workers,
+		Debug:    b.conf.Runtime.Debug,
 	})
+	go logErrors(b.conf.Runtime.Logger, "exporting", rei.Must(exporterPool.Errors()))
 
 	// Create a pool that reads the exported data and writes them to target files.
-	writerPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(makima.Woof.Write), &komi.Settings{
+	writerPool := komi.NewWithSettings(komi.WorkSimpleWithErrors(func(w makima.Woof) error {
+		defer pending.Done()
+		return w.Write()
+	}), &komi.Settings{
 		Name:     "Komi Writing 🎸",
 		Laborers: runtime.NumCPU(),
-		Debug:    kuroko.DebugEnabled,
+		Debug:    b.conf.Runtime.Debug,
 	})
-	go logErrors("writer", rei.Must(writerPool.Errors()))
-
-	// Connect all the pools between each other, so the relationship is as follows,
-	//
-	//           Reading 📚                      Parsing 🧹
-	//   path  ┌───────────┐   file handler   ┌─────────────┐
-	// ──────> │ filesPool │ ───────────────> │  parserPool │
-	//         └───────────┘                  └─────────────┘
-	//          log errors                          │
-	//                                              │    parsed files
-	//                                              │  aka yunyun pages
-	//                                              │
-	//   file  ┌────────────┐  exported data  ┌──────────────┐
-	//  <───── │ writerPool │ <────────────── │ exporterPool │
-	//         └────────────┘              	  └──────────────┘
-	//           Writing 🎸                     Exporting 🥂
-	//
-	rei.Try(filesPool.Connect(parserPool))
-	rei.Try(parserPool.Connect(exporterPool))
+	go logErrors(b.conf.Runtime.Logger, "writer", rei.Must(writerPool.Errors()))
 	rei.Try(exporterPool.Connect(writerPool))
 
-	// Find all the files that need to be parsed.
-	inputFilenames := make(chan yunyun.FullPathFile, 8)
-	go hizuru.FindFilesByExt(conf, inputFilenames)
-
-	// Record the start time.
-	start := time.Now()
+	for _, woof := range parsed {
+		pending.Add(1)
+		rei.Try(exporterPool.Submit(woof.WithSite(site)))
+	}
 
-	// Submit all the files to the pool.
-	for inputFilename := range inputFilenames {
-		// Submit the job to the pool.
-		rei.Try(filesPool.Submit(&makima.Control{
-			Conf:          conf,
-			Parser:        parser,
-			Exporter:      exporter,
-			InputFilename: inputFilename,
-		}))
+	// Also export the pages that darkness generates, like directory listings.
+	var generated []*yunyun.Page
+	if withGenerated {
+		generated = kazuma.GeneratePages(b.conf, site)
+		b.forgetGenerated(generated)
+	}
+	for _, page := range generated {
+		control := b.control(ctx, b.conf.Runtime.WorkDir.Join(page.File))
+		control.Page, control.Site = page, site
+		pending.Add(1)
+		rei.Try(exporterPool.Submit(control))
 	}
 
 	// Wait for all the pools to finish.
+	pending.Wait()
 	writerPool.Close()
 
-	// Record the time it took to finish.
-	finish := time.Now()
-
-	// Clear the download progress bar if present by wiping out the line.
-	fmt.Print("\r\033[2K")
+	// The pages are enriched now, so we can see what they show, unless they
+	// were stopped halfway and will be exported again.
+	if ctx.Err() != nil {
+		return exporterPool.JobsSucceeded(), generated
+	}
+	for _, woof := range parsed {
+		b.graph.Show(woof.ParsedPage())
+	}
+	return exporterPool.JobsSucceeded(), generated
+}
 
-	fmt.Printf("Processed %d files in %d ms\n", exporterPool.JobsSucceeded(), finish.Sub(start).Milliseconds())
+// forgetGenerated removes the outputs of the pages that darkness generated
+// before but doesn't anymore, like the last page of a shrunk listing.
+func (b *builder) forgetGenerated(pages []*yunyun.Page) {
+	current := make(map[yunyun.RelativePathFile]struct{}, len(pages))
+	for _, page := range pages {
+		current[page.File] = struct{}{}
+	}
+	for file := range b.generated {
+		if _, found := current[file]; !found {
+			b.removeOutputs(b.conf.Runtime.Manifest.Forget(file))
+		}
+	}
+	b.generated = current
+}
 
-	// Let's process the misaka report if user wants to see it.
-	if kuroko.BuildReport {
-		misaka.WriteReport(conf)
+// control returns the control of the input file, with the remembered source filled in,
+// which stops between its stages once the context is done.
+func (b *builder) control(ctx context.Context, inputFilename yunyun.FullPathFile) *makima.Control {
+	return &makima.Control{
+		Context:       ctx,
+		Conf:          b.conf,
+		Parser:        b.parser,
+		Exporter:      b.exporter,
+		InputFilename: inputFilename,
+		Input:         b.sources[b.conf.Runtime.WorkDir.Rel(inputFilename)],
+		Assets:        b.assets,
 	}
+}
 
-	// Let's write the report time to a special file, last_built.txt
-	nowUtc := time.Now().UTC().Format(time.RFC3339)
-	if err := os.WriteFile(puck.LastBuildTimestampFile, []byte(nowUtc), 0o600); err != nil {
-		conf.Runtime.Logger.Warnf("couldn't write the last_built.txt: %v", err)
+// writeSearchIndex writes the search index if the user wants the search.
+func (b *builder) writeSearchIndex(site *yunyun.Site) {
+	if !b.conf.Search.Enable {
+		return
+	}
+	if err := misa.WriteSearchIndex(b.conf, site, false); err != nil {
+		b.conf.Runtime.Logger.Errorf("couldn't write the search index: %v", err)
+		b.conf.Runtime.Problems.Fail(string(b.conf.Search.ManifestPath()), err)
 	}
 }
 
-// logErrors is a helper function that logs errors from a pool. It is meant to be
-// used as a goroutine.
-func logErrors[T any](name string, vv chan komi.PoolError[T]) {
+// sameSummary tells us if the summaries look the same from the other pages,
+// which don't show the text of the page, only the search index needs it.
+func sameSummary(a, b *yunyun.SitePage) bool {
+	a, b = a.Clone(), b.Clone()
+	a.Terms, a.Headings, a.Text = nil, nil, ""
+	b.Terms, b.Headings, b.Text = nil, nil, ""
+	return reflect.DeepEqual(a, b)
+}
+
+// sameShape tells us if the summaries put the page in the same places of the
+// site, so that the same pages link to, translate, and continue the page.
+func sameShape(a, b *yunyun.SitePage) bool {
+	return a.Title == b.Title && a.Published.Equal(b.Published) && a.Draft == b.Draft &&
+		a.SeriesName == b.SeriesName && a.SeriesOrder == b.SeriesOrder &&
+		a.Language == b.Language && a.TranslationKey == b.TranslationKey &&
+		slices.Equal(a.Links, b.Links)
+}
+
+// sameText tells us if the summaries have the same text.
+func sameText(a, b *yunyun.SitePage) bool {
+	return a.Text == b.Text && slices.Equal(a.Headings, b.Headings)
+}
+
+// logErrors is a helper function that logs errors from a pool, other than the
+// pages dropped by a stopped build. It is meant to be used as a goroutine.
+func logErrors[T any](logger *log.Logger, name string, vv chan komi.PoolError[T]) {
 	for v := range vv {
-		if v.Error != nil {
-			puck.Logger.Error("job failed", "err", v.Error, "pool", name)
+		if v.Error != nil && !errors.Is(v.Error, context.Canceled) && !errors.Is(v.Error, context.DeadlineExceeded) {
+			logger.Error("job failed", "err", v.Error, "pool", name)
 		}
 	}
 }
@@ -1,28 +1,219 @@
 package chiho
 
 import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/narumi"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
-// EnrichPage enriches the page with the following:
-// - Resolved comments
-// - Enriched headings
-// - Footnotes
-// - Math support
-// - Source code trimmed left whitespace
-// - Syntax highlighting
-// - Lazy galleries
-func EnrichPage(conf *alpha.DarknessConfig, page *yunyun.Page) *yunyun.Page {
-	return page.Options(
-		narumi.WithDate(),
-		narumi.WithResolvedComments(),
-		narumi.WithEnrichedHeadings(),
-		narumi.WithFootnotes(),
-		narumi.WithMathSupport(),
-		narumi.WithSourceCodeTrimmedLeftWhitespace(),
-		narumi.WithSyntaxHighlighting(conf),
-		narumi.WithLazyGalleries(conf),
-	)
+// Enricher returns the page option of an enrichment step for the site, with the
+// step's options from darkness.toml, which are nil if the step has none.
+type Enricher func(conf *alpha.DarknessConfig, site *yunyun.Site, options map[string]any) yunyun.PageOption
+
+// step is an enricher with its name.
+type step struct {
+	name     string
+	enricher Enricher
+	// options are the kinds of the options that the built-in step reads by
+	// their names, the registered steps read whatever they want.
+	options map[string]reflect.Kind
+	// registered is true for the steps registered for the site.
+	registered bool
+}
+
+// builtins are the built-in enrichment steps in their default order.
+var builtins = []step{
+	{name: "date", enricher: func(conf *alpha.DarknessConfig, _ *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithDate(conf)
+	}},
+	{name: "statistics", enricher: func(conf *alpha.DarknessConfig, _ *yunyun.Site, options map[string]any) yunyun.PageOption {
+		return narumi.WithStatistics(conf, option(options, "reading_time", conf.Reading.Enable))
+	}, options: map[string]reflect.Kind{"reading_time": reflect.Bool}},
+	{name: "comments", enricher: func(*alpha.DarknessConfig, *yunyun.Site, map[string]any) yunyun.PageOption {
+		return narumi.WithResolvedComments()
+	}},
+	{name: "headings", enricher: func(*alpha.DarknessConfig, *yunyun.Site, map[string]any) yunyun.PageOption {
+		return narumi.WithEnrichedHeadings()
+	}},
+	{name: "footnotes", enricher: func(*alpha.DarknessConfig, *yunyun.Site, map[string]any) yunyun.PageOption {
+		return narumi.WithFootnotes()
+	}},
+	{name: "math", enricher: func(*alpha.DarknessConfig, *yunyun.Site, map[string]any) yunyun.PageOption {
+		return narumi.WithMathSupport()
+	}},
+	{name: "trim", enricher: func(*alpha.DarknessConfig, *yunyun.Site, map[string]any) yunyun.PageOption {
+		return narumi.WithSourceCodeTrimmedLeftWhitespace()
+	}},
+	{name: "highlighting", enricher: func(conf *alpha.DarknessConfig, _ *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithSyntaxHighlighting(conf)
+	}},
+	{name: "lazy_galleries", enricher: func(conf *alpha.DarknessConfig, _ *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithLazyGalleries(conf)
+	}},
+	{name: "listings", enricher: func(_ *alpha.DarknessConfig, site *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithListings(site)
+	}},
+	{name: "related", enricher: func(conf *alpha.DarknessConfig, site *yunyun.Site, options map[string]any) yunyun.PageOption {
+		return narumi.WithRelatedPages(site, alpha.RelatedConfig{
+			Enable: option(options, "enable", conf.Related.Enable),
+			Count:  relatedCount(conf, options),
+		})
+	}, options: map[string]reflect.Kind{"enable": reflect.Bool, "count": reflect.Int64}},
+	{name: "backlinks", enricher: func(conf *alpha.DarknessConfig, site *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithBacklinks(conf, site)
+	}},
+	{name: "series", enricher: func(conf *alpha.DarknessConfig, site *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithSeries(conf, site)
+	}},
+	{name: "translations", enricher: func(_ *alpha.DarknessConfig, site *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithTranslations(site)
+	}},
+	{name: "structured_data", enricher: func(conf *alpha.DarknessConfig, site *yunyun.Site, _ map[string]any) yunyun.PageOption {
+		return narumi.WithStructuredData(conf, site)
+	}},
+}
+
+// option returns the option of a built-in step by its name, or the fallback
+// if it's not set. CheckEnrichment makes sure the set ones are of the right kind.
+func option[T any](options map[string]any, name string, fallback T) T {
+	if value, ok := options[name].(T); ok {
+		return value
+	}
+	return fallback
+}
+
+// relatedCount returns how many related pages the related step finds with its options.
+func relatedCount(conf *alpha.DarknessConfig, options map[string]any) int {
+	return int(option(options, "count", int64(conf.Related.Count)))
+}
+
+// RelatedCount returns how many related pages the page at the location finds, or
+// false if the related step is disabled there or replaced by a registered one.
+func RelatedCount(conf *alpha.DarknessConfig, location yunyun.RelativePathDir) (int, bool) {
+	rule := conf.Enrichment.Rule(location)
+	replaced := slices.ContainsFunc(conf.Runtime.Enrichers, func(enricher alpha.Enricher) bool {
+		return enricher.Name == "related"
+	})
+	if replaced || slices.Contains(rule.Disable, "related") {
+		return 0, false
+	}
+	return relatedCount(conf, rule.Options["related"]), true
+}
+
+// Register adds the enrichment step with the name to the site, before it's built,
+// which runs after all the other steps unless darkness.toml orders it. The step
+// with the same name, including a built-in one, is replaced in its place.
+func Register(conf *alpha.DarknessConfig, name string, enricher Enricher) {
+	conf.Runtime.Enrichers = append(conf.Runtime.Enrichers, alpha.Enricher{Name: name, Enrich: enricher})
+}
+
+// steps returns the built-in steps with the ones registered for the site.
+func steps(conf *alpha.DarknessConfig) []step {
+	all := slices.Clone(builtins)
+	for _, enricher := range conf.Runtime.Enrichers {
+		registered := step{name: enricher.Name, enricher: enricher.Enrich, registered: true}
+		if i := slices.IndexFunc(all, func(s step) bool { return s.name == enricher.Name }); i >= 0 {
+			all[i] = registered
+			continue
+		}
+		all = append(all, registered)
+	}
+	return all
+}
+
+// Steps returns the names of the site's enrichment steps in their default order.
+func Steps(conf *alpha.DarknessConfig) []string {
+	all := steps(conf)
+	names := make([]string, len(all))
+	for i, s := range all {
+		names[i] = s.name
+	}
+	return names
+}
+
+// CheckEnrichment returns an error if darkness.toml mentions steps that are not
+// registered, which are most likely typos, or gives the built-in steps options
+// they don't read or of the wrong kind.
+func CheckEnrichment(conf *alpha.DarknessConfig) error {
+	all := steps(conf)
+	registered := Steps(conf)
+	for _, name := range conf.Enrichment.Steps() {
+		if !slices.Contains(registered, name) {
+			return fmt.Errorf("unknown enrichment step %q, the known ones are %v", name, registered)
+		}
+	}
+	for _, table := range conf.Enrichment.OptionTables() {
+		for name, options := range table {
+			s := all[slices.Index(registered, name)]
+			if s.registered {
+				continue
+			}
+			for key, value := range options {
+				kind, known := s.options[key]
+				if !known {
+					return fmt.Errorf("unknown option %q of the enrichment step %q, the known ones are %v",
+						key, name, slices.Sorted(maps.Keys(s.options)))
+				}
+				if reflect.TypeOf(value).Kind() != kind {
+					return fmt.Errorf("option %q of the enrichment step %q should be %s, got %T", key, name, kind, value)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// EnrichPage enriches the page with the registered steps, ordered, disabled, and
+// configured for the page's directory by darkness.toml. The built-in steps are,
+// - date: the date and the time since
+// - statistics: word count and reading time, its "reading_time" overrides showing it from [reading]
+// - comments: resolved comments
+// - headings: enriched headings
+// - footnotes: footnotes
+// - math: math support
+// - trim: source code trimmed left whitespace
+// - highlighting: syntax highlighting
+// - lazy_galleries: lazy galleries
+// - listings: listings of pages from the site
+// - related: related pages, its "enable" and "count" override the [related] section
+// - backlinks: pages linking here
+// - series: series and previous/next pages
+// - translations: translations in other languages
+// - structured_data: breadcrumbs and modification date for the structured data
+func EnrichPage(conf *alpha.DarknessConfig, site *yunyun.Site, page *yunyun.Page) *yunyun.Page {
+	rule := conf.Enrichment.Rule(page.Location)
+	ordered := orderSteps(steps(conf), rule.Order)
+	options := make([]yunyun.PageOption, 0, len(ordered))
+	for _, s := range ordered {
+		if slices.Contains(rule.Disable, s.name) {
+			continue
+		}
+		options = append(options, s.enricher(conf, site, rule.Options[s.name]))
+	}
+	return page.Options(options...)
+}
+
+// orderSteps returns the steps in the order given, with the rest following
+// in their default order.
+func orderSteps(all []step, order []string) []step {
+	ordered := make([]step, 0, len(all))
+	for i, name := range order {
+		if slices.Contains(order[:i], name) {
+			continue
+		}
+		if j := slices.IndexFunc(all, func(s step) bool { return s.name == name }); j >= 0 {
+			ordered = append(ordered, all[j])
+		}
+	}
+	for _, s := range all {
+		if !slices.Contains(order, s.name) {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered
 }
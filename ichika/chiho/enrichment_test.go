@@ -0,0 +1,121 @@
+package chiho
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestEnrichPage tests that the registered steps run in the configured
+// order and with the directory's overrides
+func TestEnrichPage(t *testing.T) {
+	appendTitle := func(conf *alpha.DarknessConfig, site *yunyun.Site, options map[string]any) yunyun.PageOption {
+		return func(page *yunyun.Page) {
+			page.Title += fmt.Sprint(options["mark"])
+		}
+	}
+	conf := &alpha.DarknessConfig{}
+	Register(conf, "test_first", appendTitle)
+	Register(conf, "test_second", appendTitle)
+	if other := (&alpha.DarknessConfig{}); len(Steps(other)) != len(builtins) {
+		t.Errorf("the steps registered for one site leaked into another: %v", Steps(other))
+	}
+
+	conf.Enrichment = alpha.EnrichmentConfig{
+		Order: []string{"test_second"},
+		// Only the test steps, the built-in ones need a whole site.
+		Disable: Steps(conf)[:len(Steps(conf))-2],
+		Options: map[string]map[string]any{
+			"test_first":  {"mark": "1"},
+			"test_second": {"mark": "2"},
+		},
+		Rules: []alpha.EnrichmentRule{{
+			Dir:     "notes",
+			Disable: []string{"test_second"},
+			Options: map[string]map[string]any{"test_first": {"mark": "one"}},
+		}},
+	}
+	if err := CheckEnrichment(conf); err != nil {
+		t.Fatalf("CheckEnrichment() = %v", err)
+	}
+	tests := []struct {
+		location yunyun.RelativePathDir
+		expected string
+	}{
+		{"blog", "21"},
+		{"notes/a", "one"},
+	}
+	for _, tt := range tests {
+		page := EnrichPage(conf, nil, &yunyun.Page{Location: tt.location})
+		if page.Title != tt.expected {
+			t.Errorf("page in %s got %q, expected %q", tt.location, page.Title, tt.expected)
+		}
+	}
+
+	conf.Enrichment.Disable = append(conf.Enrichment.Disable, "highlightning")
+	if err := CheckEnrichment(conf); err == nil {
+		t.Errorf("expected an error for the misspelled step")
+	}
+}
+
+// TestCheckEnrichmentOptions tests that the built-in steps only take
+// the options they read, of the right kinds
+func TestCheckEnrichmentOptions(t *testing.T) {
+	tests := []struct {
+		step    string
+		options map[string]any
+		valid   bool
+	}{
+		{"related", map[string]any{"enable": true, "count": int64(3)}, true},
+		{"related", map[string]any{"count": "3"}, false},
+		{"related", map[string]any{"limit": int64(3)}, false},
+		{"statistics", map[string]any{"reading_time": false}, true},
+		{"math", map[string]any{"engine": "katex"}, false},
+	}
+	for _, tt := range tests {
+		conf := &alpha.DarknessConfig{}
+		conf.Enrichment.Rules = []alpha.EnrichmentRule{{
+			Dir:     "notes",
+			Options: map[string]map[string]any{tt.step: tt.options},
+		}}
+		if err := CheckEnrichment(conf); (err == nil) != tt.valid {
+			t.Errorf("CheckEnrichment() with %s options %v = %v", tt.step, tt.options, err)
+		}
+	}
+}
+
+// TestRelatedOptions tests that the related step reads its options
+// over the related section
+func TestRelatedOptions(t *testing.T) {
+	summaries := make([]*yunyun.SitePage, 0, 3)
+	for _, location := range []yunyun.RelativePathDir{"a", "b", "c"} {
+		summaries = append(summaries, &yunyun.SitePage{
+			Location:  location,
+			Published: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Terms:     yunyun.Terms{{Term: "go", Weight: 1}},
+		})
+	}
+	site := yunyun.NewSite(summaries)
+	conf := &alpha.DarknessConfig{}
+	conf.Related = alpha.RelatedConfig{Enable: false, Count: 5}
+	enrich := builtins[slices.IndexFunc(builtins, func(s step) bool { return s.name == "related" })].enricher
+	tests := []struct {
+		options  map[string]any
+		expected int
+	}{
+		{nil, 0},
+		{map[string]any{"enable": true}, 2},
+		{map[string]any{"enable": true, "count": int64(1)}, 1},
+	}
+	for _, tt := range tests {
+		page := &yunyun.Page{Location: "a", Date: "1; 12024 H.E.", Accoutrement: &yunyun.Accoutrement{}}
+		enrich(conf, site, tt.options)(page)
+		if len(page.Related) != tt.expected {
+			t.Errorf("with options %v got %d related pages, expected %d", tt.options, len(page.Related), tt.expected)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package chiho
+
+import (
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// BuildSite summarizes the parsed pages into the site.
+func BuildSite(conf *alpha.DarknessConfig, pages []*yunyun.Page) *yunyun.Site {
+	return assembleSite(conf, gana.Map(func(page *yunyun.Page) *yunyun.SitePage {
+		return SummarizePage(conf, page)
+	}, pages))
+}
+
+// assembleSite builds the site out of the page summaries, which get weighed
+// in place, so the same summaries can't go into two sites, clone them first.
+func assembleSite(conf *alpha.DarknessConfig, summaries []*yunyun.SitePage) *yunyun.Site {
+	site := yunyun.NewSite(summaries)
+	narumi.WeighTerms(site)
+	// Only the structured data needs the modification dates, so
+	// don't walk the git history if it's not enabled.
+	if conf.StructuredData.Enable {
+		addModified(conf, site)
+	}
+	return site
+}
+
+// addModified fills in when the pages' sources were last committed.
+func addModified(conf *alpha.DarknessConfig, site *yunyun.Site) {
+	modified, err := alpha.ExtractGitLastModifiedAll(conf)
+	if err != nil {
+		conf.Runtime.Logger.Warn("Couldn't read git history, pages won't have modification dates", "err", err)
+		return
+	}
+	for _, page := range site.Pages {
+		page.Modified = modified[page.File]
+	}
+}
+
+// SummarizePage returns the site summary of the parsed page, it must be
+// called before the page is enriched, as enrichment changes the contents.
+func SummarizePage(conf *alpha.DarknessConfig, page *yunyun.Page) *yunyun.SitePage {
+	published, _ := narumi.ConvertHoloscene(page.Date)
+	preview := yunyun.RelativePathFile(page.Accoutrement.Preview)
+	if len(preview) < 1 && page.Accoutrement.PreviewGenerate.IsEnabled() {
+		preview = puck.PagePreviewFilename
+	}
+	return &yunyun.SitePage{
+		Location:       page.Location,
+		File:           page.File,
+		Title:          page.Title,
+		Author:         page.Author,
+		Date:           page.Date,
+		Published:      published,
+		Description:    narumi.Description(page, conf.Website.DescriptionLength),
+		Preview:        preview,
+		Draft:          page.Accoutrement.Draft.IsEnabled(),
+		Tags:           page.Tags,
+		Terms:          narumi.TermFrequencies(page),
+		Headings:       gana.Map(func(c *yunyun.Content) string { return yunyun.RemoveFormatting(c.Heading) }, page.Contents.Headings()),
+		Text:           narumi.ContentsText(page),
+		Stats:          narumi.PageStatistics(conf, page),
+		Links:          narumi.OutgoingLinks(conf, page),
+		SeriesName:     page.SeriesName,
+		SeriesOrder:    page.SeriesOrder,
+		Language:       page.Language,
+		TranslationKey: narumi.TranslationKey(page),
+	}
+}
@@ -0,0 +1,61 @@
+package ichika
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
+	"github.com/thecsw/darkness/v3/ichika/chris"
+	"github.com/thecsw/darkness/v3/ichika/makima"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/gana"
+)
+
+// CheckLinksCommandFunc checks all the internal and external links
+// of the website and reports the broken ones.
+func CheckLinksCommandFunc() {
+	checkLinksCmd := darknessFlagset(checkLinksCommand)
+	external := checkLinksCmd.Bool("external", true, "check the links to other websites")
+	concurrency := checkLinksCmd.Int("concurrency", 8, "number of external links to check at once")
+	hostInterval := checkLinksCmd.Duration("host-interval", time.Second, "minimum time between requests to the same host")
+	timeout := checkLinksCmd.Duration("timeout", 15*time.Second, "timeout of a single request")
+	cacheTTL := checkLinksCmd.Duration("cache-ttl", 24*time.Hour, "how long to trust the external links that worked")
+	ci := checkLinksCmd.Bool("ci", false, "exit with a non-zero code if any links are broken")
+
+	options := getAlphaOptions(checkLinksCmd)
+	conf := alpha.BuildConfig(options)
+
+	ctx, stop := interruptible()
+	report := chris.CheckLinks(ctx, conf, chris.Options{
+		External:     *external,
+		Concurrency:  *concurrency,
+		HostInterval: *hostInterval,
+		Timeout:      *timeout,
+		CacheTTL:     *cacheTTL,
+	})
+	stop()
+	report.Write(os.Stdout)
+	if *ci && len(report.Broken) > 0 {
+		os.Exit(1)
+	}
+}
+
+// checkLinks warns about the internal links in the parsed pages that lead nowhere,
+// with the broken images separate, as both need all the pages to be known.
+func (b *builder) checkLinks(parsed []makima.Woof, generated []*yunyun.Page) {
+	pages := append(gana.Map(makima.Woof.ParsedPage, parsed), generated...)
+	links := make([]*chris.Link, 0, 256)
+	for _, woof := range parsed {
+		page := woof.ParsedPage()
+		links = append(links, chris.ExtractLinks(page.File, page.Location, woof.Source())...)
+	}
+	for _, broken := range chris.BrokenInternalLinks(b.conf, pages, links) {
+		class := hitagi.UnresolvedLinks
+		if target, _, _ := strings.Cut(broken.Target, "#"); yunyun.ImageExtRegexp.MatchString(strings.ToLower(target)) {
+			class = hitagi.MissingImages
+		}
+		b.conf.Runtime.Problems.Warn(class, string(broken.File), "line %d: %s (%s)", broken.Line, broken.Target, broken.Problem)
+	}
+}
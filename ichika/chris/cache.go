@@ -0,0 +1,89 @@
+package chris
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// cacheFile is where the results of the external links are kept between runs.
+const cacheFile yunyun.RelativePathFile = ".darkness/links.json"
+
+// cachedLink is the result of an external link that worked.
+type cachedLink struct {
+	// Status is the http status code that the link returned.
+	Status int `json:"status"`
+	// Checked is when the link was checked.
+	Checked time.Time `json:"checked"`
+}
+
+// linkCache remembers the external links that worked, so we don't
+// bother their hosts on every run. Broken links are always rechecked.
+type linkCache struct {
+	mutex sync.Mutex
+	ttl   time.Duration
+	links map[string]cachedLink
+}
+
+// loadCache reads the cache from the working directory, a missing or
+// broken cache is simply an empty one.
+func loadCache(conf *alpha.DarknessConfig, ttl time.Duration) *linkCache {
+	cache := &linkCache{ttl: ttl, links: make(map[string]cachedLink)}
+	data, err := os.ReadFile(filepath.Clean(string(conf.Runtime.WorkDir.Join(cacheFile))))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.links); err != nil {
+		logger.Warn("Ignoring the broken links cache", "err", err)
+		cache.links = make(map[string]cachedLink)
+	}
+	return cache
+}
+
+// get returns true if the link worked recently enough.
+func (c *linkCache) get(link string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	cached, found := c.links[link]
+	return found && time.Since(cached.Checked) < c.ttl
+}
+
+// put remembers the result of the link, forgetting it if it didn't work.
+func (c *linkCache) put(link string, status int, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err != nil || status >= 400 {
+		delete(c.links, link)
+		return
+	}
+	c.links[link] = cachedLink{Status: status, Checked: time.Now()}
+}
+
+// save writes the cache into the working directory, dropping the stale links.
+func (c *linkCache) save(conf *alpha.DarknessConfig) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for link, cached := range c.links {
+		if time.Since(cached.Checked) >= c.ttl {
+			delete(c.links, link)
+		}
+	}
+	data, err := json.MarshalIndent(c.links, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding links cache: %v", err)
+	}
+	target := conf.Runtime.WorkDir.Join(cacheFile)
+	if err := os.MkdirAll(filepath.Dir(string(target)), 0o755); err != nil {
+		return fmt.Errorf("creating links cache directory: %v", err)
+	}
+	if err := os.WriteFile(string(target), data, 0o644); err != nil {
+		return fmt.Errorf("writing links cache %s: %v", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package chris
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/ichika/chiho"
+	"github.com/thecsw/darkness/v3/ichika/himeno"
+	"github.com/thecsw/darkness/v3/ichika/hizuru"
+	"github.com/thecsw/darkness/v3/ichika/kazuma"
+	"github.com/thecsw/darkness/v3/parse"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Options are the settings of the link checker.
+type Options struct {
+	// External enables checking the links to other websites.
+	External bool
+	// Concurrency is the number of external links checked at once.
+	Concurrency int
+	// HostInterval is the minimum time between requests to the same host.
+	HostInterval time.Duration
+	// Timeout is the timeout of a single request.
+	Timeout time.Duration
+	// CacheTTL is how long the external links that worked are trusted.
+	CacheTTL time.Duration
+}
+
+// BrokenLink is a link that doesn't lead anywhere.
+type BrokenLink struct {
+	*Link
+	// Problem is what's wrong with the link.
+	Problem string
+}
+
+// Report is the result of checking all the links.
+type Report struct {
+	// Checked is the number of links checked.
+	Checked int
+	// Skipped is the number of links that can't be checked, like emails.
+	Skipped int
+	// Broken are the broken links, sorted by their files and lines.
+	Broken []*BrokenLink
+}
+
+// CheckLinks reads all the pages and checks every link written in them. Once
+// the context is done, the external links left unchecked are reported broken
+// with the context's error.
+func CheckLinks(ctx context.Context, conf *alpha.DarknessConfig, options Options) *Report {
+	defer puck.Stopwatch("Checked links").Record(logger)
+	himeno.RegisterGlobalMacros(conf)
+	parser := parse.BuildParser(conf)
+
+	// Parse all the pages and pull the links out of their sources.
+	pages := make([]*yunyun.Page, 0, 64)
+	links := make([]*Link, 0, 256)
+	for _, inputFilename := range hizuru.FindFilesByExtSimple(conf) {
+		data, err := os.ReadFile(filepath.Clean(string(inputFilename)))
+		if err != nil {
+			logger.Error("Reading page", "input", inputFilename, "err", err)
+			continue
+		}
+		file := conf.Runtime.WorkDir.Rel(inputFilename)
+		page := parser.Do(file, string(data))
+		if page == nil {
+			continue
+		}
+		pages = append(pages, page)
+		links = append(links, ExtractLinks(file, page.Location, string(data))...)
+	}
+
+	// Generated pages, like listings and tags, are also valid targets.
+	pages = append(pages, kazuma.GeneratePages(conf, chiho.BuildSite(conf, pages))...)
+	targets := newOutputs(conf, pages)
+
+	report := &Report{Broken: make([]*BrokenLink, 0, 8)}
+	externals := make([]*Link, 0, len(links))
+	for _, link := range links {
+		kind, target := classifyLink(conf, link.Target)
+		switch kind {
+		case linkSkipped:
+			report.Skipped++
+			continue
+		case linkExternal:
+			if !options.External {
+				report.Skipped++
+				continue
+			}
+			externals = append(externals, link)
+			continue
+		}
+		report.Checked++
+		if problem := targets.checkInternal(conf, link, target); len(problem) > 0 {
+			report.Broken = append(report.Broken, &BrokenLink{Link: link, Problem: problem})
+		}
+	}
+	if len(externals) > 0 {
+		report.Broken = append(report.Broken, checkExternal(ctx, conf, options, externals)...)
+		report.Checked += len(externals)
+	}
+
+	sortBroken(report.Broken)
+	return report
+}
+
+// BrokenInternalLinks checks the internal links against the pages, which have
+// to include the generated ones, and returns the broken links, sorted.
+func BrokenInternalLinks(conf *alpha.DarknessConfig, pages []*yunyun.Page, links []*Link) []*BrokenLink {
+	targets := newOutputs(conf, pages)
+	broken := make([]*BrokenLink, 0, 8)
+	for _, link := range links {
+		kind, target := classifyLink(conf, link.Target)
+		if kind != linkInternal {
+			continue
+		}
+		if problem := targets.checkInternal(conf, link, target); len(problem) > 0 {
+			broken = append(broken, &BrokenLink{Link: link, Problem: problem})
+		}
+	}
+	sortBroken(broken)
+	return broken
+}
+
+// sortBroken sorts the broken links by their files and lines.
+func sortBroken(broken []*BrokenLink) {
+	sort.SliceStable(broken, func(i, j int) bool {
+		if broken[i].File != broken[j].File {
+			return broken[i].File < broken[j].File
+		}
+		return broken[i].Line < broken[j].Line
+	})
+}
+
+// checkExternal checks the external links and returns the broken ones.
+func checkExternal(ctx context.Context, conf *alpha.DarknessConfig, options Options, links []*Link) []*BrokenLink {
+	checker := &externalChecker{
+		client:      &http.Client{Timeout: options.Timeout},
+		interval:    options.HostInterval,
+		cache:       loadCache(conf, options.CacheTTL),
+		nextRequest: make(map[string]time.Time),
+	}
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = withoutFragment(link.Target)
+	}
+	checks := checker.checkAll(ctx, urls, options.Concurrency)
+	if err := checker.cache.save(conf); err != nil {
+		logger.Warn("Couldn't save the links cache", "err", err)
+	}
+
+	broken := make([]*BrokenLink, 0, 8)
+	for i, link := range links {
+		if problem := checks[urls[i]].problem(); len(problem) > 0 {
+			broken = append(broken, &BrokenLink{Link: link, Problem: problem})
+		}
+	}
+	return broken
+}
+
+// Write writes the broken links grouped by the pages they're on.
+func (r *Report) Write(w io.Writer) {
+	var lastFile yunyun.RelativePathFile
+	for _, broken := range r.Broken {
+		if broken.File != lastFile {
+			if len(lastFile) > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintln(w, broken.File)
+			lastFile = broken.File
+		}
+		fmt.Fprintf(w, "  %d: %s (%s)\n", broken.Line, broken.Target, broken.Problem)
+	}
+	if len(r.Broken) > 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "Checked %d links, %d broken, %d skipped\n", r.Checked, len(r.Broken), r.Skipped)
+}
+
+// withoutFragment returns the url without its fragment, since the
+// fragments don't change what the server returns.
+func withoutFragment(target string) string {
+	target, _, _ = strings.Cut(target, "#")
+	return target
+}
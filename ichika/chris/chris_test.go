@@ -0,0 +1,86 @@
+package chris
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestExtractLinks tests that the links are found with their lines, outside of the source blocks
+func TestExtractLinks(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	data := "* Title\n[[../b][b]] and [[#top]]\n#+begin_src org\n[[/skipped]]\n#+end_src\n[[https://example.com]]\n#+begin_gallery :path photos :num 2\n- [[cat.png]]\n- [[/dog.png]]\n#+end_gallery"
+	links := ExtractLinks("notes/a/index.org", "notes/a", data)
+	want := []struct {
+		line   int
+		target string
+	}{{2, "../b"}, {2, "#top"}, {6, "https://example.com"}, {8, "photos/cat.png"}, {9, "/dog.png"}}
+	if len(links) != len(want) {
+		t.Fatalf("ExtractLinks() returned %d links, want %d", len(links), len(want))
+	}
+	for i, link := range links {
+		if link.Line != want[i].line || link.Target != want[i].target {
+			t.Errorf("link %d = %d:%s, want %d:%s", i, link.Line, link.Target, want[i].line, want[i].target)
+		}
+	}
+}
+
+// TestExternalCheckerFallsBackToGet tests that the links refusing HEAD are checked with GET and only the working ones are cached
+func TestExternalCheckerFallsBackToGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	checker := &externalChecker{
+		client:      server.Client(),
+		cache:       &linkCache{ttl: time.Hour, links: make(map[string]cachedLink)},
+		nextRequest: make(map[string]time.Time),
+	}
+	checks := checker.checkAll(context.Background(), []string{server.URL + "/page", server.URL + "/missing", server.URL + "/page"}, 2)
+	if problem := checks[server.URL+"/page"].problem(); problem != "" {
+		t.Errorf("page should work with GET, got %q", problem)
+	}
+	if problem := checks[server.URL+"/missing"].problem(); problem != "404 Not Found" {
+		t.Errorf("missing page problem = %q, want %q", problem, "404 Not Found")
+	}
+	if !checker.cache.get(server.URL+"/page") || checker.cache.get(server.URL+"/missing") {
+		t.Error("only the working links should be cached")
+	}
+}
+
+// TestExternalCheckerStopsWithContext tests that no links are requested once the context is done
+func TestExternalCheckerStopsWithContext(t *testing.T) {
+	var requested atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested.Store(true)
+	}))
+	defer server.Close()
+
+	checker := &externalChecker{
+		client:      server.Client(),
+		interval:    time.Hour,
+		cache:       &linkCache{ttl: time.Hour, links: make(map[string]cachedLink)},
+		nextRequest: map[string]time.Time{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checks := checker.checkAll(ctx, []string{server.URL + "/page"}, 1)
+	if err := checks[server.URL+"/page"].Err; !errors.Is(err, context.Canceled) {
+		t.Errorf("check error = %v, want %v", err, context.Canceled)
+	}
+	if requested.Load() || checker.cache.get(server.URL+"/page") {
+		t.Error("a canceled check shouldn't request or cache the url")
+	}
+}
@@ -0,0 +1,145 @@
+package chris
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thecsw/komi"
+)
+
+// userAgent is how the checker introduces itself, some hosts refuse
+// requests without a proper one.
+const userAgent = "Darkness link checker (sandyuraz.com/darkness)"
+
+// externalCheck is a single external url to check.
+type externalCheck struct {
+	// Url is the url without its fragment.
+	Url string
+	// Status is the final http status code, 0 if the request failed.
+	Status int
+	// Err is the error of the request, if any.
+	Err error
+}
+
+// problem returns what's wrong with the url, empty if nothing.
+func (e *externalCheck) problem() string {
+	switch {
+	case e.Err != nil:
+		return e.Err.Error()
+	case e.Status >= 400:
+		return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	}
+	return ""
+}
+
+// externalChecker requests the external urls, with a limit on how
+// often the same host is bothered.
+type externalChecker struct {
+	client   *http.Client
+	interval time.Duration
+	cache    *linkCache
+
+	mutex       sync.Mutex
+	nextRequest map[string]time.Time
+}
+
+// checkAll checks all the urls concurrently, every url is only requested once.
+// Once the context is done, the urls left unchecked get its error.
+func (c *externalChecker) checkAll(ctx context.Context, urls []string, concurrency int) map[string]*externalCheck {
+	checks := make(map[string]*externalCheck, len(urls))
+	check := func(e *externalCheck) { c.check(ctx, e) }
+	pool := komi.NewWithSettings(komi.WorkSimple(check), &komi.Settings{
+		Name:     "Chris 🗝️ ",
+		Laborers: concurrency,
+	})
+	for _, target := range urls {
+		if _, found := checks[target]; found {
+			continue
+		}
+		checks[target] = &externalCheck{Url: target}
+		if err := pool.Submit(checks[target]); err != nil {
+			checks[target].Err = fmt.Errorf("submitting the check: %v", err)
+		}
+	}
+	// Block until all the urls are checked.
+	pool.Wait()
+	pool.Close()
+	return checks
+}
+
+// check requests the url with HEAD and falls back to GET if that
+// didn't work, as plenty of servers don't bother supporting HEAD.
+func (c *externalChecker) check(ctx context.Context, e *externalCheck) {
+	if c.cache.get(e.Url) {
+		e.Status = http.StatusOK
+		return
+	}
+	if e.Err = ctx.Err(); e.Err != nil {
+		return
+	}
+	e.Status, e.Err = c.request(ctx, http.MethodHead, e.Url)
+	if ctx.Err() == nil && (e.Err != nil || e.Status >= 400) {
+		e.Status, e.Err = c.request(ctx, http.MethodGet, e.Url)
+	}
+	if ctx.Err() != nil {
+		// Don't remember the urls the checker gave up on.
+		return
+	}
+	c.cache.put(e.Url, e.Status, e.Err)
+	logger.Debug("Checked", "url", e.Url, "status", e.Status, "err", e.Err)
+}
+
+// request makes the request once the host is ready for it and returns the status code.
+func (c *externalChecker) request(ctx context.Context, method, target string) (int, error) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return 0, fmt.Errorf("parsing url: %v", err)
+	}
+	if err := c.waitForHost(ctx, strings.ToLower(parsed.Host)); err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %v", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The url error repeats the method and the url, we already know those.
+		if urlErr, ok := err.(*url.Error); ok {
+			return 0, urlErr.Err
+		}
+		return 0, err
+	}
+	// Drain a bit of the body so the connection can be reused.
+	_, _ = io.CopyN(io.Discard, resp.Body, 1<<16)
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// waitForHost blocks until it's the host's turn to get another request,
+// returning early with the context's error if it's done first.
+func (c *externalChecker) waitForHost(ctx context.Context, host string) error {
+	c.mutex.Lock()
+	now := time.Now()
+	turn := c.nextRequest[host]
+	if turn.Before(now) {
+		turn = now
+	}
+	c.nextRequest[host] = turn.Add(c.interval)
+	c.mutex.Unlock()
+	timer := time.NewTimer(time.Until(turn))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
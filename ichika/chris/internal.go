@@ -0,0 +1,71 @@
+package chris
+
+import (
+	"os"
+	"path"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/export/html"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// footnoteAnchorPrefix is the prefix of the anchors darkness makes for footnotes.
+const footnoteAnchorPrefix = "_footnote"
+
+// outputs are all the pages and files that the build produces.
+type outputs struct {
+	// pages are the locations of all the pages with their anchors.
+	pages map[yunyun.RelativePathDir]map[string]bool
+	// files are the generated files that might not be written yet.
+	files map[string]bool
+}
+
+// newOutputs collects the locations and anchors of the pages, along with
+// the files that darkness generates next to them.
+func newOutputs(conf *alpha.DarknessConfig, pages []*yunyun.Page) *outputs {
+	o := &outputs{
+		pages: make(map[yunyun.RelativePathDir]map[string]bool, len(pages)),
+		files: make(map[string]bool),
+	}
+	for _, page := range pages {
+		anchors := make(map[string]bool)
+		for _, heading := range page.Contents.Headings() {
+			anchors[html.ExtractID(heading.Heading)] = true
+		}
+		o.pages[page.Location] = anchors
+	}
+	for _, feed := range conf.Feeds {
+		o.files[string(feed.Path)] = true
+	}
+	if conf.Sitemap.Enable {
+		o.files[string(conf.Sitemap.Path)] = true
+	}
+	if conf.Search.Enable {
+		o.files[string(conf.Search.ManifestPath())] = true
+	}
+	return o
+}
+
+// checkInternal returns the problem with the internal link, empty if none.
+func (o *outputs) checkInternal(conf *alpha.DarknessConfig, link *Link, target string) string {
+	resolved, fragment, ok := narumi.ResolveLink(link.Location, target)
+	if !ok {
+		return "points above the website's root"
+	}
+	location := narumi.LinkLocation(conf, resolved)
+	if anchors, found := o.pages[location]; found {
+		if len(fragment) > 0 && !anchors[fragment] && !strings.HasPrefix(fragment, footnoteAnchorPrefix) {
+			return "no such anchor #" + fragment
+		}
+		return ""
+	}
+	if o.files[resolved] {
+		return ""
+	}
+	if _, err := os.Stat(string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(path.Clean(resolved))))); err == nil {
+		return ""
+	}
+	return "no such page or file"
+}
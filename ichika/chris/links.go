@@ -0,0 +1,120 @@
+package chris
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Link is a link written in the source of a page.
+type Link struct {
+	// File is the source file of the page.
+	File yunyun.RelativePathFile
+	// Location is the location of the page with the link.
+	Location yunyun.RelativePathDir
+	// Line is the line number of the link in the source file.
+	Line int
+	// Target is the link as it was written.
+	Target string
+}
+
+// linkKind tells us how the link should be checked.
+type linkKind uint8
+
+const (
+	// linkSkipped are links we can't check, like emails.
+	linkSkipped linkKind = iota
+	// linkInternal are links to the pages and files of the website.
+	linkInternal
+	// linkExternal are links to other websites.
+	linkExternal
+)
+
+var (
+	// blockStarts are the blocks whose links are not links, but text.
+	blockStarts = []string{"#+begin_src", "#+begin_example", "#+begin_export"}
+	// blockEnds close the blocks from above.
+	blockEnds = []string{"#+end_src", "#+end_example", "#+end_export"}
+	// galleryPathRegexp finds the `:path` of a gallery, which its items are relative to.
+	galleryPathRegexp = regexp.MustCompile(`(?i)^#\+begin_gallery.*:path\s+(\S+)`)
+)
+
+// ExtractLinks returns all the links from the page's source, skipping
+// the ones inside source code, example, and export blocks. The items of
+// galleries with a `:path` are relative to it, so it's joined in.
+func ExtractLinks(file yunyun.RelativePathFile, location yunyun.RelativePathDir, data string) []*Link {
+	links := make([]*Link, 0, 16)
+	inBlock := false
+	galleryPath := ""
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.ToLower(strings.TrimSpace(line))
+		switch {
+		case hasAnyPrefix(trimmed, blockStarts):
+			inBlock = true
+			continue
+		case hasAnyPrefix(trimmed, blockEnds):
+			inBlock = false
+			continue
+		case inBlock:
+			continue
+		case strings.HasPrefix(trimmed, "#+begin_gallery"):
+			if matches := galleryPathRegexp.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
+				galleryPath = matches[1]
+			}
+			continue
+		case strings.HasPrefix(trimmed, "#+end_gallery"):
+			galleryPath = ""
+			continue
+		}
+		for _, extracted := range yunyun.ExtractLinks(line) {
+			target := strings.TrimSpace(extracted.Link)
+			if len(galleryPath) > 0 && isRelative(target) {
+				target = path.Join(galleryPath, target)
+			}
+			links = append(links, &Link{
+				File:     file,
+				Location: location,
+				Line:     i + 1,
+				Target:   target,
+			})
+		}
+	}
+	return links
+}
+
+// isRelative returns true if the target is a relative path, not a url
+// or a path from the website's root.
+func isRelative(target string) bool {
+	if strings.HasPrefix(target, "/") || strings.HasPrefix(target, "#") {
+		return false
+	}
+	parsed, err := url.Parse(target)
+	return err == nil && len(parsed.Scheme) < 1
+}
+
+// classifyLink returns the kind of the link and, for internal links,
+// the target with the website's own url trimmed off.
+func classifyLink(conf *alpha.DarknessConfig, target string) (linkKind, string) {
+	if internal, ok := narumi.InternalLink(conf, target); ok {
+		return linkInternal, internal
+	}
+	if parsed, err := url.Parse(target); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+		return linkExternal, target
+	}
+	return linkSkipped, target
+}
+
+// hasAnyPrefix returns true if the text starts with any of the prefixes.
+func hasAnyPrefix(text string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,6 @@
+package chris
+
+import "github.com/thecsw/darkness/v3/emilia/puck"
+
+// logger is the logger for Chris.
+var logger = puck.NewLogger("Chris 🗝️ ", puck.InfoLevel)
@@ -12,6 +12,7 @@ const (
 	misaCommand        DarknessCommand = `misa`
 	lalatinaCommand    DarknessCommand = `lalatina`
 	aquaCommand        DarknessCommand = `aqua`
+	checkLinksCommand  DarknessCommand = `check-links`
 )
 
 // CommandFuncs maps supplied darkness command to the function
@@ -25,6 +26,7 @@ var CommandFuncs = map[DarknessCommand]func(){
 	misaCommand:        MisaCommandFunc,
 	lalatinaCommand:    LalatinaCommandFunc,
 	aquaCommand:        AquaCommandFunc,
+	checkLinksCommand:  CheckLinksCommandFunc,
 
 	// All the help commands
 	`-h`:     HelpCommandFunc,
@@ -26,6 +26,7 @@ func getAlphaOptions(cmd *flag.FlagSet) alpha.Options {
 	cmd.BoolVar(&kuroko.Akaneless, "akaneless", false, "skip akane processing")
 	cmd.BoolVar(&kuroko.Force, "force", false, "force post-processing (akane or misa)")
 	cmd.BoolVar(&kuroko.BuildReport, "build-report", false, "produce a build report")
+	cmd.BoolVar(&kuroko.Strict, "strict", false, "fail the build on warnings, like broken links")
 	if len(os.Args) < 2 {
 		puck.Logger.Fatalf("no command specified")
 	}
@@ -62,6 +63,13 @@ func getAlphaOptions(cmd *flag.FlagSet) alpha.Options {
 		Debug:           kuroko.DebugEnabled,
 		WorkDir:         kuroko.WorkDir,
 		VendorGalleries: kuroko.VendorGalleryImages,
+		Strict:          kuroko.Strict,
+		Workers:         kuroko.CustomNumWorkers,
+		Akaneless:       kuroko.Akaneless,
+		Force:           kuroko.Force,
+		BuildReport:     kuroko.BuildReport,
+		Lfs:             kuroko.LfsEnabled,
+		Logger:          puck.NewLogger("Alpha ☕", kuroko.LogLevel()),
 	}
 }
 
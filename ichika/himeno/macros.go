@@ -21,7 +21,9 @@ func RegisterGlobalMacros(conf *alpha.DarknessConfig) {
 	if conf.Project.Input != puck.ExtensionOrgmode {
 		return
 	}
-	globalMacrosFile := yunyun.RelativePathFile(globalMacrosFileBasename + conf.Project.Input)
+	// Start from scratch, so the macros removed from the file are gone.
+	orgmode.ClearGlobalMacros(conf)
+	globalMacrosFile := GlobalMacrosFile(conf)
 	globalMacrosFileFull := string(conf.Runtime.WorkDir.Join(globalMacrosFile))
 	if exists, err := rei.FileExists(globalMacrosFileFull); exists {
 		file, err := os.ReadFile(filepath.Clean(globalMacrosFileFull))
@@ -36,3 +38,8 @@ func RegisterGlobalMacros(conf *alpha.DarknessConfig) {
 		conf.Runtime.Logger.Error("Failed to see if the global macros file even exists", "err", err)
 	}
 }
+
+// GlobalMacrosFile returns the file with the global macros.
+func GlobalMacrosFile(conf *alpha.DarknessConfig) yunyun.RelativePathFile {
+	return yunyun.RelativePathFile(globalMacrosFileBasename + conf.Project.Input)
+}
@@ -1,6 +1,8 @@
 package hizuru
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -21,8 +23,9 @@ const (
 	skipPrefix = "_"
 )
 
-// FindFilesByExt finds all files with a given extension.
-func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPathFile) {
+// FindFilesByExt finds all files with a given extension, it stops looking
+// and closes the channel once the context is done.
+func FindFilesByExt(ctx context.Context, conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPathFile) {
 	// We don't need a concurrent map because we're only using it in a single goroutine.
 	pathDedupe := map[string]struct{}{}
 	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
@@ -32,6 +35,9 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 		},
 		Unsorted: true,
 		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if filepath.Ext(osPathname) != conf.Project.Input || strings.HasPrefix(filepath.Base(osPathname), ".") {
 				return nil
 			}
@@ -48,23 +54,70 @@ func FindFilesByExt(conf *alpha.DarknessConfig, inputFiles chan<- yunyun.FullPat
 			}
 			// If we haven't seen this path before, add it to the channel.
 			if _, seen := pathDedupe[relPath]; !seen {
-				inputFiles <- conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relPath))
+				select {
+				case inputFiles <- conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(relPath)):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
 			// Mark this path as seen.
 			pathDedupe[relPath] = struct{}{}
 			return nil
 		},
-	}); err != nil {
+	}); err != nil && !errors.Is(err, ctx.Err()) {
 		conf.Runtime.Logger.Errorf("root traversal: %v", err)
 	}
 	close(inputFiles)
 }
 
+// IsInputFile tells us if the file would be found as an input file, it
+// doesn't have to exist, so that removed inputs can be recognized too.
+func IsInputFile(conf *alpha.DarknessConfig, filename yunyun.FullPathFile) bool {
+	base := filepath.Base(string(filename))
+	return filepath.Ext(base) == conf.Project.Input &&
+		!strings.HasPrefix(base, ".") && !strings.Contains(base, skipPrefix) &&
+		!(conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(string(filename)))
+}
+
+// FindDirs finds all the directories that are neither hidden nor excluded,
+// including the root.
+func FindDirs(conf *alpha.DarknessConfig) []yunyun.FullPathDir {
+	dirs := make([]yunyun.FullPathDir, 0, 16)
+	if err := godirwalk.Walk(string(conf.Runtime.WorkDir), &godirwalk.Options{
+		ErrorCallback: func(osPathname string, err error) godirwalk.ErrorAction {
+			conf.Runtime.Logger.Errorf("traversing %s: %v", osPathname, err)
+			return godirwalk.SkipNode
+		},
+		Unsorted: true,
+		Callback: func(osPathname string, de *godirwalk.Dirent) error {
+			if !de.IsDir() {
+				return nil
+			}
+			if osPathname != string(conf.Runtime.WorkDir) && IsSkippedDir(conf, yunyun.FullPathDir(osPathname)) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, yunyun.FullPathDir(osPathname))
+			return nil
+		},
+	}); err != nil {
+		conf.Runtime.Logger.Errorf("root traversal: %v", err)
+	}
+	return dirs
+}
+
+// IsSkippedDir tells us if the directory is hidden or excluded, like the
+// output directory, so that its files are neither inputs nor watched. The
+// exclusions match the paths under them, hence the trailing slash.
+func IsSkippedDir(conf *alpha.DarknessConfig, dir yunyun.FullPathDir) bool {
+	return strings.HasPrefix(filepath.Base(string(dir)), ".") ||
+		(conf.Project.ExcludeEnabled && conf.Project.ExcludeRegex.MatchString(string(dir)+"/"))
+}
+
 // FindFilesByExtSimple is the same as `FindFilesByExt` but it simply blocks the
 // parent goroutine until it processes all the results.
 func FindFilesByExtSimple(conf *alpha.DarknessConfig) []yunyun.FullPathFile {
 	c := make(chan yunyun.FullPathFile)
-	go FindFilesByExt(conf, c)
+	go FindFilesByExt(context.Background(), conf, c)
 	return rei.Collect(c)
 }
 
@@ -105,7 +158,7 @@ func BuildPagesSimple(conf *alpha.DarknessConfig, dirs []string) []*yunyun.Page
 		}
 		page := parser.Do(conf.Runtime.WorkDir.Rel(bundle.First), string(data))
 		if page == nil {
-			logger.Warn("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
+			conf.Runtime.Logger.Warn("Parser produced a nil page", "input", conf.Runtime.WorkDir.Rel(bundle.First))
 			continue
 		}
 		pages = append(pages, page)
@@ -1,9 +1,11 @@
 package hizuru
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 	"testing"
@@ -325,3 +327,71 @@ func TestNoFileDuplication(t *testing.T) {
 		seen[rel] = true
 	}
 }
+
+// TestIsInputFile tests that single files are recognized the same way
+// as the discovery finds them
+func TestIsInputFile(t *testing.T) {
+	tempDir, config := setupTestEnvironment(t)
+	defer os.RemoveAll(tempDir)
+
+	found := make(map[string]bool)
+	for _, file := range FindFilesByExtSimple(config) {
+		found[string(file)] = true
+	}
+	for _, file := range []string{
+		"file1.org", "nested/deep/file4.org", "nested/.hidden/hidden.org",
+		"_ignoredfile.org", ".hiddenfile.org", "other.txt", "removed.org",
+	} {
+		fullPath := yunyun.FullPathFile(filepath.Join(tempDir, file))
+		expected := found[string(fullPath)] || file == "removed.org"
+		if got := IsInputFile(config, fullPath); got != expected {
+			t.Errorf("IsInputFile(%s) = %v, expected %v", file, got, expected)
+		}
+	}
+}
+
+// TestFindFilesByExtCancelled tests that the discovery stops and closes
+// the channel once the context is done
+func TestFindFilesByExtCancelled(t *testing.T) {
+	tempDir, config := setupTestEnvironment(t)
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nobody reads the channel, so a discovery that doesn't stop would block.
+	c := make(chan yunyun.FullPathFile)
+	FindFilesByExt(ctx, config, c)
+	if file, open := <-c; open {
+		t.Errorf("FindFilesByExt() found %s after being cancelled", file)
+	}
+}
+
+// TestIsSkippedDir tests that the hidden and excluded directories, like
+// the output directory, are skipped along with the ones under them
+func TestIsSkippedDir(t *testing.T) {
+	tempDir, config := setupTestEnvironment(t)
+	defer os.RemoveAll(tempDir)
+	config.Project.ExcludeEnabled = true
+	config.Project.ExcludeRegex = regexp.MustCompile(`(?mU)(public)/.*`)
+	if err := os.MkdirAll(filepath.Join(tempDir, "public", "notes"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for dir, expected := range map[string]bool{
+		"nested":        false,
+		"nested/deep":   false,
+		"nested/.cache": true,
+		"public":        true,
+		"public/notes":  true,
+	} {
+		if got := IsSkippedDir(config, yunyun.FullPathDir(filepath.Join(tempDir, dir))); got != expected {
+			t.Errorf("IsSkippedDir(%s) = %v, expected %v", dir, got, expected)
+		}
+	}
+	for _, dir := range FindDirs(config) {
+		if strings.Contains(string(dir), "public") {
+			t.Errorf("FindDirs() found the excluded %s", dir)
+		}
+	}
+}
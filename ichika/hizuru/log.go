@@ -1,5 +0,0 @@
-package hizuru
-
-import "github.com/thecsw/darkness/v3/emilia/puck"
-
-var logger = puck.NewLogger("Hizuru 🔨")
@@ -0,0 +1,31 @@
+package kazuma
+
+import (
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// GeneratePages returns all the pages that darkness generates from the site.
+func GeneratePages(conf *alpha.DarknessConfig, site *yunyun.Site) []*yunyun.Page {
+	pages := append(listingPages(conf, site), tagPages(conf, site)...)
+	return append(pages, searchPages(conf, site)...)
+}
+
+// newGeneratedPage returns a new empty page at the location, as if it were
+// written in the location's index file.
+func newGeneratedPage(
+	conf *alpha.DarknessConfig,
+	location yunyun.RelativePathDir,
+	title string,
+	contents ...*yunyun.Content,
+) *yunyun.Page {
+	page := yunyun.NewPage(
+		yunyun.WithFilename(yunyun.JoinRelativePaths(location, yunyun.RelativePathFile("index"+conf.Project.Input))),
+		yunyun.WithLocation(location),
+		yunyun.WithContents(contents),
+	)
+	page.Title = title
+	page.Date, page.DateHoloscene = "", false
+	page.Author = conf.RSS.DefaultAuthor
+	return page
+}
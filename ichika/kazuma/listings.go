@@ -0,0 +1,75 @@
+package kazuma
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// paginationDir is the directory under the listing with the rest of its pages.
+const paginationDir = "page"
+
+// listingPages generates the index pages of directories listed in the config.
+func listingPages(conf *alpha.DarknessConfig, site *yunyun.Site) []*yunyun.Page {
+	pages := make([]*yunyun.Page, 0, len(conf.Listings))
+	for _, listing := range conf.Listings {
+		// Never overwrite the pages written by hand.
+		if site.Page(listing.Dir) != nil {
+			conf.Runtime.Logger.Warn("Directory already has an index page, use #+list_pages: there",
+				"dir", listing.Dir)
+			continue
+		}
+		pages = append(pages, paginatedPages(conf, site, listing.Dir, listing.Title, &yunyun.Listing{
+			Dir:       listing.Dir,
+			Sort:      listing.Sort,
+			Limit:     listing.Limit,
+			Recursive: listing.Recursive,
+			PageSize:  listing.PageSize,
+		})...)
+	}
+	return pages
+}
+
+// paginatedPages returns the listing pages, where the first page is at the
+// location and the rest go into `page/N` under it.
+func paginatedPages(
+	conf *alpha.DarknessConfig,
+	site *yunyun.Site,
+	location yunyun.RelativePathDir,
+	title string,
+	listing *yunyun.Listing,
+) []*yunyun.Page {
+	total := narumi.CountListingPages(site, listing)
+	pages := make([]*yunyun.Page, total)
+	for i := range pages {
+		current := *listing
+		current.Page = i + 1
+		pages[i] = newGeneratedPage(conf, paginatedLocation(location, i+1), title, &yunyun.Content{
+			Type:    yunyun.TypeListing,
+			Listing: &current,
+		})
+		// Don't bother with navigation if everything fits on one page.
+		if total < 2 {
+			continue
+		}
+		pages[i].Pagination = &yunyun.Pagination{Current: i + 1, Total: total}
+		if i > 0 {
+			pages[i].Pagination.Prev = paginatedLocation(location, i)
+		}
+		if i+1 < total {
+			pages[i].Pagination.Next = paginatedLocation(location, i+2)
+		}
+	}
+	return pages
+}
+
+// paginatedLocation returns the location of the n-th page of the listing.
+func paginatedLocation(location yunyun.RelativePathDir, n int) yunyun.RelativePathDir {
+	if n < 2 {
+		return location
+	}
+	return yunyun.RelativePathDir(filepath.Join(string(location), paginationDir, strconv.Itoa(n)))
+}
@@ -0,0 +1,17 @@
+package kazuma
+
+import (
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// searchPages generates the search page, unless it's written by hand.
+func searchPages(conf *alpha.DarknessConfig, site *yunyun.Site) []*yunyun.Page {
+	if !conf.Search.Enable || site.Page(conf.Search.Dir) != nil {
+		return nil
+	}
+	return []*yunyun.Page{
+		newGeneratedPage(conf, conf.Search.Dir, conf.Search.Title,
+			&yunyun.Content{Type: yunyun.TypeSearch}),
+	}
+}
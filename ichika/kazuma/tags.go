@@ -0,0 +1,35 @@
+package kazuma
+
+import (
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// tagPages generates a listing page for every tag and the tags index.
+func tagPages(conf *alpha.DarknessConfig, site *yunyun.Site) []*yunyun.Page {
+	if !conf.Tags.Enable {
+		return nil
+	}
+	tags := narumi.SiteTags(site)
+	pages := make([]*yunyun.Page, 0, len(tags)+1)
+
+	// The index of all the tags.
+	if site.Page(conf.Tags.Dir) == nil {
+		pages = append(pages, newGeneratedPage(conf, conf.Tags.Dir, conf.Tags.Title,
+			&yunyun.Content{Type: yunyun.TypeTagCloud}))
+	}
+
+	// And every tag gets its own page.
+	for _, tag := range tags {
+		location := conf.Tags.TagLocation(tag.Slug)
+		if site.Page(location) != nil {
+			conf.Runtime.Logger.Warn("Tag page is already written by hand", "tag", tag.Name, "location", location)
+			continue
+		}
+		listing := narumi.TagListing(tag.Slug)
+		listing.PageSize = conf.Tags.PageSize
+		pages = append(pages, paginatedPages(conf, site, location, tag.Name, listing)...)
+	}
+	return pages
+}
@@ -2,6 +2,8 @@ package kuroko
 
 import "github.com/charmbracelet/log"
 
+// The flags of the command line, which are only read by ichika
+// and passed along to alpha as options.
 var (
 	// WorkDir is the directory to look for files.
 	WorkDir = "."
@@ -53,6 +55,10 @@ var (
 	// project's .darkness directory with then files discovered, duration,
 	// and the output file that they reached.
 	BuildReport bool
+
+	// Strict makes the build fail on the warnings, like links that lead
+	// nowhere or missing images, not only on the files it couldn't write.
+	Strict bool
 )
 
 // LogLevel returns the log level as defined in kuroko
@@ -2,23 +2,32 @@ package makima
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/export"
 	"github.com/thecsw/darkness/v3/ichika/chiho"
 	"github.com/thecsw/darkness/v3/ichika/misaka"
+	"github.com/thecsw/darkness/v3/ichika/tohru"
+	"github.com/thecsw/darkness/v3/ichika/yor"
 	"github.com/thecsw/darkness/v3/parse"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
 // Control is the struct that is passed across darkness to build the site.
 type Control struct {
+	// Context stops the page between its stages once done, nil never stops it.
+	Context context.Context
 	// Conf is the configuration for the site.
 	Conf *alpha.DarknessConfig
 	// Parser is the parser to use for the site.
@@ -33,31 +42,49 @@ type Control struct {
 
 	// Page is the parsed page.
 	Page *yunyun.Page
+	// Site is the summary of all the pages, set before exporting.
+	Site *yunyun.Site
 
 	// OutputFilename is the filename of the output file.
 	OutputFilename string
 	// Output is the output file's contents.
 	Output io.Reader
+
+	// Assets are where the static files referenced by the output go, nil
+	// if the outputs are written next to the inputs.
+	Assets *tohru.Assets
 }
 
-// Read reads the input file and returns the Control.
-func (c *Control) Read() (Woof, error) {
+// Read reads the input file and returns the Control, the input that
+// is already there (like the one remembered from a previous build) is kept.
+func (c *Control) Read() (woof Woof, err error) {
+	defer c.recover("reading", &err)
+	if err := c.cancelled(); err != nil {
+		return nil, err
+	}
+	if len(c.Input) > 0 {
+		return c, nil
+	}
 	defer puck.
 		Stopwatch("Read", "input", c.Conf.Runtime.WorkDir.Rel(c.InputFilename)).
-		RecordWithFile(misaka.RecordReadTime, c.InputFilename)
+		RecordWithFile(misaka.For(c.Conf).RecordReadTime, c.InputFilename, c.Conf.Runtime.Logger)
 	file, err := os.ReadFile(filepath.Clean(string(c.InputFilename)))
 	if err != nil {
-		return nil, fmt.Errorf("reading input file %s: %v", c.InputFilename, err)
+		return nil, c.fail(fmt.Errorf("reading input file %s: %v", c.InputFilename, err))
 	}
 	c.Input = string(file)
 	return c, nil
 }
 
 // Parse parses the input file and returns the Control.
-func (c *Control) Parse() Woof {
+func (c *Control) Parse() (woof Woof, err error) {
+	defer c.recover("parsing", &err)
+	if err := c.cancelled(); err != nil {
+		return nil, err
+	}
 	defer puck.
 		Stopwatch("Parsed", "input", c.Conf.Runtime.WorkDir.Rel(c.InputFilename)).
-		RecordWithFile(misaka.RecordParseTime, c.InputFilename)
+		RecordWithFile(misaka.For(c.Conf).RecordParseTime, c.InputFilename, c.Conf.Runtime.Logger)
 	c.Page = c.Parser.Do(c.Conf.Runtime.WorkDir.Rel(c.InputFilename), c.Input)
 
 	// The user (probably Sandy) may want to see the parsed pages in json format for
@@ -68,46 +95,164 @@ func (c *Control) Parse() Woof {
 		enc.SetIndent("", "\t")
 		err := enc.Encode(c.Page)
 		if err != nil {
-			puck.Logger.Warn("Failed to convert page to json", "page", c.InputFilename, "error", err)
-			return c
+			c.Conf.Runtime.Logger.Warn("Failed to convert page to json", "page", c.InputFilename, "error", err)
+			return c, nil
 		}
 		targetFile := c.Conf.Project.InputFilenameToDebugStruct(c.InputFilename)
-		if err := writeNewFile(targetFile, &buf); err != nil {
-			puck.Logger.Warn("Failed to write converted json page", "error", err)
-			return c
+		if err := c.writeFile(targetFile, &buf); err != nil {
+			c.Conf.Runtime.Logger.Warn("Failed to write converted json page", "error", err)
+			return c, nil
 		}
-		puck.Logger.Debug("Wrote parsed page as json", "parsed", c.Conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(targetFile)))
+		c.Conf.Runtime.Logger.Debug("Wrote parsed page as json", "parsed", c.Conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(targetFile)))
 	}
 
+	return c, nil
+}
+
+// Source returns the contents of the input file, empty if not read yet.
+func (c *Control) Source() string {
+	return c.Input
+}
+
+// ParsedPage returns the parsed page, nil if not parsed yet.
+func (c *Control) ParsedPage() *yunyun.Page {
+	return c.Page
+}
+
+// WithSite sets the site that the page will be exported with.
+func (c *Control) WithSite(site *yunyun.Site) Woof {
+	c.Site = site
 	return c
 }
 
 // Export exports the parsed page and returns the Control.
-func (c *Control) Export() Woof {
+func (c *Control) Export() (woof Woof, err error) {
+	defer c.recover("exporting", &err)
+	if err := c.cancelled(); err != nil {
+		return nil, err
+	}
 	defer puck.
 		Stopwatch("Exported", "input", c.Conf.Runtime.WorkDir.Rel(c.InputFilename)).
-		RecordWithFile(misaka.RecordExportTime, c.InputFilename)
+		RecordWithFile(misaka.For(c.Conf).RecordExportTime, c.InputFilename, c.Conf.Runtime.Logger)
 	c.OutputFilename = c.Conf.Project.InputFilenameToOutput(c.InputFilename)
-	c.Output = c.Exporter.Do(chiho.EnrichPage(c.Conf, c.Page))
-	return c
+	c.Output = c.Exporter.Do(chiho.EnrichPage(c.Conf, c.Site, c.Page))
+	misaka.For(c.Conf).RecordStats(c.InputFilename, c.Page.Stats)
+	return c, nil
 }
 
 // Write copies the exported contents onto the output file.
-func (c *Control) Write() error {
+func (c *Control) Write() (err error) {
+	defer c.recover("writing", &err)
+	if err := c.cancelled(); err != nil {
+		return err
+	}
 	defer puck.
 		Stopwatch("Wrote", "output", c.Conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(c.OutputFilename))).
-		RecordWithFile(misaka.RecordWriteTime, c.InputFilename)
-	return writeNewFile(c.OutputFilename, c.Output)
+		RecordWithFile(misaka.For(c.Conf).RecordWriteTime, c.InputFilename, c.Conf.Runtime.Logger)
+	if c.Assets != nil || len(c.Conf.Hooks.Filters) > 0 {
+		data, err := io.ReadAll(c.Output)
+		if err != nil {
+			return c.fail(fmt.Errorf("reading exported %s: %v", c.InputFilename, err))
+		}
+		if data, err = c.filter(data); err != nil {
+			return err
+		}
+		c.Assets.Find(c.Conf, c.Page.Location, data)
+		c.Output = bytes.NewReader(data)
+	}
+	return c.fail(c.writeFile(c.OutputFilename, c.Output))
+}
+
+// recover turns a panic in the stage into the error of this page, so that
+// only the page fails and the rest of the site still builds.
+func (c *Control) recover(stage string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	input := c.Conf.Runtime.WorkDir.Rel(c.InputFilename)
+	c.Conf.Runtime.Logger.Error("Page panicked", "input", input, "stage", stage, "panic", r, "stack", string(debug.Stack()))
+	*err = c.fail(fmt.Errorf("%s panicked: %v%s", stage, r, panicFrames(panicFramesShown)))
+}
+
+// panicFramesShown is how many frames of the panic go into the page's problem,
+// the whole stack is in the log.
+const panicFramesShown = 5
+
+// panicFrames returns the top frames of the panicking stack, each on its own
+// line, when called from a deferred recover.
+func panicFrames(shown int) string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+	var b strings.Builder
+	panicking := false
+	for more := true; more && shown > 0; {
+		var frame runtime.Frame
+		frame, more = frames.Next()
+		// Everything up to the panic itself is the recovering, and the
+		// runtime's frames, like the index checks, say nothing new.
+		if !panicking || strings.HasPrefix(frame.Function, "runtime.") {
+			panicking = panicking || frame.Function == "runtime.gopanic"
+			continue
+		}
+		fmt.Fprintf(&b, "\n     at %s (%s:%d)", frame.Function, frame.File, frame.Line)
+		shown--
+	}
+	return b.String()
+}
+
+// context returns the context of the page, one that's never done if not set.
+func (c *Control) context() context.Context {
+	if c.Context == nil {
+		return context.Background()
+	}
+	return c.Context
 }
 
-// writeNewFile is a makima utility to flush a reader into a new file.
-func writeNewFile(target string, from io.Reader) error {
-	file, err := os.Create(target)
+// cancelled returns the error of the context if it's done, the page is not
+// failed by it, as it's the build that stops.
+func (c *Control) cancelled() error {
+	return c.context().Err()
+}
+
+// fail records the error in the build's problems, so the build fails, and returns it.
+func (c *Control) fail(err error) error {
+	if err != nil {
+		c.Conf.Runtime.Problems.Fail(string(c.Conf.Runtime.WorkDir.Rel(c.InputFilename)), err)
+	}
+	return err
+}
+
+// filter pipes the exported page through the hook filters, a failing filter
+// fails the build, but the page is still written as it was exported. Only
+// the error of a done context is returned, so that the page is not written.
+func (c *Control) filter(data []byte) ([]byte, error) {
+	filtered, err := yor.Filter(c.context(), c.Conf, c.Page, c.OutputFilename, data)
+	if err := c.cancelled(); err != nil {
+		return nil, err
+	}
 	if err != nil {
-		return fmt.Errorf("creating output file %s: %v", target, err)
+		c.fail(err)
+		return data, nil
 	}
-	if _, err := io.Copy(file, from); err != nil {
-		return fmt.Errorf("writing to output file %s: %v", target, err)
+	return filtered, nil
+}
+
+// writeFile is a makima utility to flush a reader into the file, unless the file
+// already has the same contents, and to record it in the build manifest.
+func (c *Control) writeFile(target string, from io.Reader) error {
+	hash, written, err := uiharu.WriteFile(target, func(w io.Writer) error {
+		_, err := io.Copy(w, from)
+		return err
+	})
+	if err != nil {
+		return err
 	}
+	c.Conf.Runtime.Manifest.Record(
+		c.Conf.Runtime.WorkDir.Rel(c.InputFilename),
+		c.Conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(target)),
+		hash,
+		written,
+	)
 	return nil
 }
@@ -0,0 +1,46 @@
+package makima
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// panickingParser fails the way a buggy parser would.
+type panickingParser struct{}
+
+// Do panics with an index out of range.
+func (panickingParser) Do(yunyun.RelativePathFile, string) *yunyun.Page {
+	var lines []string
+	return &yunyun.Page{Title: lines[1]}
+}
+
+// TestParsePanic tests that a panic in a page is attributed to its file,
+// along with the frames that panicked
+func TestParsePanic(t *testing.T) {
+	conf := &alpha.DarknessConfig{}
+	conf.Runtime.WorkDir = "/site"
+	conf.Runtime.Problems = hitagi.NewReport(nil)
+	conf.Runtime.Logger = log.New(io.Discard)
+	control := &Control{
+		Conf:          conf,
+		Parser:        panickingParser{},
+		InputFilename: "/site/notes/a.org",
+		Input:         "* A",
+	}
+	if _, err := control.Parse(); err == nil || !strings.Contains(err.Error(), "parsing panicked") {
+		t.Fatalf("expected the panic as an error, got %v", err)
+	}
+	problems := conf.Runtime.Problems.Problems()
+	if len(problems) != 1 || problems[0].File != "notes/a.org" || !problems[0].Fatal {
+		t.Fatalf("expected one failure of notes/a.org, got %+v", problems)
+	}
+	if !strings.Contains(problems[0].Message, "at github.com/thecsw/darkness/v3/ichika/makima.panickingParser.Do (") {
+		t.Errorf("expected the panicking frame in the problem, got %q", problems[0].Message)
+	}
+}
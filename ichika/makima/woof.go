@@ -1,12 +1,21 @@
 package makima
 
+import "github.com/thecsw/darkness/v3/yunyun"
+
 // Woof is the interface that wraps the basic methods of a makima parser.
 type Woof interface {
+	// Read reads the input file.
 	Read() (Woof, error)
 	// Parse parses the input internally.
-	Parse() Woof
+	Parse() (Woof, error)
+	// Source returns the contents of the input file.
+	Source() string
+	// ParsedPage returns the parsed page.
+	ParsedPage() *yunyun.Page
+	// WithSite sets the site to export with.
+	WithSite(site *yunyun.Site) Woof
 	// Export exports the result internally.
-	Export() Woof
+	Export() (Woof, error)
 	// Write flushes the exported data.
 	Write() error
 }
@@ -8,8 +8,10 @@ import (
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/ichika/hizuru"
 	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/rei"
 )
 
 // if true, darkness cleans with no output
@@ -48,8 +50,14 @@ func CleanCommandFunc() {
 	removeOutputFiles(conf)
 }
 
-// removeOutputFiles is the low-level command to be used when cleaning data.
+// removeOutputFiles is the low-level command to be used when cleaning data. If
+// there is a build manifest, exactly what the builds wrote gets removed.
 func removeOutputFiles(conf *alpha.DarknessConfig) {
+	manifestFilename := conf.Runtime.WorkDir.Join(uiharu.ManifestFile)
+	if exists, _ := rei.FileExists(string(manifestFilename)); exists {
+		removeManifestOutputs(conf, manifestFilename)
+		return
+	}
 	inputFilenames := hizuru.FindFilesByExtSimple(conf)
 	for _, inputFilename := range inputFilenames {
 		toRemove := conf.Project.InputFilenameToOutput(inputFilename)
@@ -67,7 +75,29 @@ func removeOutputFiles(conf *alpha.DarknessConfig) {
 
 	}
 	// don't forget to remove the build logfile if found
-	_ = os.Remove(puck.LastBuildTimestampFile) // ignore
+	_ = os.Remove(string(conf.Runtime.OutputDir.Join(puck.LastBuildTimestampFile))) // ignore
+}
+
+// removeManifestOutputs removes the outputs recorded in the build manifest and
+// the manifest itself. Outputs changed since the build are kept and reported.
+func removeManifestOutputs(conf *alpha.DarknessConfig, manifestFilename yunyun.FullPathFile) {
+	manifest, err := uiharu.Load(manifestFilename)
+	if err != nil {
+		fmt.Println(uiharu.ManifestFile, "is broken:", err)
+		return
+	}
+	for _, output := range manifest.Outputs() {
+		if err := output.Remove(yunyun.FullPathDir(conf.Runtime.WorkDir)); err != nil {
+			fmt.Println(output.File, "survived:", err)
+			continue
+		}
+		if !isQuietMegumin {
+			fmt.Println(output.File, "went boom!")
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+	// The survivors were changed by someone else, so they are not ours anymore.
+	_ = os.Remove(string(manifestFilename))
 }
 
 // delayedLinesPrint prints lines with a delay.
@@ -21,6 +21,9 @@ func MisaCommandFunc() {
 	addHolosceneTitles := misaCmd.Bool("holoscene-titles", false, "add holoscene titles")
 	rss := misaCmd.String("rss", "", "generate an rss file")
 	rssDirectories := misaCmd.String("rss-dirs", "", "look up specific dirs")
+	feeds := misaCmd.Bool("feeds", false, "generate all feeds listed in the config")
+	sitemap := misaCmd.Bool("sitemap", false, "generate the sitemap")
+	searchIndex := misaCmd.Bool("search", false, "generate the search index")
 
 	indexNowKeyPath := misaCmd.String("index-now-key", "", "path to the index-now key")
 
@@ -31,14 +34,17 @@ func MisaCommandFunc() {
 
 	puck.Logger.SetPrefix("Misa 🍎 ")
 
-	if len(*rss) > 0 || len(*indexNowKeyPath) > 0 {
+	if len(*rss) > 0 || *feeds || *sitemap || *searchIndex || len(*indexNowKeyPath) > 0 {
 		options.Dev = false
 	}
 	conf := alpha.BuildConfig(options)
 
 	if *buildGalleryPreviews {
-		misa.BuildGalleryFiles(conf, *dryRun)
-		os.Exit(0)
+		// Stop between the downloads on Ctrl-C.
+		ctx, stop := interruptible()
+		err := misa.BuildGalleryFiles(ctx, conf, *dryRun)
+		stop()
+		exitMisa("Building the gallery previews", err)
 	}
 	if *removeGalleryPreviews {
 		misa.RemoveGalleryFiles(conf, *dryRun)
@@ -49,11 +55,22 @@ func MisaCommandFunc() {
 		os.Exit(0)
 	}
 	if len(*rss) > 0 {
-		misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), *dryRun)
-		os.Exit(0)
+		exitMisa("Generating the rss feed",
+			misa.GenerateRssFeed(conf, *rss, strings.Split(*rssDirectories, ","), "", *dryRun))
+	}
+	if *feeds {
+		exitMisa("Generating the feeds", misa.GenerateFeeds(conf, *dryRun))
+	}
+	if *sitemap {
+		exitMisa("Generating the sitemap", misa.GenerateSitemap(conf, *dryRun))
+	}
+	if *searchIndex {
+		exitMisa("Generating the search index", misa.GenerateSearchIndex(conf, *dryRun))
 	}
 	if len(*indexNowKeyPath) > 0 {
-		misa.NotifySearchEngines(conf, yunyun.RelativePathFile(*indexNowKeyPath), *dryRun)
+		ctx, stop := interruptible()
+		misa.NotifySearchEngines(ctx, conf, yunyun.RelativePathFile(*indexNowKeyPath), *dryRun)
+		stop()
 		os.Exit(0)
 	}
 
@@ -61,3 +78,13 @@ func MisaCommandFunc() {
 		fmt.Println("I don't know what you want me to do, see -help")
 	}
 }
+
+// exitMisa exits darkness once the misa tool is done, with a failure
+// if the tool returned an error.
+func exitMisa(what string, err error) {
+	if err != nil {
+		puck.Logger.Error(what, "err", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,77 @@
+package misa
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/darkness/v3/yunyun/atom"
+)
+
+// GenerateAtomFeed generates an Atom feed based on the given config and directories,
+// only with the pages in the language, if given.
+func GenerateAtomFeed(conf *alpha.DarknessConfig, atomFilename string, atomDirectories []string, language string, dryRun bool) error {
+	initLog()
+	channel := collectFeed(conf, atomDirectories, language)
+
+	// Create Atom entries.
+	entries := make([]*atom.Entry, len(channel.Items))
+	for i, item := range channel.Items {
+		summary := item.Description
+		if item.Stats != nil {
+			summary += " (" + narumi.ReadingTimeText(conf, language, *item.Stats) + ")"
+		}
+		entries[i] = &atom.Entry{
+			Id:         item.Id,
+			Title:      atom.PlainText(strings.TrimSpace(item.Title)),
+			Updated:    item.Published.Format(atom.AtomFormat),
+			Published:  item.Published.Format(atom.AtomFormat),
+			Links:      []*atom.Link{{Href: item.Link, Rel: "alternate", Type: "text/html"}},
+			Categories: []*atom.Category{{Term: item.CategoryName, Scheme: item.CategoryLink}},
+			Summary:    atom.PlainText(summary),
+		}
+		if len(item.Author) > 0 {
+			entries[i].Authors = []*atom.Person{{Name: item.Author}}
+		}
+	}
+
+	// Default author of the feed, entries without authors inherit it.
+	authorName := conf.RSS.DefaultAuthor
+	if len(authorName) < 1 {
+		authorName = conf.Author.Name
+	}
+	author := &atom.Person{Name: authorName, Uri: conf.Url}
+	if conf.Author.EmailEnable {
+		author.Email = conf.Author.Email
+	}
+
+	// Create the final feed.
+	feed := &atom.Feed{
+		Xmlns:    atom.AtomNamespace,
+		Lang:     channel.Language,
+		Id:       conf.Url,
+		Title:    atom.PlainText(channel.Title),
+		Subtitle: atom.PlainText(channel.Description),
+		Updated:  channel.Updated.Format(atom.AtomFormat),
+		Authors:  []*atom.Person{author},
+		Links: []*atom.Link{
+			{Href: string(conf.Runtime.Join(yunyun.RelativePathFile(atomFilename))), Rel: "self", Type: alpha.FeedMimeTypes[alpha.FeedFormatAtom]},
+			{Href: conf.Url, Rel: "alternate", Type: "text/html"},
+		},
+		Generator: &atom.Generator{Uri: feedGeneratorUri, Value: feedGenerator},
+		Entries:   entries,
+	}
+	if len(conf.RSS.Copyright) > 0 {
+		feed.Rights = atom.PlainText(conf.RSS.Copyright)
+	}
+	if len(conf.RSS.Category) > 0 {
+		feed.Categories = []*atom.Category{{Term: conf.RSS.Category}}
+	}
+
+	if err := writeGeneratedFile(conf, atomFilename, dryRun, encodeXml(feed)); err != nil {
+		return fmt.Errorf("writing atom feed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package misa
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/ichika/hizuru"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/gana"
+)
+
+const (
+	// feedGenerator is the generator string used in the feeds.
+	feedGenerator = "Darkness (sandyuraz.com/darkness)"
+
+	// feedGeneratorUri is the generator uri used in the feeds.
+	feedGeneratorUri = "https://sandyuraz.com/darkness"
+)
+
+// feedChannel is the format-agnostic description of the whole feed.
+type feedChannel struct {
+	// Title is the title of the website.
+	Title string
+	// Description is the description of the root page or the rss config.
+	Description string
+	// Language is the language of the feed, can be empty.
+	Language string
+	// Updated is the date of the latest item, or now if there are none.
+	Updated time.Time
+	// Items are the feed items sorted in the descending order of dates.
+	Items []feedItem
+}
+
+// feedItem is the format-agnostic item that all the feed formats are built from.
+type feedItem struct {
+	// Id is the permanent identifier of the item.
+	Id string
+	// Title is the final title with the rss prefix and title overrides, kept as
+	// the rss feed always had it, the other formats trim the space of no prefix.
+	Title string
+	// Link is the full url of the page.
+	Link string
+	// Description is the plain text description of the page.
+	Description string
+	// Author is the author of the page, can be empty.
+	Author string
+	// CategoryName is the title of the parent page.
+	CategoryName string
+	// CategoryLink is the full url of the parent page.
+	CategoryLink string
+	// Published is the publication date, with the configured timezone and default hour.
+	Published time.Time
+	// Stats are the word count and reading time, nil if those are not shown.
+	Stats *yunyun.PageStats
+}
+
+// GenerateFeeds generates all the feeds listed in the config, the ones
+// that fail don't stop the rest, their errors are returned together.
+func GenerateFeeds(conf *alpha.DarknessConfig, dryRun bool) error {
+	initLog()
+	if len(conf.Feeds) < 1 {
+		logger.Warn("No feeds found in the config, add some with [[feeds]]")
+		return nil
+	}
+	errs := make([]error, 0, len(conf.Feeds))
+	for _, feed := range conf.Feeds {
+		switch feed.Format {
+		case alpha.FeedFormatRss:
+			errs = append(errs, GenerateRssFeed(conf, string(feed.Path), feed.Dirs, feed.Language, dryRun))
+		case alpha.FeedFormatAtom:
+			errs = append(errs, GenerateAtomFeed(conf, string(feed.Path), feed.Dirs, feed.Language, dryRun))
+		case alpha.FeedFormatJson:
+			errs = append(errs, GenerateJsonFeed(conf, string(feed.Path), feed.Dirs, feed.Language, dryRun))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// collectFeed builds all the pages in the given directories and turns them
+// into feed items, skipping drafts and pages without dates. If the language
+// is given, only the pages in that language are included.
+func collectFeed(conf *alpha.DarknessConfig, directories []string, language string) *feedChannel {
+	// Get all all the pages we can build out.
+	allPages := hizuru.BuildPagesSimple(conf, directories)
+	if len(language) > 0 {
+		allPages = gana.Filter(func(page *yunyun.Page) bool { return page.Language == language }, allPages)
+	}
+	// Try to retrieve the top root page (or its translation) to get channel description.
+	// If not found, use the website's title as the description.
+	topPage := gana.First(gana.Filter(func(page *yunyun.Page) bool {
+		return page.Location == "." || (len(language) > 0 && yunyun.RelativePathTrim(page.File) == ".")
+	}, allPages))
+	rootDescription := conf.RSS.Description
+	if topPage != nil {
+		rootDescription = narumi.Description(topPage, conf.Website.DescriptionLength*4)
+	}
+	// If both the top page and RSS config have no description, default to the title.
+	if len(rootDescription) < 1 {
+		rootDescription = conf.Title
+	}
+
+	sort.Slice(allPages, func(i, j int) bool { return allPages[i].Title < allPages[j].Title })
+
+	// Get all pages that have dates defined, we only use those to be included in the feed.
+	pages := Pages(gana.Filter(func(page *yunyun.Page) bool {
+		_, dateFound := narumi.ConvertHoloscene(page.Date)
+		if !dateFound {
+			logger.Debug("Skipping because no date found", "page", page.Location)
+		}
+		return dateFound
+	}, allPages))
+
+	// Sort the pages in descending order of dates.
+	sort.Sort(pages)
+
+	// Create feed items.
+	items := make([]feedItem, 0, len(pages))
+
+	func() {
+		defer puck.Stopwatch("Built feed pages", "num", len(pages)).Record()
+		for _, page := range pages {
+			// Skip drafts.
+			if page.Accoutrement.Draft.IsEnabled() {
+				logger.Warn("Skipping draft", "page", page.Location)
+				continue
+			}
+			// Create the category name and location.
+			categoryName, categoryLocation := page.Title, page.Location
+			if categoryPage := getCategory(page, allPages); categoryPage != nil {
+				categoryName = categoryPage.Title
+				categoryLocation = categoryPage.Location
+			}
+
+			// Override the title if the page has a custom RSS title.
+			finalTitle := page.Title
+			if len(page.Accoutrement.RssTitle) > 0 {
+				finalTitle = page.Accoutrement.RssTitle
+			}
+
+			// Add the RSS prefix to the title.
+			finalTitle = page.Accoutrement.RssPrefix + " " + finalTitle
+
+			// Let's update the time if needed.
+			parsedDate, isValid := narumi.ConvertHoloscene(page.Date)
+			if !isValid {
+				logger.Warn("Skipping invalid publication date", "page", page.Location)
+				continue
+			}
+			finalLocation, err := time.LoadLocation(conf.RSS.Timezone)
+			// Fallback to UTC
+			if err != nil {
+				finalLocation = time.UTC
+			}
+			hour, minute := parsedDate.Hour(), parsedDate.Minute()
+			if hour == 0 && minute == 0 {
+				hour = conf.RSS.DefaultHour
+				minute = 0
+			}
+			finalDate := time.Date(
+				parsedDate.Year(), parsedDate.Month(), parsedDate.Day(),
+				hour, minute, 0, 0, finalLocation)
+
+			// Show the reading time, unless the page opted out.
+			var stats *yunyun.PageStats
+			if conf.Reading.Enable && !page.Accoutrement.ReadingTime.IsDisabled() {
+				pageStats := narumi.PageStatistics(conf, page)
+				stats = &pageStats
+			}
+
+			// Create the feed item.
+			items = append(items, feedItem{
+				Id:           conf.Url + string(page.Location),
+				Title:        yunyun.RemoveFormatting(yunyun.FancyText(finalTitle)),
+				Link:         string(conf.Runtime.JoinDir(page.Location)),
+				Description:  yunyun.FancyText(narumi.Description(page, conf.Website.DescriptionLength*4)),
+				Author:       page.Author,
+				CategoryName: categoryName,
+				CategoryLink: conf.Url + string(categoryLocation),
+				Published:    finalDate,
+				Stats:        stats,
+			})
+		}
+	}()
+
+	// Try to find the latest date, if none, then reuse the build date
+	updated := time.Now()
+	if len(items) > 0 {
+		updated = items[0].Published
+	}
+
+	// The rss config's language is the more specific one, if it's the default language.
+	channelLanguage := language
+	if len(channelLanguage) < 1 || (channelLanguage == conf.Website.Language() && len(conf.RSS.Language) > 0) {
+		channelLanguage = conf.RSS.Language
+	}
+
+	return &feedChannel{
+		Title:       yunyun.FancyText(conf.Title),
+		Description: yunyun.FancyText(rootDescription),
+		Language:    channelLanguage,
+		Updated:     updated,
+		Items:       items,
+	}
+}
+
+var categoryCache = make(map[string]*yunyun.Page)
+
+func getCategory(page *yunyun.Page, pages Pages) *yunyun.Page {
+	categoryName := strings.TrimSuffix(string(page.Location), "/"+filepath.Base(string(page.Location)))
+	if v, ok := categoryCache[categoryName]; ok {
+		return v
+	}
+	for _, allPage := range pages {
+		if allPage.Location == yunyun.RelativePathDir(categoryName) {
+			categoryCache[categoryName] = allPage
+			return allPage
+		}
+	}
+	return nil
+}
+
+// Pages is custom type of slice of pages to enable sorting.
+type Pages []*yunyun.Page
+
+// Len returns the number of pages.
+func (p Pages) Len() int { return len(p) }
+
+// Swap swaps lol.
+func (p Pages) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+
+// Less sorts the array in descending order.
+func (p Pages) Less(i, j int) bool { return mustDate(p[i]).Unix() > mustDate(p[j]).Unix() }
+
+func mustDate(v *yunyun.Page) time.Time {
+	t, f := narumi.ConvertHoloscene(v.Date)
+	if !f {
+		panic("must be date")
+	}
+	return t
+}
@@ -1,6 +1,7 @@
 package misa
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"io"
@@ -15,8 +16,8 @@ import (
 	"github.com/thecsw/darkness/v3/emilia/puck"
 	"github.com/thecsw/darkness/v3/emilia/rem"
 	"github.com/thecsw/darkness/v3/emilia/reze"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/ichika/hizuru"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
 	"github.com/thecsw/rei"
@@ -31,13 +32,14 @@ const (
 )
 
 // BuildGalleryFiles finds all the gallery entries and build a resized blurred
-// preview version of it.
-func BuildGalleryFiles(conf *alpha.DarknessConfig, dryRun bool) {
+// preview version of it. It stops between the items once the context is done, the
+// items that fail are only logged.
+func BuildGalleryFiles(ctx context.Context, conf *alpha.DarknessConfig, dryRun bool) error {
 	initLog()
 	// Make sure the preview directory exists
 	previewDirectory := string(conf.Runtime.WorkDir.Join(yunyun.RelativePathFile(conf.Project.DarknessPreviewDirectory)))
 	if err := rei.Mkdir(previewDirectory); err != nil {
-		puck.Logger.Fatalf("creating preview directory %s: %v", previewDirectory, err)
+		return fmt.Errorf("creating preview directory %s: %v", previewDirectory, err)
 	}
 
 	// Get all the gallery files, some may need nested macros.
@@ -45,11 +47,14 @@ func BuildGalleryFiles(conf *alpha.DarknessConfig, dryRun bool) {
 
 	// Filter out all the files that already exist.
 	missingFiles := gana.Filter(func(item rem.GalleryItem) bool {
-		return !rei.FileMustExist(string(rem.GalleryPreview(conf, item))) || kuroko.Force
+		return !rei.FileMustExist(string(rem.GalleryPreview(conf, item))) || conf.Runtime.Force
 	}, galleryFiles)
 
 	// Build all the missing files.
 	for i, galleryFile := range missingFiles {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		newFile := rem.GalleryPreview(conf, galleryFile)
 
 		// Retrieve image contents reader:
@@ -57,7 +62,7 @@ func BuildGalleryFiles(conf *alpha.DarknessConfig, dryRun bool) {
 		// - For remote files, it's a reader of the response body,
 		//   unless it's vendored, then it's a read of the vendored file.
 		prefix := fmt.Sprintf("[%d/%d] ", i+1, len(missingFiles))
-		sourceImage, err := rem.GalleryItemToImage(conf, galleryFile, "preview", prefix)
+		sourceImage, err := rem.GalleryItemToImage(ctx, conf, galleryFile, "preview", prefix)
 		if err != nil {
 			puck.Logger.Warnf("\nopening a gallery image (%s): %v",
 				filepath.Join(string(galleryFile.Path), string(galleryFile.Item)), err)
@@ -69,35 +74,30 @@ func BuildGalleryFiles(conf *alpha.DarknessConfig, dryRun bool) {
 
 		// Don't save the file if it's in dry run mode.
 		if !dryRun {
-			file, err := os.Create(string(newFile))
-
 			// Create a progress bar.
 			bar := reze.ProgressBar(-1, "misa", prefix, "Resizing", string(conf.Runtime.WorkDir.Rel(newFile)))
-			if err != nil {
-				puck.Logger.Errorf("creating file %s: %v", newFile, err)
-				continue
-			}
 
-			// Write the final preview image file.
-			if err := imgio.JPEGEncoder(galleryJPEGQuality)(io.MultiWriter(file, bar), previewImage); err != nil {
-				puck.Logger.Errorf("encoding image: %v", err)
+			// Write the final preview image file, whole or not at all.
+			_, _, err := uiharu.WriteFile(string(newFile), func(w io.Writer) error {
+				return imgio.JPEGEncoder(galleryJPEGQuality)(io.MultiWriter(w, bar), previewImage)
+			})
+			if err != nil {
+				puck.Logger.Errorf("writing image preview file %s: %v", newFile, err)
 				continue
 			}
 
-			// Close the file.
-			if err := file.Close(); err != nil {
-				puck.Logger.Errorf("closing image preview file %s: %v", newFile, err)
-			}
-
 			// Clear the progressbar.
 			fmt.Print("\r\033[2K")
 			// Log the thing.
 			logger.Info("Resized item",
 				"path", conf.Runtime.WorkDir.Rel(newFile),
 				"dir", galleryFile.Path)
-			rei.Try(bar.Close())
+			if err := bar.Close(); err != nil {
+				logger.Warn("Closing the progress bar", "err", err)
+			}
 		}
 	}
+	return nil
 }
 
 // resizeAndBlur takes an image object and modifies it to preview standards.
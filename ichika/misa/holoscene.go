@@ -12,7 +12,6 @@ import (
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/narumi"
 	"github.com/thecsw/darkness/v3/ichika/hizuru"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/rei"
 )
@@ -75,7 +74,7 @@ func UpdateHoloceneTitles(conf *alpha.DarknessConfig, dryRun bool) {
 		newOutput := narumi.AddHolosceneTitles(string(output), -1)
 
 		// Skip if the same, unless forced.
-		if !kuroko.Force {
+		if !conf.Runtime.Force {
 			if len(output) == len(newOutput) {
 				skipped.Add(1)
 				continue
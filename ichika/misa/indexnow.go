@@ -1,6 +1,7 @@
 package misa
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -29,7 +30,7 @@ var (
 )
 
 // NotifySearchEngines notifies search engines of the updated URLs through indexnow.org.
-func NotifySearchEngines(conf *alpha.DarknessConfig, indexNowKey yunyun.RelativePathFile, dryRun bool) {
+func NotifySearchEngines(ctx context.Context, conf *alpha.DarknessConfig, indexNowKey yunyun.RelativePathFile, dryRun bool) {
 	initLog()
 	if !indexNowKeyRegex.MatchString(string(indexNowKey)) {
 		logger.Fatalf("indexnow file text should match the pattern %s", indexNowKeyPattern)
@@ -53,7 +54,7 @@ func NotifySearchEngines(conf *alpha.DarknessConfig, indexNowKey yunyun.Relative
 
 	// Let's filter the pages only if their most recent modified
 	// date is after the published online (if found).
-	lastBuilt, err := getLastBuilt(conf)
+	lastBuilt, err := getLastBuilt(ctx, conf)
 	if err != nil {
 		logger.Warn("getting last built, sending all pages", "err", err)
 	}
@@ -90,7 +91,7 @@ func NotifySearchEngines(conf *alpha.DarknessConfig, indexNowKey yunyun.Relative
 	// Notify search engines.
 	if !dryRun {
 		for _, searchEngine := range conf.External.SearchEngines {
-			if err := notifySearchEngineMultiple(conf, searchEngine, indexNowKeyContentsString, conf.Url, allPagesRelative); err != nil {
+			if err := notifySearchEngineMultiple(ctx, conf, searchEngine, indexNowKeyContentsString, conf.Url, allPagesRelative); err != nil {
 				logger.Warnf("failed to notify search engine %s: %s", searchEngine, err)
 				continue
 			}
@@ -101,9 +102,9 @@ func NotifySearchEngines(conf *alpha.DarknessConfig, indexNowKey yunyun.Relative
 	}
 }
 
-func getLastBuilt(conf *alpha.DarknessConfig) (*time.Time, error) {
+func getLastBuilt(ctx context.Context, conf *alpha.DarknessConfig) (*time.Time, error) {
 	remotePath := conf.Runtime.UrlPath.JoinPath(puck.LastBuildTimestampFile).String()
-	lastBuilt, err := haruhi.URL(remotePath).ResponseString()
+	lastBuilt, err := haruhi.URL(remotePath).Context(ctx).ResponseString()
 	if err != nil {
 		return nil, fmt.Errorf("collecting last_built.txt from %s: %v", remotePath, err)
 	}
@@ -121,6 +122,7 @@ type indexNowRequestMultipleUrls struct {
 }
 
 func notifySearchEngineMultiple(
+	ctx context.Context,
 	conf *alpha.DarknessConfig,
 	searchEngineUrl string,
 	indexNowKey string,
@@ -144,16 +146,16 @@ func notifySearchEngineMultiple(
 	}
 	resp, cancel, err := haruhi.URL(path).
 		Path("/indexnow").
+		Context(ctx).
 		Method(http.MethodPost).
 		Header("Content-Type", "application/json; charset=utf-8").
 		BodyJson(payload).
 		Response()
 	defer cancel()
-	defer resp.Body.Close()
-
 	if err != nil {
 		return fmt.Errorf("failed to notify search engine %s: %w", searchEngineUrl, err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		errorBody, _ := io.ReadAll(resp.Body)
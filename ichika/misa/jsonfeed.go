@@ -0,0 +1,72 @@
+package misa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/darkness/v3/yunyun/jsonfeed"
+)
+
+// GenerateJsonFeed generates a JSON Feed based on the given config and directories,
+// only with the pages in the language, if given.
+func GenerateJsonFeed(conf *alpha.DarknessConfig, jsonFilename string, jsonDirectories []string, language string, dryRun bool) error {
+	initLog()
+	channel := collectFeed(conf, jsonDirectories, language)
+
+	// Create JSON Feed items.
+	items := make([]*jsonfeed.Item, len(channel.Items))
+	for i, item := range channel.Items {
+		items[i] = &jsonfeed.Item{
+			Id:            item.Id,
+			Url:           item.Link,
+			Title:         strings.TrimSpace(item.Title),
+			ContentText:   item.Description,
+			Summary:       item.Description,
+			DatePublished: item.Published.Format(jsonfeed.JsonFeedFormat),
+			Tags:          []string{item.CategoryName},
+		}
+		if item.Stats != nil {
+			items[i].Reading = &jsonfeed.Reading{
+				Words:      item.Stats.Words,
+				Minutes:    item.Stats.ReadingMinutes(),
+				CodeBlocks: item.Stats.CodeBlocks,
+				Images:     item.Stats.Images,
+			}
+		}
+		if len(item.Author) > 0 {
+			items[i].Authors = []*jsonfeed.Author{{Name: item.Author}}
+		}
+	}
+
+	// Default author of the feed.
+	authorName := conf.RSS.DefaultAuthor
+	if len(authorName) < 1 {
+		authorName = conf.Author.Name
+	}
+
+	// Create the final feed.
+	feed := &jsonfeed.Feed{
+		Version:     jsonfeed.JsonFeedVersion,
+		Title:       channel.Title,
+		HomePageUrl: conf.Url,
+		FeedUrl:     string(conf.Runtime.Join(yunyun.RelativePathFile(jsonFilename))),
+		Description: channel.Description,
+		Authors:     []*jsonfeed.Author{{Name: authorName, Url: conf.Url, Avatar: string(conf.Author.ImagePreComputed)}},
+		Language:    channel.Language,
+		Items:       items,
+	}
+
+	err := writeGeneratedFile(conf, jsonFilename, dryRun, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(feed)
+	})
+	if err != nil {
+		return fmt.Errorf("writing json feed: %w", err)
+	}
+	return nil
+}
@@ -1,14 +1,45 @@
 package misa
 
 import (
+	"fmt"
+	"io"
+	"os"
+
 	"github.com/charmbracelet/log"
+	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
 	"github.com/thecsw/darkness/v3/ichika/kuroko"
+	"github.com/thecsw/darkness/v3/yunyun"
 )
 
 // Logger is the logger for Akane.
 var logger = puck.NewLogger("Misa 🍎", log.WarnLevel)
 
+// initLog sets the logger to the level from the flags, only the commands
+// call it, the builds log through the logger of their config.
 func initLog() {
 	logger = puck.NewLogger("Misa 🍎", kuroko.LogLevel())
 }
+
+// writeGeneratedFile writes the file (or uses stdout on dry runs) with the contents
+// the encoder generates, unless the file already has the same contents.
+func writeGeneratedFile(conf *alpha.DarknessConfig, filename string, dryRun bool, encode func(io.Writer) error) error {
+	if dryRun {
+		if err := encode(os.Stdout); err != nil {
+			return fmt.Errorf("encoding stdout: %v", err)
+		}
+		return nil
+	}
+	target := conf.Runtime.OutputDir.Join(yunyun.RelativePathFile(filename))
+	hash, written, err := uiharu.WriteFile(string(target), encode)
+	if err != nil {
+		return err
+	}
+	relative := conf.Runtime.WorkDir.Rel(target)
+	conf.Runtime.Manifest.Record(uiharu.SiteInput, relative, hash, written)
+	if written {
+		conf.Runtime.Logger.Info("Created file", "path", relative)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package misa
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/ichika/chiho"
+	"github.com/thecsw/darkness/v3/ichika/hizuru"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/darkness/v3/yunyun/search"
+)
+
+const (
+	// searchTextLength is the number of characters of text kept for results.
+	searchTextLength = 280
+
+	// Words found in titles and headings matter more than in the text.
+	searchTitleScore   = 10
+	searchHeadingScore = 5
+	searchTextScore    = 1
+)
+
+// GenerateSearchIndex builds all the pages and writes the search index.
+func GenerateSearchIndex(conf *alpha.DarknessConfig, dryRun bool) error {
+	initLog()
+	site := chiho.BuildSite(conf, hizuru.BuildPagesSimple(conf, nil))
+	if err := WriteSearchIndex(conf, site, dryRun); err != nil {
+		return fmt.Errorf("writing search index: %w", err)
+	}
+	return nil
+}
+
+// WriteSearchIndex writes the search index of the site, split into shards
+// by directory, with a manifest that lists all of them.
+func WriteSearchIndex(conf *alpha.DarknessConfig, site *yunyun.Site, dryRun bool) error {
+	defer puck.Stopwatch("Built search index", "num", len(site.Pages)).Record(conf.Runtime.Logger)
+
+	// Group the pages by their shards, drafts are never searchable.
+	shards := make(map[string]*search.Shard)
+	for _, page := range site.Pages {
+		if page.Draft {
+			continue
+		}
+		name := conf.Search.Shard(page.Location)
+		shard, found := shards[name]
+		if !found {
+			shard = &search.Shard{Documents: make([]*search.Document, 0, 16), Terms: make(map[string][]search.Posting)}
+			shards[name] = shard
+		}
+		indexPage(conf, shard, page)
+	}
+
+	// Write the shards in order, so the manifest doesn't churn.
+	names := make([]string, 0, len(shards))
+	for name := range shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	manifest := &search.Manifest{Shards: make([]*search.ShardInfo, 0, len(names))}
+	for _, name := range names {
+		shardPath := conf.Search.ShardPath(name)
+		if err := writeGeneratedFile(conf, string(shardPath), dryRun, encodeJson(shards[name])); err != nil {
+			return err
+		}
+		manifest.Shards = append(manifest.Shards, &search.ShardInfo{
+			Name:      name,
+			Url:       string(conf.Runtime.Join(shardPath)),
+			Documents: len(shards[name].Documents),
+		})
+	}
+	return writeGeneratedFile(conf, string(conf.Search.ManifestPath()), dryRun, encodeJson(manifest))
+}
+
+// indexPage adds the page to the shard's documents and inverted index.
+func indexPage(conf *alpha.DarknessConfig, shard *search.Shard, page *yunyun.SitePage) {
+	document := len(shard.Documents)
+	text := []rune(page.Text)
+	shard.Documents = append(shard.Documents, &search.Document{
+		Url:      string(conf.Runtime.JoinDir(page.Location)),
+		Title:    yunyun.RemoveFormatting(page.Title),
+		Headings: page.Headings,
+		Text:     string(text[:min(len(text), searchTextLength)]),
+	})
+
+	// Score every term of the page.
+	scores := make(map[string]int)
+	addWords := func(text string, score int) {
+		for _, word := range narumi.Words(text) {
+			if narumi.IsTerm(word) {
+				scores[word] += score
+			}
+		}
+	}
+	addWords(page.Title, searchTitleScore)
+	for _, heading := range page.Headings {
+		addWords(heading, searchHeadingScore)
+	}
+	addWords(page.Text, searchTextScore)
+
+	// Documents are added in order, so the postings stay sorted by document.
+	for term, score := range scores {
+		shard.Terms[term] = append(shard.Terms[term], search.Posting{document, score})
+	}
+}
+
+// encodeJson returns an encoder that writes the compact json of the value.
+func encodeJson(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(v)
+	}
+}
@@ -0,0 +1,141 @@
+package misa
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/ichika/hizuru"
+	"github.com/thecsw/darkness/v3/yunyun"
+	"github.com/thecsw/darkness/v3/yunyun/sitemap"
+)
+
+// GenerateSitemap builds all the pages and writes the sitemap.
+func GenerateSitemap(conf *alpha.DarknessConfig, dryRun bool) error {
+	initLog()
+	if err := WriteSitemap(conf, hizuru.BuildPagesSimple(conf, nil), dryRun); err != nil {
+		return fmt.Errorf("writing sitemap: %w", err)
+	}
+	return nil
+}
+
+// WriteSitemap writes the sitemap of the given pages, skipping drafts and
+// excluded pages. If there are more urls than one sitemap can hold, then
+// the urls are split across multiple sitemaps listed in a sitemap index.
+func WriteSitemap(conf *alpha.DarknessConfig, pages []*yunyun.Page, dryRun bool) error {
+	defer puck.Stopwatch("Built sitemap", "num", len(pages)).Record(conf.Runtime.Logger)
+	urls := sitemapUrls(conf, pages)
+
+	// Most websites will just have the one sitemap.
+	if len(urls) <= sitemap.MaxUrls {
+		return writeGeneratedFile(conf, string(conf.Sitemap.Path), dryRun, encodeXml(&sitemap.UrlSet{
+			Xmlns: sitemap.SitemapNamespace,
+			Urls:  urls,
+		}))
+	}
+
+	// Otherwise, split them up and write the index.
+	now := time.Now().Format(sitemap.SitemapFormat)
+	index := &sitemap.Index{Xmlns: sitemap.SitemapNamespace}
+	for i := 0; i*sitemap.MaxUrls < len(urls); i++ {
+		chunk := urls[i*sitemap.MaxUrls : min((i+1)*sitemap.MaxUrls, len(urls))]
+		chunkPath := sitemapChunkPath(conf.Sitemap.Path, i+1)
+		err := writeGeneratedFile(conf, string(chunkPath), dryRun, encodeXml(&sitemap.UrlSet{
+			Xmlns: sitemap.SitemapNamespace,
+			Urls:  chunk,
+		}))
+		if err != nil {
+			return err
+		}
+		index.Sitemaps = append(index.Sitemaps, &sitemap.Sitemap{
+			Loc:     string(conf.Runtime.Join(chunkPath)),
+			LastMod: now,
+		})
+	}
+	return writeGeneratedFile(conf, string(conf.Sitemap.Path), dryRun, encodeXml(index))
+}
+
+// sitemapUrls returns the sorted sitemap urls of pages that should be in the sitemap.
+func sitemapUrls(conf *alpha.DarknessConfig, pages []*yunyun.Page) []*sitemap.Url {
+	// Walk the git history only once for all the pages.
+	gitLastModified, err := alpha.ExtractGitLastModifiedAll(conf)
+	if err != nil {
+		conf.Runtime.Logger.Warn("Couldn't read git history, falling back to file times", "err", err)
+	}
+
+	urls := make([]*sitemap.Url, 0, len(pages))
+	for _, page := range pages {
+		// Drafts never make it into the sitemap.
+		if page.Accoutrement.Draft.IsEnabled() {
+			continue
+		}
+		// Pages can opt out (or back in) with the sitemap option.
+		rule := conf.Sitemap.Rule(page.Location)
+		if page.Accoutrement.Sitemap.IsDisabled() || (rule.Exclude && !page.Accoutrement.Sitemap.IsEnabled()) {
+			conf.Runtime.Logger.Debug("Excluding from sitemap", "page", page.Location)
+			continue
+		}
+		url := &sitemap.Url{
+			Loc:        string(conf.Runtime.JoinDir(page.Location)),
+			ChangeFreq: rule.ChangeFreq,
+			Priority:   rule.Priority,
+		}
+		if len(page.Accoutrement.SitemapChangeFreq) > 0 {
+			url.ChangeFreq = page.Accoutrement.SitemapChangeFreq
+		}
+		if len(page.Accoutrement.SitemapPriority) > 0 {
+			url.Priority = page.Accoutrement.SitemapPriority
+		}
+		if lastModified, found := pageLastModified(conf, gitLastModified, page); found {
+			url.LastMod = lastModified.Format(sitemap.SitemapFormat)
+		}
+		urls = append(urls, url)
+	}
+
+	// Keep the order stable, so the sitemap doesn't churn between builds.
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+	return urls
+}
+
+// pageLastModified returns the git date of when the page's source was last
+// modified, falling back to the file's modification time.
+func pageLastModified(
+	conf *alpha.DarknessConfig,
+	gitLastModified map[yunyun.RelativePathFile]time.Time,
+	page *yunyun.Page,
+) (time.Time, bool) {
+	if lastModified, found := gitLastModified[page.File]; found {
+		return lastModified, true
+	}
+	info, err := os.Stat(string(conf.Runtime.WorkDir.Join(page.File)))
+	if err != nil {
+		conf.Runtime.Logger.Debug("Couldn't stat page source", "page", page.File, "err", err)
+		return time.Time{}, false
+	}
+	return info.ModTime(), true
+}
+
+// sitemapChunkPath returns the path of the n-th sitemap, like `sitemap-2.xml`.
+func sitemapChunkPath(path yunyun.RelativePathFile, n int) yunyun.RelativePathFile {
+	ext := filepath.Ext(string(path))
+	return yunyun.RelativePathFile(fmt.Sprintf("%s-%d%s", strings.TrimSuffix(string(path), ext), n, ext))
+}
+
+// encodeXml returns an encoder that writes the xml header and the indented value.
+func encodeXml(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		encoder := xml.NewEncoder(w)
+		encoder.Indent("", "  ")
+		return encoder.Encode(v)
+	}
+}
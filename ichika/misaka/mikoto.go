@@ -5,20 +5,26 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
-
+	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
-var (
-	recordedFiles        = sync.Map{}
-	recordedFilesCounter = atomic.Int32{}
+// Recorder records the times and stats of the files of a site's build,
+// the nil recorder records nothing.
+type Recorder struct {
+	recordedFiles        sync.Map
+	recordedFilesCounter atomic.Int32
 
-	readTimes   = sync.Map{}
-	parseTimes  = sync.Map{}
-	exportTimes = sync.Map{}
-	writeTimes  = sync.Map{}
-)
+	readTimes   sync.Map
+	parseTimes  sync.Map
+	exportTimes sync.Map
+	writeTimes  sync.Map
+
+	pageStats sync.Map
+}
+
+// recorderKey is the key of the recorder in the build state of the site.
+type recorderKey struct{}
 
 const (
 	readIndex = iota
@@ -27,61 +33,111 @@ const (
 	writeIndex
 )
 
+// For returns the recorder of the site's build, nil if the user
+// doesn't want a build report.
+func For(conf *alpha.DarknessConfig) *Recorder {
+	if !conf.Runtime.BuildReport {
+		return nil
+	}
+	return conf.Runtime.State.Load(recorderKey{}, func() any { return &Recorder{} }).(*Recorder)
+}
+
 // RecordReadTime records the time it took to read a file.
 //
 //go:inline
-func RecordReadTime(inputFile yunyun.FullPathFile, duration time.Duration) {
-	recordTime(inputFile, duration, &readTimes)
+func (r *Recorder) RecordReadTime(inputFile yunyun.FullPathFile, duration time.Duration) {
+	r.recordTime(inputFile, duration, r.times(readIndex))
 }
 
 // RecordParseTime records the time it took to parse a file.
 //
 //go:inline
-func RecordParseTime(inputFile yunyun.FullPathFile, duration time.Duration) {
-	recordTime(inputFile, duration, &parseTimes)
+func (r *Recorder) RecordParseTime(inputFile yunyun.FullPathFile, duration time.Duration) {
+	r.recordTime(inputFile, duration, r.times(parseIndex))
 }
 
 // RecordExportTime records the time it took to export a file.
 //
 //go:inline
-func RecordExportTime(inputFile yunyun.FullPathFile, duration time.Duration) {
-	recordTime(inputFile, duration, &exportTimes)
+func (r *Recorder) RecordExportTime(inputFile yunyun.FullPathFile, duration time.Duration) {
+	r.recordTime(inputFile, duration, r.times(exportIndex))
 }
 
 // RecordWriteTime records the time it took to write a file.
 //
 //go:inline
-func RecordWriteTime(inputFile yunyun.FullPathFile, duration time.Duration) {
-	recordTime(inputFile, duration, &writeTimes)
+func (r *Recorder) RecordWriteTime(inputFile yunyun.FullPathFile, duration time.Duration) {
+	r.recordTime(inputFile, duration, r.times(writeIndex))
+}
+
+// RecordStats records the word count and alike of a file.
+func (r *Recorder) RecordStats(inputFile yunyun.FullPathFile, stats *yunyun.PageStats) {
+	if r != nil && stats != nil {
+		r.pageStats.Store(inputFile, *stats)
+	}
+}
+
+// GetStats returns the recorded stats of a file, zeroes if not recorded.
+func (r *Recorder) GetStats(inputFile yunyun.FullPathFile) yunyun.PageStats {
+	if r == nil {
+		return yunyun.PageStats{}
+	}
+	stats, ok := r.pageStats.Load(inputFile)
+	if !ok {
+		return yunyun.PageStats{}
+	}
+	return stats.(yunyun.PageStats)
+}
+
+// times returns the sync.Map with the times of the given index, nil for the nil recorder.
+func (r *Recorder) times(index int) *sync.Map {
+	if r == nil {
+		return nil
+	}
+	switch index {
+	case readIndex:
+		return &r.readTimes
+	case parseIndex:
+		return &r.parseTimes
+	case exportIndex:
+		return &r.exportTimes
+	default:
+		return &r.writeTimes
+	}
 }
 
 // recordTime records the time it took to do something in its respective sync.Map.
 //
 //go:inline
-func recordTime(inputFile yunyun.FullPathFile, duration time.Duration, times *sync.Map) {
+func (r *Recorder) recordTime(inputFile yunyun.FullPathFile, duration time.Duration, times *sync.Map) {
 	// Only record the file if we are recording build reports.
-	if kuroko.BuildReport {
+	if r != nil {
 		times.Store(inputFile, duration.Microseconds())
-		recordedFiles.Store(inputFile, true)
-		recordedFilesCounter.Add(1)
+		r.recordedFiles.Store(inputFile, true)
+		r.recordedFilesCounter.Add(1)
 	}
 }
 
 // GetNumberReports returns the number of files that have been recorded.
-func GetNumberReports() int {
-	return int(recordedFilesCounter.Load())
+func (r *Recorder) GetNumberReports() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.recordedFilesCounter.Load())
 }
 
 // GetFullReport returns a map of all the files and their times.
-func GetFullReport() map[yunyun.FullPathFile][]int64 {
+func (r *Recorder) GetFullReport() map[yunyun.FullPathFile][]int64 {
 	fullReport := make(map[yunyun.FullPathFile][]int64)
-	recordedFiles.Range(func(key, value any) bool {
+	if r == nil {
+		return fullReport
+	}
+	r.recordedFiles.Range(func(key, value any) bool {
 		inputFile := key.(yunyun.FullPathFile)
 		fullReport[inputFile] = make([]int64, 4)
-		loadIntoFullReport(inputFile, fullReport, &readTimes, readIndex)
-		loadIntoFullReport(inputFile, fullReport, &parseTimes, parseIndex)
-		loadIntoFullReport(inputFile, fullReport, &exportTimes, exportIndex)
-		loadIntoFullReport(inputFile, fullReport, &writeTimes, writeIndex)
+		for index := readIndex; index <= writeIndex; index++ {
+			loadIntoFullReport(inputFile, fullReport, r.times(index), index)
+		}
 		// Signal to continue.
 		return true
 	})
@@ -1,6 +0,0 @@
-package misaka
-
-import "github.com/thecsw/darkness/v3/emilia/puck"
-
-// Logger is the logger for Akane.
-var logger = puck.NewLogger("Misaka 🪙", puck.InfoLevel)
@@ -30,7 +30,7 @@ func WriteReport(conf *alpha.DarknessConfig) {
 	if _, err := os.Stat(string(reportDir)); os.IsNotExist(err) {
 		// Create the directory.
 		if err := rei.Mkdir(string(reportDir)); err != nil {
-			logger.Error("failed to create report directory", "err", err)
+			conf.Runtime.Logger.Error("failed to create report directory", "err", err)
 			return
 		}
 	}
@@ -40,27 +40,27 @@ func WriteReport(conf *alpha.DarknessConfig) {
 
 	reportOutputHandler, err := os.Create(filepath.Clean(string(reportOutputFilename)))
 	if err != nil {
-		logger.Error("failed to create report file", "err", err)
+		conf.Runtime.Logger.Error("failed to create report file", "err", err)
 		return
 	}
 	defer func(reportOutputHandler *os.File) {
 		err := reportOutputHandler.Close()
 		if err != nil {
-			logger.Error("failed to close report file", "err", err)
+			conf.Runtime.Logger.Error("failed to close report file", "err", err)
 		}
 	}(reportOutputHandler)
 
 	written, err := io.Copy(reportOutputHandler, buildCSVReport(conf))
 	if err != nil {
-		logger.Error("failed to write report file", "err", err)
+		conf.Runtime.Logger.Error("failed to write report file", "err", err)
 		return
 	}
 	if written == 0 {
-		logger.Warn("no report written")
+		conf.Runtime.Logger.Warn("no report written")
 		return
 	}
 
-	logger.Warn(
+	conf.Runtime.Logger.Warn(
 		"Build report produced",
 		"loc", conf.Runtime.WorkDir.Rel(reportOutputFilename),
 		"elapsed", time.Since(start),
@@ -69,7 +69,8 @@ func WriteReport(conf *alpha.DarknessConfig) {
 
 // buildCSVReport builds a CSV report of the current run.
 func buildCSVReport(conf *alpha.DarknessConfig) *bytes.Buffer {
-	fullReport := GetFullReport()
+	recorder := For(conf)
+	fullReport := recorder.GetFullReport()
 	buf := &bytes.Buffer{}
 	unit := ", μs"
 	writer := csv.NewWriter(buf)
@@ -82,6 +83,10 @@ func buildCSVReport(conf *alpha.DarknessConfig) *bytes.Buffer {
 		"Export Time" + unit,
 		"Write Time" + unit,
 		"Total Time" + unit,
+		"Words",
+		"Reading Time, min",
+		"Code Blocks",
+		"Images",
 	}))
 	num := 1
 	for inputFile, report := range fullReport {
@@ -91,6 +96,7 @@ func buildCSVReport(conf *alpha.DarknessConfig) *bytes.Buffer {
 		writeTime := int64(report[writeIndex])
 		totalTime := readTime + parseTime + exportTime + writeTime
 		fullpath := yunyun.FullPathFile(conf.Project.InputFilenameToOutput(inputFile))
+		stats := recorder.GetStats(inputFile)
 		rei.Try(writer.Write([]string{
 			strconv.Itoa(num),
 			string(conf.Runtime.WorkDir.Rel(inputFile)),
@@ -100,6 +106,10 @@ func buildCSVReport(conf *alpha.DarknessConfig) *bytes.Buffer {
 			humanize.Comma(exportTime),
 			humanize.Comma(writeTime),
 			humanize.Comma(totalTime),
+			humanize.Comma(int64(stats.Words)),
+			strconv.Itoa(stats.ReadingMinutes()),
+			strconv.Itoa(stats.CodeBlocks),
+			strconv.Itoa(stats.Images),
 		}))
 		num++
 	}
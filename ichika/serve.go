@@ -1,12 +1,15 @@
 package ichika
 
 import (
+	"context"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,8 +20,8 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/emilia/puck"
+	"github.com/thecsw/darkness/v3/ichika/himeno"
 	"github.com/thecsw/darkness/v3/ichika/hizuru"
-	"github.com/thecsw/darkness/v3/ichika/kuroko"
 	"github.com/thecsw/darkness/v3/yunyun"
 )
 
@@ -26,6 +29,14 @@ const (
 	// defaultServePort is the default port used when serving
 	// local files.
 	defaultServePort = 8080
+
+	// settleDuration is how long the file changes have to settle
+	// before the site is rebuilt.
+	settleDuration = 20 * time.Millisecond
+
+	// configFile stands in for the config file among the changed files,
+	// as it may live outside of the working directory.
+	configFile = yunyun.RelativePathFile("darkness.toml")
 )
 
 // ServeCommandFunc builds the website, local serves it on 8080 and then
@@ -43,9 +54,26 @@ func ServeCommandFunc() {
 
 	puck.Logger.SetPrefix("Server 🍩 ")
 
-	build(conf)
-	// disable akane after the first build
-	kuroko.Akaneless = true
+	// Stop serving on Ctrl-C, which also stops the build that may be running,
+	// and the next Ctrl-C kills the server right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	context.AfterFunc(ctx, stop)
+	defer stop()
+
+	b, err := build(ctx, conf)
+	if ctx.Err() != nil {
+		puck.Logger.Print("Stopped before serving, cleaning up")
+		isQuietMegumin = true
+		removeOutputFiles(conf)
+		return
+	}
+	if err != nil {
+		puck.Logger.Fatal("Building the site", "err", err)
+	}
+	conf.Runtime.Problems.Write(os.Stdout)
+	// disable akane after the first build, also for the config read again
+	conf.Runtime.Akaneless = true
+	options.Akaneless = true
 	puck.Logger.Print("Serving the files", "url", options.Url)
 
 	r := chi.NewRouter()
@@ -63,7 +91,7 @@ func ServeCommandFunc() {
 	}
 
 	// Tune it to serve local files.
-	fileServer(r, "/", http.Dir(string(conf.Runtime.WorkDir)))
+	fileServer(r, "/", http.Dir(string(conf.Runtime.OutputDir)))
 
 	// Spin the local server up.
 	go func() {
@@ -71,7 +99,11 @@ func ServeCommandFunc() {
 	}()
 
 	// File watcher will rebuild dir if any files change.
-	go launchWatcher(conf)
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		launchWatcher(ctx, conf, options, b)
+	}()
 	puck.Logger.Print("Launched file watcher")
 
 	// Try to open the local server with `open` command.
@@ -88,18 +120,20 @@ func ServeCommandFunc() {
 		}
 	}
 
-	sigint := make(chan os.Signal, 1)
-	signal.Notify(sigint, os.Interrupt)
-	<-sigint
+	<-ctx.Done()
 	puck.Logger.Print("Shutting down the server + cleaning up")
+	// Let the rebuild that may be running stop before cleaning up after it.
+	<-watched
 	isQuietMegumin = true
 	removeOutputFiles(conf)
 	puck.Logger.Print("farewell")
 }
 
 // launchWatcher watches for any file creations, changes, modifications, deletions
-// and rebuilds the directory as that happens.
-func launchWatcher(conf *alpha.DarknessConfig) {
+// and rebuilds the pages affected by them as that happens. A rebuild still running
+// when newer changes settle is stopped for them. It returns once the context is
+// done and the last rebuild stopped.
+func launchWatcher(ctx context.Context, conf *alpha.DarknessConfig, options alpha.Options, b *builder) {
 	// Create new watcher.
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
@@ -107,51 +141,183 @@ func launchWatcher(conf *alpha.DarknessConfig) {
 	}
 	defer watcher.Close()
 
-	// Start listening for events.
-	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					puck.Logger.Warn("stopped watching")
-					return
-				}
-				filename := string(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name)))
-				if strings.HasSuffix(filename, conf.Project.Output) ||
-					strings.HasPrefix(filepath.Base(filename), `.`) {
-					continue
-				}
-				// Skip CHMOD events that IDE and editors do by default
-				if event.Has(fsnotify.Chmod) {
+	// Watch the directories instead of the files, so that the new files, gallery
+	// images, and files replaced by editors on save are all seen. Inputs in hidden
+	// directories are found too, so watch their directories as well.
+	dirs := hizuru.FindDirs(conf)
+	for _, inputFilename := range hizuru.FindFilesByExtSimple(conf) {
+		dirs = append(dirs, yunyun.FullPathDir(filepath.Dir(string(inputFilename))))
+	}
+	configFilename, err := filepath.Abs(options.DarknessConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dirs = append(dirs, yunyun.FullPathDir(filepath.Dir(configFilename)))
+	slices.Sort(dirs)
+	for _, dir := range slices.Compact(dirs) {
+		if err := watcher.Add(string(dir)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	puck.Logger.Print("Listening to file changes", "num", len(watcher.WatchList()), "dir", conf.Runtime.WorkDir)
+	puck.Logger.Print("Press Ctrl-C to stop the server")
+
+	// Editors usually touch a file a few times on save, so wait for the
+	// events to settle and rebuild once for all of them.
+	changed := make(map[yunyun.RelativePathFile]struct{})
+	settled := time.NewTimer(time.Hour)
+	settled.Stop()
+	var current *rebuilding
+	defer func() {
+		if current != nil {
+			current.stop()
+		}
+	}()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				puck.Logger.Warn("stopped watching")
+				return
+			}
+			// Skip CHMOD events that IDE and editors do by default
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			filename := conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(event.Name))
+			if strings.HasSuffix(string(filename), conf.Project.Output) ||
+				strings.HasPrefix(filepath.Base(string(filename)), `.`) {
+				continue
+			}
+			// Start watching the new directories, their files may be needed soon,
+			// unless they're excluded, like the output directory we write into.
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() && event.Has(fsnotify.Create) {
+				if hizuru.IsSkippedDir(conf, yunyun.FullPathDir(event.Name)) {
 					continue
 				}
-				if event.Has(fsnotify.Write) || event.Has(fsnotify.Rename) ||
-					event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) {
-					puck.Logger.Warn("A file was modified", "path", filename)
+				if err := watcher.Add(event.Name); err != nil {
+					puck.Logger.Error("Watching new directory", "dir", filename, "err", err)
 				}
-				build(conf)
-			case err, ok := <-watcher.Errors:
-				if !ok {
-					puck.Logger.Warn("Watcher is leaving")
-					return
+				continue
+			}
+			if absolute, _ := filepath.Abs(event.Name); absolute == configFilename {
+				filename = configFile
+			}
+			changed[filename] = struct{}{}
+			settled.Reset(settleDuration)
+		case <-settled.C:
+			if current != nil {
+				var unfinished []yunyun.RelativePathFile
+				conf, b, unfinished = current.stop()
+				for _, file := range unfinished {
+					changed[file] = struct{}{}
 				}
-				puck.Logger.Error("Watcher", "err", err)
 			}
+			current = startRebuild(ctx, conf, options, b, slices.Sorted(maps.Keys(changed)))
+			clear(changed)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				puck.Logger.Warn("Watcher is leaving")
+				return
+			}
+			puck.Logger.Error("Watcher", "err", err)
 		}
+	}
+}
+
+// rebuilding is a rebuild running in the background.
+type rebuilding struct {
+	// cancel stops the rebuild.
+	cancel context.CancelFunc
+	// done is closed once the rebuild returns.
+	done chan struct{}
+	// conf is the config the rebuild left, set before done is closed.
+	conf *alpha.DarknessConfig
+	// b is the builder the rebuild left, set before done is closed.
+	b *builder
+	// unfinished are the changed files of a stopped rebuild, which the
+	// next one has to rebuild, set before done is closed.
+	unfinished []yunyun.RelativePathFile
+}
+
+// startRebuild starts rebuilding the changed files in the background.
+func startRebuild(
+	ctx context.Context,
+	conf *alpha.DarknessConfig,
+	options alpha.Options,
+	b *builder,
+	changed []yunyun.RelativePathFile,
+) *rebuilding {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &rebuilding{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(r.done)
+		r.conf, r.b, r.unfinished = rebuildChanged(ctx, conf, options, b, changed)
 	}()
+	return r
+}
 
-	// start adding all the source files
-	for _, inputFilenameToWatch := range hizuru.FindFilesByExtSimple(conf) {
-		err = watcher.Add(string(inputFilenameToWatch))
+// stop stops the rebuild if it's still running, waits for it to return,
+// and returns the config, the builder, and the unfinished files it left.
+func (r *rebuilding) stop() (*alpha.DarknessConfig, *builder, []yunyun.RelativePathFile) {
+	r.cancel()
+	<-r.done
+	return r.conf, r.b, r.unfinished
+}
+
+// rebuildChanged rebuilds the site after the files changed, the config or the global
+// macros change every page, so they get a full build with the config read again. A
+// stopped rebuild returns its changed files, so the next one rebuilds them too, and
+// a stopped full build leaves no builder behind, so the next one builds everything.
+func rebuildChanged(
+	ctx context.Context,
+	conf *alpha.DarknessConfig,
+	options alpha.Options,
+	b *builder,
+	changed []yunyun.RelativePathFile,
+) (*alpha.DarknessConfig, *builder, []yunyun.RelativePathFile) {
+	full := b == nil
+	if full {
+		puck.Logger.Warn("Rebuilding everything after the stopped rebuild")
+	}
+	previous := conf
+	for _, filename := range changed {
+		if filename != configFile && filename != himeno.GlobalMacrosFile(previous) {
+			continue
+		}
+		puck.Logger.Warn("Rebuilding everything", "path", filename)
+		full = true
+		if filename != configFile {
+			continue
+		}
+		rebuilt, err := alpha.NewConfig(options)
 		if err != nil {
-			log.Fatal(err)
+			puck.Logger.Error("Keeping the old config", "err", err)
+			return previous, b, nil
 		}
+		conf = rebuilt
 	}
-	puck.Logger.Print("Listening to file changes", "num", len(watcher.WatchList()), "dir", conf.Runtime.WorkDir)
 
-	puck.Logger.Print("Press Ctrl-C to stop the server")
-	// Block main goroutine forever.
-	<-make(chan struct{})
+	conf.Runtime.Problems = conf.Strict.NewReport()
+	if full {
+		rebuilt, err := build(ctx, conf)
+		if ctx.Err() != nil {
+			puck.Logger.Warn("Stopped the rebuild")
+			return conf, nil, nil
+		}
+		if err != nil {
+			puck.Logger.Error("Keeping the old build", "err", err)
+			return previous, b, nil
+		}
+		b = rebuilt
+	} else if err := b.rebuild(ctx, changed); err != nil {
+		puck.Logger.Warn("Stopped the rebuild, the next one picks it up")
+		return conf, b, changed
+	}
+	conf.Runtime.Problems.Write(conf.Runtime.Stdout)
+	return conf, b, nil
 }
 
 // isURLSafe checks if a URL is safe to pass to exec.Command
@@ -0,0 +1,171 @@
+package subaru
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/emilia/rem"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// Graph is the dependency graph of the pages, by their source files. It is
+// not safe for concurrent use, the builds must record into it one at a time.
+type Graph struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// nodes are the dependencies of the pages by their source files.
+	nodes map[yunyun.RelativePathFile]*node
+}
+
+// node is what a single page depends on.
+type node struct {
+	// location is the location of the page.
+	location yunyun.RelativePathDir
+	// setupFiles are the files pulled in with `#+setupfile:`.
+	setupFiles []yunyun.RelativePathFile
+	// images are the local images of the page's galleries.
+	images []yunyun.RelativePathFile
+	// shown are the locations of the other pages the page shows.
+	shown []yunyun.RelativePathDir
+	// listed are the directories whose pages the page lists.
+	listed []yunyun.RelativePathDir
+	// related are the locations of the page's related pages, nil if
+	// the page doesn't show related pages at all.
+	related []yunyun.RelativePathDir
+}
+
+// NewGraph returns an empty dependency graph.
+func NewGraph() *Graph {
+	return &Graph{nodes: make(map[yunyun.RelativePathFile]*node)}
+}
+
+// Depend records the files the parsed page pulled in, replacing whatever
+// was recorded for the page before.
+func (g *Graph) Depend(conf *alpha.DarknessConfig, page *yunyun.Page) {
+	n := g.node(page)
+	n.setupFiles = slices.Clone(page.SetupFiles)
+	n.images = n.images[:0]
+	for _, content := range page.Contents {
+		if !content.IsGallery() {
+			continue
+		}
+		for _, line := range content.List {
+			item := rem.NewGalleryItem(conf, page, content, line.Text)
+			if !item.IsExternal {
+				n.images = append(n.images, galleryImage(item))
+			}
+		}
+	}
+}
+
+// Show records the other pages the enriched page shows, replacing whatever
+// was recorded for the page before.
+func (g *Graph) Show(page *yunyun.Page) {
+	n := g.node(page)
+	n.shown, n.listed, n.related = n.shown[:0], n.listed[:0], nil
+	show := func(pages ...*yunyun.SitePage) {
+		for _, shown := range pages {
+			n.shown = append(n.shown, shown.Location)
+		}
+	}
+	show(page.Related...)
+	if page.Related != nil {
+		n.related = make([]yunyun.RelativePathDir, len(page.Related))
+		for i, related := range page.Related {
+			n.related[i] = related.Location
+		}
+	}
+	show(page.Translations...)
+	show(page.Breadcrumbs...)
+	for _, backlink := range page.Backlinks {
+		show(backlink.Page)
+	}
+	if page.Series != nil {
+		show(page.Series.Parts...)
+	}
+	for _, content := range page.Contents {
+		if content.IsListing() && content.Listing != nil {
+			n.listed = append(n.listed, content.Listing.Dir)
+		}
+	}
+}
+
+// Forget drops the page from the graph, like when its source is removed.
+func (g *Graph) Forget(file yunyun.RelativePathFile) {
+	delete(g.nodes, file)
+}
+
+// Dependents returns the sorted source files of the pages that pulled in the
+// changed file, either as a setupfile or as an image in one of their galleries.
+func (g *Graph) Dependents(changed yunyun.RelativePathFile) []yunyun.RelativePathFile {
+	return g.filter(func(n *node) bool {
+		return slices.Contains(n.setupFiles, changed) || slices.Contains(n.images, changed)
+	})
+}
+
+// Showing returns the sorted source files of the pages that show the page at
+// the location, either directly or by listing its directory.
+func (g *Graph) Showing(location yunyun.RelativePathDir) []yunyun.RelativePathFile {
+	return g.filter(func(n *node) bool {
+		return slices.Contains(n.shown, location) || slices.ContainsFunc(n.listed,
+			func(dir yunyun.RelativePathDir) bool { return dir != location && isInside(dir, location) })
+	})
+}
+
+// Relating returns the sorted source files of the pages whose related pages are
+// not the ones they would find in the site now, like when a changed page became
+// similar enough to show up. The count tells how many related pages the page at
+// the location finds, or false if we can't tell, then the page is left alone.
+func (g *Graph) Relating(site *yunyun.Site, count func(yunyun.RelativePathDir) (int, bool)) []yunyun.RelativePathFile {
+	return g.filter(func(n *node) bool {
+		if n.related == nil {
+			return false
+		}
+		wanted, known := count(n.location)
+		if !known {
+			return false
+		}
+		related := narumi.RelatedPages(site, n.location, wanted)
+		return !slices.EqualFunc(n.related, related, func(location yunyun.RelativePathDir, page *yunyun.SitePage) bool {
+			return location == page.Location
+		})
+	})
+}
+
+// node returns the node of the page, adding it if it's new.
+func (g *Graph) node(page *yunyun.Page) *node {
+	n, found := g.nodes[page.File]
+	if !found {
+		n = &node{}
+		g.nodes[page.File] = n
+	}
+	n.location = page.Location
+	return n
+}
+
+// filter returns the sorted source files of the pages satisfying the predicate.
+func (g *Graph) filter(predicate func(*node) bool) []yunyun.RelativePathFile {
+	files := make([]yunyun.RelativePathFile, 0, 4)
+	for file, n := range g.nodes {
+		if predicate(n) {
+			files = append(files, file)
+		}
+	}
+	slices.Sort(files)
+	return files
+}
+
+// galleryImage returns the gallery's image relative to the working directory,
+// the galleries with absolute paths start from the working directory too.
+func galleryImage(item rem.GalleryItem) yunyun.RelativePathFile {
+	image := yunyun.JoinRelativePaths(item.Path, item.Item)
+	return yunyun.RelativePathFile(strings.TrimPrefix(filepath.Clean(string(image)), "/"))
+}
+
+// isInside tells us if the location is inside of the directory.
+func isInside(dir, location yunyun.RelativePathDir) bool {
+	return dir == "." || strings.HasPrefix(string(location), string(dir)+"/")
+}
@@ -0,0 +1,99 @@
+package subaru
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestGraph tests that the pages are found by the files they pulled in and the pages they show
+func TestGraph(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	graph, conf := NewGraph(), &alpha.DarknessConfig{}
+	gallery := &yunyun.Content{Type: yunyun.TypeList, GalleryPath: "photos",
+		List: []yunyun.ListItem{{Text: "sea.jpg"}, {Text: "https://example.com/sky.jpg"}}}
+	yunyun.AddFlag(&gallery.Options, yunyun.InGalleryFlag)
+	listing := &yunyun.Content{Type: yunyun.TypeListing, Listing: &yunyun.Listing{Dir: "blog"}}
+
+	trip := yunyun.NewPage(yunyun.WithFilename("trip/index.org"), yunyun.WithLocation("trip"),
+		yunyun.WithContents([]*yunyun.Content{gallery}))
+	trip.SetupFiles = []yunyun.RelativePathFile{"_setup.org"}
+	trip.Breadcrumbs = []*yunyun.SitePage{{Location: "."}}
+	graph.Depend(conf, trip)
+	graph.Show(trip)
+
+	blog := yunyun.NewPage(yunyun.WithFilename("blog/index.org"), yunyun.WithLocation("blog"),
+		yunyun.WithContents([]*yunyun.Content{listing}))
+	blog.SetupFiles = []yunyun.RelativePathFile{"_setup.org", "blog/_header.org"}
+	graph.Depend(conf, blog)
+	graph.Show(blog)
+
+	tests := []struct {
+		name     string
+		got      []yunyun.RelativePathFile
+		expected []yunyun.RelativePathFile
+	}{
+		{"shared setupfile", graph.Dependents("_setup.org"), []yunyun.RelativePathFile{"blog/index.org", "trip/index.org"}},
+		{"own setupfile", graph.Dependents("blog/_header.org"), []yunyun.RelativePathFile{"blog/index.org"}},
+		{"gallery image", graph.Dependents("trip/photos/sea.jpg"), []yunyun.RelativePathFile{"trip/index.org"}},
+		{"unlisted image", graph.Dependents("trip/photos/sky.jpg"), []yunyun.RelativePathFile{}},
+		{"breadcrumb", graph.Showing("."), []yunyun.RelativePathFile{"trip/index.org"}},
+		{"listed page", graph.Showing("blog/first"), []yunyun.RelativePathFile{"blog/index.org"}},
+		{"listing itself", graph.Showing("blog"), []yunyun.RelativePathFile{}},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.expected) {
+			t.Errorf("%s: got %v, expected %v", tt.name, tt.got, tt.expected)
+		}
+	}
+
+	graph.Forget("trip/index.org")
+	if got := graph.Dependents("_setup.org"); !slices.Equal(got, []yunyun.RelativePathFile{"blog/index.org"}) {
+		t.Errorf("forgotten page is still a dependent: %v", got)
+	}
+}
+
+// TestGraphRelating tests that only the pages whose related pages changed are relating
+func TestGraphRelating(t *testing.T) {
+	yunyun.ActiveMarkings.BuildRegex()
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	summary := func(location yunyun.RelativePathDir, text string) *yunyun.SitePage {
+		page := yunyun.NewPage(yunyun.WithLocation(location),
+			yunyun.WithContents(yunyun.Contents{{Type: yunyun.TypeParagraph, Paragraph: text}}))
+		return &yunyun.SitePage{Location: location, Published: date, Terms: narumi.TermFrequencies(page)}
+	}
+	site := func(summaries ...*yunyun.SitePage) *yunyun.Site {
+		site := yunyun.NewSite(summaries)
+		narumi.WeighTerms(site)
+		return site
+	}
+	count := func(yunyun.RelativePathDir) (int, bool) { return 1, true }
+
+	graph := NewGraph()
+	before := site(summary("go", "goroutines channels"), summary("rust", "ownership borrowing"),
+		summary("cooking", "pasta tomatoes"))
+	for _, location := range []yunyun.RelativePathDir{"go", "cooking"} {
+		page := yunyun.NewPage(yunyun.WithFilename(yunyun.RelativePathFile(location)+"/index.org"),
+			yunyun.WithLocation(location))
+		page.Related = narumi.RelatedPages(before, location, 1)
+		graph.Show(page)
+	}
+	if got := graph.Relating(before, count); len(got) > 0 {
+		t.Errorf("nothing changed, but got relating %v", got)
+	}
+
+	// Rust now talks about goroutines, so the go page finds it.
+	after := site(summary("go", "goroutines channels"), summary("rust", "goroutines ownership"),
+		summary("cooking", "pasta tomatoes"))
+	if got := graph.Relating(after, count); !slices.Equal(got, []yunyun.RelativePathFile{"go/index.org"}) {
+		t.Errorf("got relating %v, expected the go page", got)
+	}
+	unknown := func(yunyun.RelativePathDir) (int, bool) { return 0, false }
+	if got := graph.Relating(after, unknown); len(got) > 0 {
+		t.Errorf("the counts are unknown, but got relating %v", got)
+	}
+}
@@ -0,0 +1,197 @@
+package tohru
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/narumi"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
+	"github.com/thecsw/darkness/v3/ichika/hizuru"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+var (
+	// referenceRegexp finds the urls in the attributes of the exported pages.
+	referenceRegexp = regexp.MustCompile(`\b(?:src|href|content|poster)="([^"]+)"`)
+
+	// styleUrlRegexp finds the urls in the stylesheets, like fonts and backgrounds.
+	styleUrlRegexp = regexp.MustCompile(`url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+)
+
+// Assets are the static files in the working directory that the pages reference,
+// which get copied into the output directory. A nil Assets finds nothing.
+type Assets struct {
+	// mutex guards the files.
+	mutex sync.Mutex
+	// files are the referenced files relative to the working directory.
+	files map[yunyun.RelativePathFile]struct{}
+}
+
+// NewAssets returns the assets if the site is built into the output directory,
+// otherwise the files are already where they should be, so it returns nil.
+func NewAssets(conf *alpha.DarknessConfig) *Assets {
+	if len(conf.Project.OutputDirectory) < 1 {
+		return nil
+	}
+	return &Assets{files: make(map[yunyun.RelativePathFile]struct{})}
+}
+
+// Find adds the files referenced by the exported page at the location.
+func (a *Assets) Find(conf *alpha.DarknessConfig, location yunyun.RelativePathDir, data []byte) {
+	if a == nil {
+		return
+	}
+	for _, match := range referenceRegexp.FindAllSubmatch(data, -1) {
+		a.add(conf, location, html.UnescapeString(string(match[1])))
+	}
+}
+
+// Has tells us if the file relative to the working directory is an asset.
+func (a *Assets) Has(file yunyun.RelativePathFile) bool {
+	if a == nil {
+		return false
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	_, found := a.files[file]
+	return found
+}
+
+// add adds the file that the reference on the page at the location points to, if
+// it's a local file that is not one of the inputs or outputs. It returns the file
+// and true if the file is a new asset.
+func (a *Assets) add(
+	conf *alpha.DarknessConfig,
+	location yunyun.RelativePathDir,
+	reference string,
+) (yunyun.RelativePathFile, bool) {
+	internal, ok := narumi.InternalLink(conf, strings.TrimSpace(reference))
+	if !ok {
+		return "", false
+	}
+	resolved, _, ok := narumi.ResolveLink(location, internal)
+	if !ok || resolved == "." {
+		return "", false
+	}
+	if yunyun.RelativePathDir(resolved) == conf.Project.OutputDirectory ||
+		strings.HasPrefix(resolved, string(conf.Project.OutputDirectory)+"/") {
+		return "", false
+	}
+	file := yunyun.RelativePathFile(resolved)
+	filename := conf.Runtime.WorkDir.Join(file)
+	if info, err := os.Stat(string(filename)); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	if hizuru.IsInputFile(conf, filename) {
+		return "", false
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if _, found := a.files[file]; found {
+		return "", false
+	}
+	a.files[file] = struct{}{}
+	return file, true
+}
+
+// Copy copies the assets into the output directory and records them in the build
+// manifest, the copies that are already up to date are left alone. Stylesheets are
+// looked through for the files they use as well. It returns the number of copies.
+func (a *Assets) Copy(conf *alpha.DarknessConfig) (int, error) {
+	if a == nil {
+		return 0, nil
+	}
+	a.mutex.Lock()
+	queue := slices.Sorted(maps.Keys(a.files))
+	a.mutex.Unlock()
+
+	copied := 0
+	errs := make([]error, 0, 2)
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		wrote, err := copyAsset(conf, file)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if wrote {
+			copied++
+		}
+		if path.Ext(string(file)) != ".css" {
+			continue
+		}
+		data, err := os.ReadFile(string(conf.Runtime.WorkDir.Join(file)))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading %s: %v", file, err))
+			continue
+		}
+		location := yunyun.RelativePathDir(path.Dir(string(file)))
+		for _, match := range styleUrlRegexp.FindAllSubmatch(data, -1) {
+			if found, isNew := a.add(conf, location, string(match[1])); isNew {
+				queue = append(queue, found)
+			}
+		}
+	}
+	return copied, errors.Join(errs...)
+}
+
+// copyAsset copies the asset into the output directory unless the copy there
+// is up to date, and records it in the build manifest. It returns true if copied.
+func copyAsset(conf *alpha.DarknessConfig, file yunyun.RelativePathFile) (bool, error) {
+	source := conf.Runtime.WorkDir.Join(file)
+	target := conf.Runtime.OutputDir.Join(file)
+	record := func(hash string, written bool) {
+		conf.Runtime.Manifest.Record(uiharu.SiteInput, conf.Runtime.WorkDir.Rel(target), hash, written)
+	}
+	sourceInfo, err := os.Stat(string(source))
+	if errors.Is(err, os.ErrNotExist) {
+		// The file is gone since the pages referenced it, so is its copy.
+		err := os.Remove(string(target))
+		if err == nil {
+			conf.Runtime.Manifest.Removed()
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("removing %s: %v", target, err)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %v", file, err)
+	}
+	if targetInfo, err := os.Stat(string(target)); err == nil && targetInfo.Size() == sourceInfo.Size() &&
+		!targetInfo.ModTime().Before(sourceInfo.ModTime()) {
+		hash, err := uiharu.HashFile(target)
+		if err != nil {
+			return false, err
+		}
+		record(hash, false)
+		return false, nil
+	}
+
+	from, err := os.Open(filepath.Clean(string(source)))
+	if err != nil {
+		return false, fmt.Errorf("opening %s: %v", file, err)
+	}
+	defer from.Close()
+	hash, written, err := uiharu.WriteFile(string(target), func(w io.Writer) error {
+		_, err := io.Copy(w, from)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("copying %s: %v", file, err)
+	}
+	record(hash, written)
+	return written, nil
+}
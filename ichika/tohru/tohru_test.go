@@ -0,0 +1,66 @@
+package tohru
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/emilia/uiharu"
+)
+
+// TestCopy tests that the referenced files and the files that the
+// stylesheets use get copied into the output directory
+func TestCopy(t *testing.T) {
+	workDir := t.TempDir()
+	write := func(file, data string) {
+		filename := filepath.Join(workDir, file)
+		if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("darkness.toml", "url = \"https://example.com\"\n[project]\noutput_directory = \"public\"\n")
+	write("css/style.css", `@font-face { src: url("fonts/mono.woff2"); } body { background: url(data:image/png;base64,AA==); }`)
+	write("css/fonts/mono.woff2", "font")
+	write("notes/cat.png", "meow")
+	write("notes/index.org", "* Notes")
+	write("public/index.html", "old")
+
+	conf := alpha.BuildConfig(alpha.Options{
+		DarknessConfig: filepath.Join(workDir, "darkness.toml"),
+		WorkDir:        workDir,
+	})
+	conf.Runtime.Manifest = uiharu.NewManifest()
+	assets := NewAssets(conf)
+	assets.Find(conf, "notes", []byte(`<link rel="stylesheet" href="https://example.com/css/style.css"/>
+<img src="cat.png"/> <a href="https://example.com/notes/index.org">source</a>
+<a href="https://example.com/public/index.html">output</a> <a href="https://example.org/cat.png">elsewhere</a>
+<a href="../../etc/passwd">above</a> <img src="missing.png"/>`))
+
+	copied, err := assets.Copy(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != 3 {
+		t.Errorf("copied %d files, expected 3", copied)
+	}
+	for _, file := range []string{"css/style.css", "css/fonts/mono.woff2", "notes/cat.png"} {
+		if _, err := os.Stat(filepath.Join(workDir, "public", file)); err != nil {
+			t.Errorf("expected %s to be copied: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(workDir, "public", "notes", "index.org")); err == nil {
+		t.Errorf("expected the input not to be copied")
+	}
+	if got := len(conf.Runtime.Manifest.Outputs()); got != 3 {
+		t.Errorf("recorded %d outputs, expected 3", got)
+	}
+
+	// The copies are up to date now, so nothing is copied again.
+	if copied, _ := assets.Copy(conf); copied != 0 {
+		t.Errorf("copied %d files again, expected none", copied)
+	}
+}
@@ -0,0 +1,109 @@
+package yor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+const (
+	// PreBuild is the stage of the commands before the build.
+	PreBuild = "pre_build"
+	// PostBuild is the stage of the commands after the build.
+	PostBuild = "post_build"
+	// filterStage is the stage of the filters.
+	filterStage = "filter"
+)
+
+// Run runs the commands of the build stage one by one in the working directory,
+// with both of their outputs going where the build progress goes, and stops at
+// the first one that fails. The command still running when the context is done
+// gets killed.
+func Run(ctx context.Context, conf *alpha.DarknessConfig, stage string, commands []string) error {
+	for _, command := range commands {
+		start := time.Now()
+		err := run(ctx, conf, stage, command, conf.Hooks.Timeout, func(cmd *exec.Cmd) {
+			cmd.Stdout, cmd.Stderr = conf.Runtime.Stdout, conf.Runtime.Stdout
+		})
+		if err != nil {
+			return fmt.Errorf("%s hook %q: %w", stage, command, err)
+		}
+		conf.Runtime.Logger.Info("Ran hook", "stage", stage, "command", command, "elapsed", time.Since(start))
+	}
+	return nil
+}
+
+// Filter pipes the exported page through the filters for its directory, one
+// after another, and returns what the last one wrote.
+func Filter(ctx context.Context, conf *alpha.DarknessConfig, page *yunyun.Page, output string, data []byte) ([]byte, error) {
+	for _, filter := range conf.Hooks.FiltersFor(page.Location) {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		err := run(ctx, conf, filterStage, filter.Command, filter.Timeout, func(cmd *exec.Cmd) {
+			cmd.Env = append(cmd.Env, pageEnv(conf, page, output)...)
+			cmd.Stdin = bytes.NewReader(data)
+			cmd.Stdout, cmd.Stderr = stdout, stderr
+		})
+		if err != nil {
+			if message := strings.TrimSpace(stderr.String()); len(message) > 0 {
+				err = fmt.Errorf("%w: %s", err, message)
+			}
+			return nil, fmt.Errorf("filter %q: %w", filter.Command, err)
+		}
+		data = stdout.Bytes()
+	}
+	return data, nil
+}
+
+// run runs the command with the shell in the working directory, with the variables
+// describing the site, and kills it after the timeout or once the context is done.
+// The setup fills in the rest.
+func run(
+	ctx context.Context,
+	conf *alpha.DarknessConfig,
+	stage, command string,
+	timeout time.Duration,
+	setup func(cmd *exec.Cmd),
+) error {
+	timed, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(timed, "sh", "-c", command) // #nosec G204 - the commands come from the user's own config
+	cmd.Dir = string(conf.Runtime.WorkDir)
+	cmd.Env = append(os.Environ(),
+		"DARKNESS_HOOK="+stage,
+		"DARKNESS_WORK_DIR="+string(conf.Runtime.WorkDir),
+		"DARKNESS_OUTPUT_DIR="+string(conf.Runtime.OutputDir),
+		"DARKNESS_URL="+conf.Url,
+	)
+	// The processes the command started may still hold the pipes after it's
+	// gone, so don't wait for them forever.
+	cmd.WaitDelay = time.Second
+	setup(cmd)
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(timed.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// pageEnv returns the variables describing the page to its filters.
+func pageEnv(conf *alpha.DarknessConfig, page *yunyun.Page, output string) []string {
+	return []string{
+		"DARKNESS_PAGE_FILE=" + string(page.File),
+		"DARKNESS_PAGE_LOCATION=" + string(page.Location),
+		"DARKNESS_PAGE_OUTPUT=" + string(conf.Runtime.WorkDir.Rel(yunyun.FullPathFile(output))),
+		"DARKNESS_PAGE_URL=" + string(conf.Runtime.JoinDir(page.Location)),
+		"DARKNESS_PAGE_TITLE=" + page.Title,
+		"DARKNESS_PAGE_LANGUAGE=" + page.Language,
+	}
+}
@@ -0,0 +1,74 @@
+package yor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
+)
+
+// TestFilter tests that the filters for the page's directory run in order
+// and see the page, and that a slow one is stopped
+func TestFilter(t *testing.T) {
+	conf := alpha.BuildConfig(alpha.Options{WorkDir: t.TempDir(), Test: true})
+	conf.Hooks.Filters = []alpha.FilterConfig{
+		{Command: `tr a-z A-Z`, Timeout: time.Minute},
+		{Command: `cat; printf " $DARKNESS_PAGE_FILE"`, Dir: "notes", Timeout: time.Minute},
+	}
+	page := &yunyun.Page{File: "notes/a/index.org", Location: "notes/a"}
+	output, err := Filter(context.Background(), conf, page, "", []byte("hello"))
+	if err != nil {
+		t.Fatalf("Filter() = %v", err)
+	}
+	if expected := "HELLO notes/a/index.org"; string(output) != expected {
+		t.Errorf("got %q, expected %q", output, expected)
+	}
+	output, _ = Filter(context.Background(), conf, &yunyun.Page{File: "index.org", Location: "."}, "", []byte("hello"))
+	if string(output) != "HELLO" {
+		t.Errorf("the notes filter shouldn't run on the root page, got %q", output)
+	}
+
+	conf.Hooks.Filters = []alpha.FilterConfig{{Command: `sleep 10`, Timeout: 100 * time.Millisecond}}
+	start := time.Now()
+	if _, err := Filter(context.Background(), conf, page, "", []byte("hello")); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected the filter to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the slow filter took %s to stop", elapsed)
+	}
+}
+
+// TestFilterCancelled tests that a filter is killed once the build stops
+func TestFilterCancelled(t *testing.T) {
+	conf := alpha.BuildConfig(alpha.Options{WorkDir: t.TempDir(), Test: true})
+	conf.Hooks.Filters = []alpha.FilterConfig{{Command: `sleep 10`, Timeout: time.Minute}}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	page := &yunyun.Page{File: "index.org", Location: "."}
+	if _, err := Filter(ctx, conf, page, "", []byte("hello")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the filter to stop with the build, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the cancelled filter took %s to stop", elapsed)
+	}
+}
+
+// TestRunOutput tests that both outputs of the hooks go where the build progress goes
+func TestRunOutput(t *testing.T) {
+	conf := alpha.BuildConfig(alpha.Options{WorkDir: t.TempDir(), Test: true})
+	conf.Hooks.Timeout = time.Minute
+	stdout := &bytes.Buffer{}
+	conf.Runtime.Stdout = stdout
+	if err := Run(context.Background(), conf, PreBuild, []string{`echo hello; echo oops >&2`}); err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+	if expected := "hello\noops\n"; stdout.String() != expected {
+		t.Errorf("got %q, expected %q", stdout.String(), expected)
+	}
+}
@@ -3,6 +3,7 @@ package orgmode
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -240,6 +241,95 @@ func extractAuthor(line string) string {
 	return extractOptionLabel(line, optionAuthor)
 }
 
+// extractSeries extracts series `NAME` from `#+series: NAME`.
+func extractSeries(line string) string {
+	return extractOptionLabel(line, optionSeries)
+}
+
+// extractSeriesOrder extracts the part `N` from `#+series_order: N`,
+// zero if it's not a positive number.
+func extractSeriesOrder(line string) int {
+	order, err := strconv.Atoi(extractOptionLabel(line, optionSeriesOrder))
+	if err != nil || order < 1 {
+		return 0
+	}
+	return order
+}
+
+// extractLanguage extracts language `TAG` from `#+language: TAG`, empty
+// if it doesn't look like a language tag.
+func extractLanguage(line string) string {
+	language := yunyun.NormalizeLanguage(extractOptionLabel(line, optionLanguage))
+	if !yunyun.IsLanguageTag(language) {
+		return ""
+	}
+	return language
+}
+
+// extractTranslationKey extracts key `KEY` from `#+translation_key: KEY`.
+func extractTranslationKey(line string) string {
+	return extractOptionLabel(line, optionTranslation)
+}
+
+// extractListing extracts the listing from `#+list_pages: DIR sort:date limit:20`,
+// where the directory is relative to the page, unless it starts with a slash.
+func extractListing(location yunyun.RelativePathDir, line string) *yunyun.Listing {
+	listing := &yunyun.Listing{Dir: location, Sort: yunyun.ListingSortDate}
+	for field := range strings.FieldsSeq(extractOptionLabel(line, optionListPages)) {
+		key, value, isOption := strings.Cut(field, ":")
+		if !isOption {
+			listing.Dir = resolveListingDir(location, field)
+			continue
+		}
+		switch key {
+		case "sort":
+			if yunyun.ListingSort(value) == yunyun.ListingSortTitle {
+				listing.Sort = yunyun.ListingSortTitle
+			}
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				logger.Warn("Bad listing limit", "limit", value)
+				continue
+			}
+			listing.Limit = limit
+		case "recursive":
+			listing.Recursive = value == "t"
+		case "tag":
+			listing.Tag = yunyun.TagSlug(value)
+		default:
+			logger.Warn("Unknown listing option", "option", key)
+		}
+	}
+	return listing
+}
+
+// resolveListingDir resolves the listing directory against the page's location.
+func resolveListingDir(location yunyun.RelativePathDir, dir string) yunyun.RelativePathDir {
+	if strings.HasPrefix(dir, "/") {
+		location = "."
+	}
+	return yunyun.RelativePathDir(filepath.Join(string(location), strings.Trim(dir, "/")))
+}
+
+// extractTags extracts the tags from `#+filetags: :go:web:` or `#+tags: go, web`.
+func extractTags(line string, option string) []string {
+	return yunyun.SplitTags(extractOptionLabel(line, option))
+}
+
+// appendTags appends the new tags, skipping the ones already there.
+func appendTags(tags []string, newTags []string) []string {
+	for _, tag := range newTags {
+		if len(yunyun.TagSlug(tag)) < 1 {
+			continue
+		}
+		if !gana.Anyf(func(v string) bool { return yunyun.TagSlug(v) == yunyun.TagSlug(tag) }, tags) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
 // extractGalleryFolder extracts gallery `FOLDER` from `#+begin_gallery FOLDER`.
 func extractGalleryFolder(line string) string {
 	path, err := extractCustomBlockOption(line, `path`, regexpPatternNoWhitespace)
@@ -2,6 +2,7 @@ package orgmode
 
 import (
 	"regexp"
+	"sync"
 )
 
 const (
@@ -37,6 +38,15 @@ const (
 	optionHtmlTags     = "html_tags:"
 	optionAttrHtml     = "attr_html:"
 	optionAuthor       = "author:"
+	optionListPages    = "list_pages:"
+	optionFileTags     = "filetags:"
+	optionTags         = "tags:"
+	optionTagCloud     = "tag_cloud"
+	optionSearch       = "search"
+	optionSeries       = "series:"
+	optionSeriesOrder  = "series_order:"
+	optionLanguage     = "language:"
+	optionTranslation  = "translation_key:"
 	horizontalLine     = "-----"
 
 	sectionLevelOne   = "* "
@@ -52,6 +62,25 @@ const (
 )
 
 var (
+	// ignoredOptions are the orgmode options darkness knows about and doesn't need,
+	// or handles before the parsing, so they aren't reported as unknown.
+	ignoredOptions = map[string]struct{}{
+		"title:":       {},
+		"startup:":     {},
+		"setupfile:":   {},
+		"macro:":       {},
+		"description:": {},
+		"email:":       {},
+		"name:":        {},
+		"results:":     {},
+		optionAttrHtml: {},
+		"begin_export": {},
+		"end_export":   {},
+		"end_src":      {},
+	}
+	// buildRegexOnce builds yunyun's regexes only once, as the pages are parsed
+	// at the same time and the regexes are shared.
+	buildRegexOnce sync.Once
 	// linkRegexp is the regexp for matching links
 	linkRegexp *regexp.Regexp
 	// attentionBlockRegexp is the regexp for matching attention blocks
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/thecsw/darkness/v3/emilia"
+	"github.com/thecsw/darkness/v3/emilia/hitagi"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
 )
@@ -23,10 +24,17 @@ func (p ParserOrgmode) Do(
 
 	page := yunyun.NewPage(
 		yunyun.WithFilename(filename),
-		yunyun.WithLocation(yunyun.RelativePathTrim(filename)),
+		yunyun.WithLocation(yunyun.FileLocation(filename)),
 		yunyun.WithContents(make([]*yunyun.Content, 0, 32)),
 	)
 	page.Author = p.Config.RSS.DefaultAuthor
+	page.SetupFiles = takeSetupFiles(p.Config, filename)
+	// Translations by the filename convention know their language,
+	// otherwise it's the site's default, unless `#+language:` says so.
+	page.Language = yunyun.FileLanguage(filename)
+	if len(page.Language) < 1 {
+		page.Language = p.Config.Website.Language()
+	}
 
 	// currentFlags uses flags to set options
 	currentFlags := yunyun.Bits(0)
@@ -53,6 +61,11 @@ func (p ParserOrgmode) Do(
 	// and then parsed out before leaving this parser.
 	optionsStrings := ""
 	defer emilia.FillAccoutrement(p.Config.Website.Tombs, &optionsStrings, page)
+	defer func() {
+		for _, option := range emilia.UnknownAccoutrementOptions(optionsStrings) {
+			p.Config.Runtime.Problems.Warn(hitagi.UnknownOptions, string(filename), "unknown option %q in #+options", option)
+		}
+	}()
 
 	// Optional parsing to see if H.E. has been left on the first line
 	// as the date
@@ -103,19 +116,39 @@ func (p ParserOrgmode) Do(
 			galleryPath = extractGalleryFolder(line)
 			galleryWidth = extractGalleryImagesPerRow(line)
 		},
-		optionEndGallery: func(line string) { removeFlag(yunyun.InGalleryFlag) },
-		optionCaption:    func(line string) { caption = extractCaptionTitle(line) },
-		optionDate:       func(line string) { page.Date = extractDate(line) },
-		optionHtmlHead:   func(line string) { page.HtmlHead = append(page.HtmlHead, extractHtmlHead(line)) },
-		optionOptions:    func(line string) { optionsStrings += extractOptions(line) + " " },
-		optionAttributes: func(line string) { attributes = extractAttributes(line) },
-		optionAuthor:     func(line string) { page.Author = extractAuthor(line) },
-		optionHtmlTags:   func(line string) { customHtmlTags = extractHtmlTags(line) },
+		optionEndGallery:  func(line string) { removeFlag(yunyun.InGalleryFlag) },
+		optionCaption:     func(line string) { caption = extractCaptionTitle(line) },
+		optionDate:        func(line string) { page.Date = extractDate(line) },
+		optionHtmlHead:    func(line string) { page.HtmlHead = append(page.HtmlHead, extractHtmlHead(line)) },
+		optionOptions:     func(line string) { optionsStrings += extractOptions(line) + " " },
+		optionAttributes:  func(line string) { attributes = extractAttributes(line) },
+		optionAuthor:      func(line string) { page.Author = extractAuthor(line) },
+		optionHtmlTags:    func(line string) { customHtmlTags = extractHtmlTags(line) },
+		optionFileTags:    func(line string) { page.Tags = appendTags(page.Tags, extractTags(line, optionFileTags)) },
+		optionTags:        func(line string) { page.Tags = appendTags(page.Tags, extractTags(line, optionTags)) },
+		optionTagCloud:    func(line string) { addContent(&yunyun.Content{Type: yunyun.TypeTagCloud}) },
+		optionSearch:      func(line string) { addContent(&yunyun.Content{Type: yunyun.TypeSearch}) },
+		optionSeries:      func(line string) { page.SeriesName = extractSeries(line) },
+		optionSeriesOrder: func(line string) { page.SeriesOrder = extractSeriesOrder(line) },
+		optionLanguage: func(line string) {
+			if language := extractLanguage(line); len(language) > 0 {
+				page.Language = language
+			}
+		},
+		optionTranslation: func(line string) { page.TranslationKey = extractTranslationKey(line) },
+		optionListPages: func(line string) {
+			addContent(&yunyun.Content{
+				Type:    yunyun.TypeListing,
+				Listing: extractListing(page.Location, line),
+			})
+		},
 	}
 
 	// Yunyun's markings default to orgmode
-	yunyun.ActiveMarkings.BuildRegex()
-	linkRegexp = yunyun.LinkRegexp
+	buildRegexOnce.Do(func() {
+		yunyun.ActiveMarkings.BuildRegex()
+		linkRegexp = yunyun.LinkRegexp
+	})
 
 	// Loop through the lines
 	for rawLine := range lines {
@@ -197,6 +230,8 @@ func (p ParserOrgmode) Do(
 		if val, ok := isOption(line); ok {
 			if action, ok := optionsActions[val]; ok {
 				action(rawLine)
+			} else if _, ok := ignoredOptions[strings.ToLower(val)]; !ok {
+				p.Config.Runtime.Problems.Warn(hitagi.UnknownOptions, string(filename), "unknown option #+%s", val)
 			}
 			currentContext = previousContext
 			continue
@@ -461,6 +461,54 @@ func TestIndexingFunctions(t *testing.T) {
 	}
 }
 
+// TestListingExtraction tests the extraction of `#+list_pages:` directives
+func TestListingExtraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		location yunyun.RelativePathDir
+		line     string
+		dir      yunyun.RelativePathDir
+		sort     yunyun.ListingSort
+		limit    int
+		recurse  bool
+	}{
+		{"Own directory", "blog", "#+list_pages:", "blog", yunyun.ListingSortDate, 0, false},
+		{"Relative directory", ".", "#+list_pages: blogs sort:date limit:20", "blogs", yunyun.ListingSortDate, 20, false},
+		{"Nested relative directory", "blog", "#+list_pages: 2024 sort:title", "blog/2024", yunyun.ListingSortTitle, 0, false},
+		{"Absolute directory", "blog/2024", "#+list_pages: /notes recursive:t", "notes", yunyun.ListingSortDate, 0, true},
+		{"Root directory", "blog", "#+list_pages: /", ".", yunyun.ListingSortDate, 0, false},
+		{"Bad options", "blog", "#+list_pages: sort:weird limit:-5", "blog", yunyun.ListingSortDate, 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			listing := extractListing(test.location, test.line)
+			if listing.Dir != test.dir || listing.Sort != test.sort ||
+				listing.Limit != test.limit || listing.Recursive != test.recurse {
+				t.Errorf("Expected %s to extract (%s, %s, %d, %t), got (%s, %s, %d, %t)", test.line,
+					test.dir, test.sort, test.limit, test.recurse,
+					listing.Dir, listing.Sort, listing.Limit, listing.Recursive)
+			}
+		})
+	}
+}
+
+// TestSeriesExtraction tests the extraction of the series names and orders
+func TestSeriesExtraction(t *testing.T) {
+	if name := extractSeries("#+series: Learning Go "); name != "Learning Go" {
+		t.Errorf("Expected series name %q, got %q", "Learning Go", name)
+	}
+	for line, want := range map[string]int{
+		"#+series_order: 3":    3,
+		"#+series_order: -1":   0,
+		"#+series_order: last": 0,
+	} {
+		if order := extractSeriesOrder(line); order != want {
+			t.Errorf("Expected %s to extract %d, got %d", line, want, order)
+		}
+	}
+}
+
 // TestFormParagraph tests the paragraph formation function
 func TestFormParagraph(t *testing.T) {
 	tests := []struct {
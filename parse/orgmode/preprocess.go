@@ -1,18 +1,20 @@
 package orgmode
 
 import (
+	"fmt"
 	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/thecsw/darkness/v3/emilia/alpha"
 	"github.com/thecsw/darkness/v3/yunyun"
 	"github.com/thecsw/gana"
-	"github.com/thecsw/rei"
 )
 
 const (
@@ -45,10 +47,6 @@ var (
 		optionBeginGallery: {}, optionEndGallery: {},
 	}
 
-	globalMacrosTable = map[string]string{}
-
-	expandedFiles = sync.Map{}
-
 	stringBuilderPool = sync.Pool{
 		New: func() any {
 			return new(strings.Builder)
@@ -56,6 +54,30 @@ var (
 	}
 )
 
+// globalMacrosKey is the key of the site's global macros in the build state, the
+// tables behind it are replaced whole and never changed.
+type globalMacrosKey struct{}
+
+// expandedFilesKey is the key of the contents of the setupfiles read during the
+// build, by their full paths, in the build state.
+type expandedFilesKey struct{}
+
+// usedSetupFilesKey is the key of the setupfiles pulled in by the pages being
+// parsed, by the full paths of the pages, in the build state.
+type usedSetupFilesKey struct{}
+
+// buildMap returns the map under the key in the build state of the site.
+func buildMap(conf *alpha.DarknessConfig, key any) *sync.Map {
+	return conf.Runtime.State.Load(key, func() any { return &sync.Map{} }).(*sync.Map)
+}
+
+// buildGlobalMacros returns where the global macros of the site are kept.
+func buildGlobalMacros(conf *alpha.DarknessConfig) *atomic.Pointer[map[string]string] {
+	return conf.Runtime.State.Load(globalMacrosKey{}, func() any {
+		return &atomic.Pointer[map[string]string]{}
+	}).(*atomic.Pointer[map[string]string])
+}
+
 func (p ParserOrgmode) preprocess(filename yunyun.RelativePathFile, what string) string {
 	// We will do everything in one pass here and build the final input file using
 	// a string builder for performance.
@@ -67,7 +89,7 @@ func (p ParserOrgmode) preprocess(filename yunyun.RelativePathFile, what string)
 
 	// Here we will store the macro definitions.
 	macrosLookupTable := make(map[string]string)
-	maps.Copy(macrosLookupTable, globalMacrosTable)
+	maps.Copy(macrosLookupTable, loadGlobalMacros(p.Config))
 
 	// We add a newline before lists start
 	previousLine := ""
@@ -168,7 +190,11 @@ func expandSetupFile(conf *alpha.DarknessConfig, filename yunyun.RelativePathFil
 		absoluteImportFilename = conf.Runtime.WorkDir.Join(setupFileTargetFilename)
 	}
 
+	// Remember that the page depends on the setupfile, even if it's cached.
+	recordSetupFile(conf, filename, conf.Runtime.WorkDir.Rel(absoluteImportFilename))
+
 	// Check the hot cache.
+	expandedFiles := buildMap(conf, expandedFilesKey{})
 	if expandedFile, alreadyExpanded := expandedFiles.Load(absoluteImportFilename); alreadyExpanded {
 		// See if the type is right, if it's not, drop in to the slow IO retrieval.
 		if stringified, isString := expandedFile.(string); isString {
@@ -176,17 +202,44 @@ func expandSetupFile(conf *alpha.DarknessConfig, filename yunyun.RelativePathFil
 		}
 	}
 
-	if !rei.FileMustExist(string(absoluteImportFilename)) {
-		conf.Runtime.Logger.Fatal("setupfile target not found",
-			"orgfile", filename, "target", absoluteImportFilename)
+	// Read the data and splash it into the input, the page fails if we can't.
+	data, err := os.ReadFile(filepath.Clean(string(absoluteImportFilename)))
+	if err != nil {
+		conf.Runtime.Problems.Fail(string(filename), fmt.Errorf("reading setupfile: %w", err))
+		return "", false
 	}
-
-	// Read the data and splash it into the input.
-	setupFileTargetContents := string(rei.Must(os.ReadFile(filepath.Clean(string(absoluteImportFilename)))))
+	setupFileTargetContents := string(data)
 	expandedFiles.Store(absoluteImportFilename, setupFileTargetContents)
 	return setupFileTargetContents, true
 }
 
+// recordSetupFile remembers that the page pulled in the setupfile.
+func recordSetupFile(conf *alpha.DarknessConfig, filename, setupFile yunyun.RelativePathFile) {
+	setupFiles, _ := buildMap(conf, usedSetupFilesKey{}).LoadOrStore(conf.Runtime.WorkDir.Join(filename), &sync.Map{})
+	setupFiles.(*sync.Map).Store(setupFile, struct{}{})
+}
+
+// takeSetupFiles returns the sorted setupfiles the page pulled in and forgets them.
+func takeSetupFiles(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile) []yunyun.RelativePathFile {
+	setupFiles, found := buildMap(conf, usedSetupFilesKey{}).LoadAndDelete(conf.Runtime.WorkDir.Join(filename))
+	if !found {
+		return nil
+	}
+	taken := make([]yunyun.RelativePathFile, 0, 2)
+	setupFiles.(*sync.Map).Range(func(key, _ any) bool {
+		taken = append(taken, key.(yunyun.RelativePathFile))
+		return true
+	})
+	slices.Sort(taken)
+	return taken
+}
+
+// ForgetSetupFile drops the cached contents of the setupfile, so
+// that the pages pulling it in will read it again.
+func ForgetSetupFile(conf *alpha.DarknessConfig, setupFile yunyun.RelativePathFile) {
+	buildMap(conf, expandedFilesKey{}).Delete(conf.Runtime.WorkDir.Join(setupFile))
+}
+
 func expandUntilSaturation(conf *alpha.DarknessConfig, filename yunyun.RelativePathFile, macrosLookupTable map[string]string, line string) (string, bool) {
 	contents, expanded := expandSetupFile(conf, filename, line)
 	if !expanded {
@@ -211,11 +264,33 @@ func expandUntilSaturation(conf *alpha.DarknessConfig, filename yunyun.RelativeP
 	return what, true
 }
 
+// ClearGlobalMacros forgets all the global macros of the site, so that
+// the removed ones don't linger after the macros file is read again.
+func ClearGlobalMacros(conf *alpha.DarknessConfig) {
+	buildGlobalMacros(conf).Store(nil)
+}
+
+// CollectGlobalMacros adds the macros defined in what to the global macros
+// of the site, returning true if there were any.
 func CollectGlobalMacros(
 	conf *alpha.DarknessConfig,
 	filename yunyun.RelativePathFile,
 	what string) bool {
-	return collectMacros(conf, filename, globalMacrosTable, what)
+	table := maps.Clone(loadGlobalMacros(conf))
+	if table == nil {
+		table = make(map[string]string)
+	}
+	found := collectMacros(conf, filename, table, what)
+	buildGlobalMacros(conf).Store(&table)
+	return found
+}
+
+// loadGlobalMacros returns the global macros of the site, which must not be changed.
+func loadGlobalMacros(conf *alpha.DarknessConfig) map[string]string {
+	if table := buildGlobalMacros(conf).Load(); table != nil {
+		return *table
+	}
+	return nil
 }
 
 func collectMacros(
@@ -233,8 +308,8 @@ func collectMacros(
 		macroLine := gana.SkipString(uint(len(macroPrefix)), line)
 		split := strings.SplitN(macroLine, " ", 2)
 		if len(split) != 2 {
-			conf.Runtime.Logger.Fatal("malformed macro definition found",
-				"path", filename, "line", line)
+			conf.Runtime.Problems.Fail(string(filename), fmt.Errorf("malformed macro definition: %s", line))
+			continue
 		}
 		macroDefsFound = true
 		macroName := strings.TrimSpace(split[0])
@@ -257,8 +332,8 @@ func expandMacros(conf *alpha.DarknessConfig,
 		fullMatch := match[0]
 		macroName := strings.TrimSpace(match[1])
 		if _, ok := macrosLookupTable[macroName]; !ok {
-			conf.Runtime.Logger.Fatal("macro used but not defined",
-				"path", filename, "macro", macroName)
+			conf.Runtime.Problems.Fail(string(filename), fmt.Errorf("macro %s used but not defined", macroName))
+			continue
 		}
 		macroBody := strings.ReplaceAll(macrosLookupTable[macroName], "\\n", "\n")
 		macroParamsString := strings.Trim(match[2], ")(")
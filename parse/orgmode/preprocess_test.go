@@ -9,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/thecsw/darkness/v3/emilia/alpha"
+	"github.com/thecsw/darkness/v3/yunyun"
 )
 
 // TestPreprocess tests the preprocess function with various inputs
@@ -421,6 +422,7 @@ func TestExpandSetupFile(t *testing.T) {
 			Level: log.FatalLevel, // Only show fatal errors
 		})
 		config.Runtime.WorkDir = alpha.WorkingDirectory(tmpDir)
+		config.Runtime.State = &alpha.BuildState{}
 
 		// First call should read from disk
 		result1, found1 := expandSetupFile(config, "main.org", "#+setupfile: setup.org")
@@ -444,6 +446,22 @@ func TestExpandSetupFile(t *testing.T) {
 		if result2 != setupFileContent {
 			t.Errorf("Cache not working. Expected original content, got: %q", result2)
 		}
+
+		// Once forgotten, the file should be read again
+		ForgetSetupFile(config, "setup.org")
+		result3, _ := expandSetupFile(config, "main.org", "#+setupfile: setup.org")
+		if result3 != modifiedContent {
+			t.Errorf("Forgotten setup file wasn't read again, got: %q", result3)
+		}
+
+		// The page should remember the setup file only once
+		setupFiles := takeSetupFiles(config, "main.org")
+		if !reflect.DeepEqual(setupFiles, []yunyun.RelativePathFile{"setup.org"}) {
+			t.Errorf("Expected the page to depend on setup.org, got: %v", setupFiles)
+		}
+		if setupFiles := takeSetupFiles(config, "main.org"); setupFiles != nil {
+			t.Errorf("Expected the setup files to be taken, got: %v", setupFiles)
+		}
 	})
 }
 
@@ -38,6 +38,18 @@ type Accoutrement struct {
 	RssPrefix string
 	// RssTitle is the title of the page in the rss feed. Still prefixed with RssPrefix.
 	RssTitle string
+	// Sitemap enables/disables the page's inclusion in the sitemap.
+	Sitemap AccoutrementFlip
+	// SitemapChangeFreq overrides the sitemap's changefreq of the page.
+	SitemapChangeFreq string
+	// SitemapPriority overrides the sitemap's priority of the page.
+	SitemapPriority string
+	// Related enables/disables the related pages at the bottom of the page.
+	Related AccoutrementFlip
+	// ReadingTime enables/disables the reading time next to the date.
+	ReadingTime AccoutrementFlip
+	// Backlinks enables/disables the pages linking here at the bottom of the page.
+	Backlinks AccoutrementFlip
 }
 
 // ExcludeHtmlHeadContains is a type to store excluded keywords for html head.
@@ -0,0 +1,91 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// AtomNamespace is the namespace of Atom documents.
+	AtomNamespace = "http://www.w3.org/2005/Atom"
+
+	// AtomFormat date format used in Atom spec (RFC 3339).
+	AtomFormat = time.RFC3339
+
+	// AtomDocs Atom spec implemented.
+	AtomDocs = "https://www.rfc-editor.org/rfc/rfc4287"
+)
+
+// Feed The "atom:feed" element is the document (i.e., top-level) element of
+// an Atom Feed Document, acting as a container for metadata and data
+// associated with the feed. Its element children consist of metadata
+// elements followed by zero or more atom:entry child elements.
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+
+	// Must be "http://www.w3.org/2005/Atom"
+	Xmlns string `xml:"xmlns,attr"`
+
+	// Any element defined by this specification MAY have an xml:lang
+	// attribute, whose content indicates the natural language for the
+	// element and its descendents.
+	Lang string `xml:"xml:lang,attr,omitempty"`
+
+	// The "atom:id" element conveys a permanent, universally unique
+	// identifier for an entry or feed.
+	//
+	// atom:feed elements MUST contain exactly one atom:id element.
+	Id string `xml:"id"`
+
+	// The "atom:title" element is a Text construct that conveys a
+	// human-readable title for an entry or feed.
+	//
+	// atom:feed elements MUST contain exactly one atom:title element.
+	Title *Text `xml:"title"`
+
+	// The "atom:subtitle" element is a Text construct that conveys a
+	// human-readable description or subtitle for a feed.
+	Subtitle *Text `xml:"subtitle,omitempty"`
+
+	// The "atom:updated" element is a Date construct indicating the most
+	// recent instant in time when an entry or feed was modified in a way
+	// the publisher considers significant.
+	//
+	// atom:feed elements MUST contain exactly one atom:updated element.
+	Updated string `xml:"updated"`
+
+	// The "atom:author" element is a Person construct that indicates the
+	// author of the entry or feed.
+	//
+	// atom:feed elements MUST contain one or more atom:author elements,
+	// unless all of the atom:feed element's child atom:entry elements
+	// contain at least one atom:author element.
+	Authors []*Person `xml:"author,omitempty"`
+
+	// The "atom:link" element defines a reference from an entry or feed to
+	// a Web resource.
+	//
+	// atom:feed elements SHOULD contain one atom:link element with a rel
+	// attribute value of "self". This is the preferred URI for retrieving
+	// Atom Feed Documents representing this Atom feed.
+	Links []*Link `xml:"link,omitempty"`
+
+	// The "atom:category" element conveys information about a category
+	// associated with an entry or feed.
+	Categories []*Category `xml:"category,omitempty"`
+
+	// The "atom:generator" element's content identifies the agent used to
+	// generate a feed, for debugging and other purposes.
+	Generator *Generator `xml:"generator,omitempty"`
+
+	// The "atom:icon" element's content is an IRI reference that identifies
+	// an image that provides iconic visual identification for a feed.
+	Icon string `xml:"icon,omitempty"`
+
+	// The "atom:rights" element is a Text construct that conveys information
+	// about rights held in and over an entry or feed.
+	Rights *Text `xml:"rights,omitempty"`
+
+	// Zero or more entries of the feed.
+	Entries []*Entry `xml:"entry"`
+}
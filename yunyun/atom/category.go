@@ -0,0 +1,19 @@
+package atom
+
+// Category The "atom:category" element conveys information about a category
+// associated with an entry or feed. This specification assigns no
+// meaning to the content (if any) of this element.
+type Category struct {
+	// The "term" attribute is a string that identifies the category to
+	// which the entry or feed belongs. Category elements MUST have a
+	// "term" attribute.
+	Term string `xml:"term,attr"`
+
+	// The "scheme" attribute is an IRI that identifies a categorization
+	// scheme. Category elements MAY have a "scheme" attribute.
+	Scheme string `xml:"scheme,attr,omitempty"`
+
+	// The "label" attribute provides a human-readable label for display in
+	// end-user applications. Category elements MAY have a "label" attribute.
+	Label string `xml:"label,attr,omitempty"`
+}
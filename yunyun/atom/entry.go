@@ -0,0 +1,56 @@
+package atom
+
+import "encoding/xml"
+
+// Entry The "atom:entry" element represents an individual entry, acting as a
+// container for metadata and data associated with the entry. This
+// element can appear as a child of the atom:feed element, or it can
+// appear as the document (i.e., top-level) element of a stand-alone
+// Atom Entry Document.
+type Entry struct {
+	XMLName xml.Name `xml:"entry"`
+
+	// The "atom:id" element conveys a permanent, universally unique
+	// identifier for an entry or feed.
+	//
+	// atom:entry elements MUST contain exactly one atom:id element.
+	Id string `xml:"id"`
+
+	// The "atom:title" element is a Text construct that conveys a
+	// human-readable title for an entry or feed.
+	//
+	// atom:entry elements MUST contain exactly one atom:title element.
+	Title *Text `xml:"title"`
+
+	// The "atom:updated" element is a Date construct indicating the most
+	// recent instant in time when an entry or feed was modified in a way
+	// the publisher considers significant.
+	//
+	// atom:entry elements MUST contain exactly one atom:updated element.
+	Updated string `xml:"updated"`
+
+	// The "atom:published" element is a Date construct indicating an
+	// instant in time associated with an event early in the life cycle of
+	// the entry.
+	Published string `xml:"published,omitempty"`
+
+	// The "atom:author" element is a Person construct that indicates the
+	// author of the entry or feed.
+	Authors []*Person `xml:"author,omitempty"`
+
+	// The "atom:link" element defines a reference from an entry or feed to
+	// a Web resource.
+	//
+	// atom:entry elements that contain no child atom:content element
+	// MUST contain at least one atom:link element with a rel attribute
+	// value of "alternate".
+	Links []*Link `xml:"link,omitempty"`
+
+	// The "atom:category" element conveys information about a category
+	// associated with an entry or feed.
+	Categories []*Category `xml:"category,omitempty"`
+
+	// The "atom:summary" element is a Text construct that conveys a short
+	// summary, abstract, or excerpt of an entry.
+	Summary *Text `xml:"summary,omitempty"`
+}
@@ -0,0 +1,19 @@
+package atom
+
+// Generator The "atom:generator" element's content identifies the agent used to
+// generate a feed, for debugging and other purposes.
+//
+// The content of this element, when present, MUST be a string that is a
+// human-readable name for the generating agent.
+type Generator struct {
+	// The atom:generator element MAY have a "uri" attribute whose value
+	// MUST be an IRI reference.
+	Uri string `xml:"uri,attr,omitempty"`
+
+	// The atom:generator element MAY have a "version" attribute that
+	// indicates the version of the generating agent.
+	Version string `xml:"version,attr,omitempty"`
+
+	// Human-readable name of the generating agent.
+	Value string `xml:",chardata"`
+}
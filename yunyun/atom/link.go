@@ -0,0 +1,32 @@
+package atom
+
+// Link The "atom:link" element defines a reference from an entry or feed to
+// a Web resource. This specification assigns no meaning to the content
+// (if any) of this element.
+type Link struct {
+	// The "href" attribute contains the link's IRI. atom:link elements MUST
+	// have an href attribute, whose value MUST be a IRI reference.
+	Href string `xml:"href,attr"`
+
+	// atom:link elements MAY have a "rel" attribute that indicates the link
+	// relation type. If the "rel" attribute is not present, the link
+	// element MUST be interpreted as if the link relation type is
+	// "alternate".
+	//
+	// Examples: "alternate", "related", "self", "enclosure", "via"
+	Rel string `xml:"rel,attr,omitempty"`
+
+	// On the link element, the "type" attribute's value is an advisory
+	// media type: it is a hint about the type of the representation that
+	// is expected to be returned when the value of the href attribute is
+	// dereferenced.
+	Type string `xml:"type,attr,omitempty"`
+
+	// The "hreflang" attribute's content describes the language of the
+	// resource pointed to by the href attribute.
+	Hreflang string `xml:"hreflang,attr,omitempty"`
+
+	// The "title" attribute conveys human-readable information about the
+	// link.
+	Title string `xml:"title,attr,omitempty"`
+}
@@ -0,0 +1,24 @@
+package atom
+
+// Person A Person construct is an element that describes a person,
+// corporation, or similar entity (hereafter, 'person').
+//
+// This specification assigns no significance to the order of appearance
+// of the child elements in a Person construct. Person constructs allow
+// extension Metadata elements.
+type Person struct {
+	// The "atom:name" element's content conveys a human-readable name for
+	// the person. Person constructs MUST contain exactly one "atom:name"
+	// element.
+	Name string `xml:"name"`
+
+	// The "atom:uri" element's content conveys an IRI associated with the
+	// person. Person constructs MAY contain an atom:uri element, but MUST
+	// NOT contain more than one.
+	Uri string `xml:"uri,omitempty"`
+
+	// The "atom:email" element's content conveys an e-mail address
+	// associated with the person. Person constructs MAY contain an
+	// atom:email element, but MUST NOT contain more than one.
+	Email string `xml:"email,omitempty"`
+}
@@ -0,0 +1,21 @@
+package atom
+
+// Text A Text construct contains human-readable text, usually in small
+// quantities. The content of Text constructs is Language-Sensitive.
+//
+// Text constructs MAY have a "type" attribute. When present, the value
+// MUST be one of "text", "html", or "xhtml". If the "type" attribute
+// is not provided, Atom Processors MUST behave as though it were
+// present with a value of "text".
+type Text struct {
+	// One of "text", "html", or "xhtml".
+	Type string `xml:"type,attr,omitempty"`
+
+	// The text itself.
+	Value string `xml:",chardata"`
+}
+
+// PlainText returns a plain text construct.
+func PlainText(what string) *Text {
+	return &Text{Type: "text", Value: what}
+}
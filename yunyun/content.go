@@ -74,6 +74,12 @@ type Content struct {
 	// List is the list of items, unordered.
 	List []ListItem
 
+	// Listing is the listing of pages.
+	Listing *Listing
+
+	// TagCloud is the list of tags of the site.
+	TagCloud []*SiteTag
+
 	// GalleryImagesPerRow stores the number of default images per row,
 	// therefore what flex class to use -- defaults to 3.
 	GalleryImagesPerRow uint
@@ -143,6 +149,15 @@ func (c Content) IsRawHtml() bool { return c.Type == TypeRawHtml }
 // IsTableOfContents if the content is table of contents.
 func (c Content) IsTableOfContents() bool { return c.Type == TypeTableOfContents }
 
+// IsListing tells us if the content is a listing of pages.
+func (c Content) IsListing() bool { return c.Type == TypeListing }
+
+// IsTagCloud tells us if the content is a tag cloud.
+func (c Content) IsTagCloud() bool { return c.Type == TypeTagCloud }
+
+// IsSearch tells us if the content is a search box.
+func (c Content) IsSearch() bool { return c.Type == TypeSearch }
+
 // IsRawHtmlUnsafe tells us if the html block is raw and unsafe.
 func (c Content) IsRawHtmlUnsafe() bool { return HasFlag(&c.Options, InRawHtmlFlagUnsafe) }
 
@@ -28,6 +28,12 @@ const (
 	TypeDetails
 	// TypeTableOfContents is the type that splashes links to headings.
 	TypeTableOfContents
+	// TypeListing is the type that lists pages of a directory.
+	TypeListing
+	// TypeTagCloud is the type that lists all the tags of the site.
+	TypeTagCloud
+	// TypeSearch is the type of the search box with results.
+	TypeSearch
 	// TypeShouldBeLastDoNotTouch the last type that should not be touched --
 	// It's used to verify consistency within darkness.
 	TypeShouldBeLastDoNotTouch
@@ -0,0 +1,14 @@
+package jsonfeed
+
+// Author An author object has several members. These are all optional,
+// but if you provide an author object, then at least one is required.
+type Author struct {
+	// Name (string, optional) is the author's name.
+	Name string `json:"name,omitempty"`
+
+	// Url (string, optional) is the URL of a site owned by the author.
+	Url string `json:"url,omitempty"`
+
+	// Avatar (string, optional) is the URL for an image for the author.
+	Avatar string `json:"avatar,omitempty"`
+}
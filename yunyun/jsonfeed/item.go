@@ -0,0 +1,69 @@
+package jsonfeed
+
+// Item Each item in the array of `items` of a feed. An item may represent
+// a blog post or anything else, with at least one of `content_html` or
+// `content_text` being present.
+type Item struct {
+	// Id (string, required) is unique for that item for that feed over
+	// time. If an item is ever updated, the id should be unchanged.
+	Id string `json:"id"`
+
+	// Url (string, optional) is the URL of the resource described by the
+	// item. It's the permalink.
+	Url string `json:"url,omitempty"`
+
+	// Title (string, optional) is plain text. Microblog items in
+	// particular may omit titles.
+	Title string `json:"title,omitempty"`
+
+	// ContentHtml and ContentText are each optional strings, but one or
+	// both must be present. This is the HTML or plain text of the item.
+	ContentHtml string `json:"content_html,omitempty"`
+	ContentText string `json:"content_text,omitempty"`
+
+	// Summary (string, optional) is a plain text sentence or two
+	// describing the item.
+	Summary string `json:"summary,omitempty"`
+
+	// Image (string, optional) is the URL of the main image for the item.
+	Image string `json:"image,omitempty"`
+
+	// DatePublished (string, optional) specifies the date in RFC 3339
+	// format.
+	DatePublished string `json:"date_published,omitempty"`
+
+	// DateModified (string, optional) specifies the modification date in
+	// RFC 3339 format.
+	DateModified string `json:"date_modified,omitempty"`
+
+	// Authors (array of objects, optional) has the same structure as the
+	// top-level authors.
+	Authors []*Author `json:"authors,omitempty"`
+
+	// Tags (array of strings, optional) can have any plain text values
+	// you want. Tags tend to be just one word, but they may be anything.
+	Tags []string `json:"tags,omitempty"`
+
+	// Language (string, optional) is the language for this item, using
+	// the same format as the top-level language field.
+	Language string `json:"language,omitempty"`
+
+	// Reading (object, optional) is the darkness extension with the
+	// word count and the reading time of the item.
+	Reading *Reading `json:"_reading,omitempty"`
+}
+
+// Reading is the extension object describing how long the item is.
+type Reading struct {
+	// Words (integer) is the number of words, every CJK character is one.
+	Words int `json:"words"`
+
+	// Minutes (integer) is the estimated reading time in minutes.
+	Minutes int `json:"minutes"`
+
+	// CodeBlocks (integer) is the number of source code blocks.
+	CodeBlocks int `json:"code_blocks"`
+
+	// Images (integer) is the number of images.
+	Images int `json:"images"`
+}
@@ -0,0 +1,64 @@
+package jsonfeed
+
+import "time"
+
+const (
+	// JsonFeedVersion is the URL of the version of the format the feed uses.
+	JsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+	// JsonFeedFormat date format used in JSON Feed spec (RFC 3339).
+	JsonFeedFormat = time.RFC3339
+
+	// JsonFeedMimeType is the mime type to use for JSON Feed.
+	JsonFeedMimeType = "application/feed+json"
+)
+
+// Feed JSON Feed is a syndication format similar to RSS and Atom but
+// using JSON instead of XML. The top-level object of a feed is described
+// here, with its items being a list of `Item`s.
+type Feed struct {
+	// Version (string, required) is the URL of the version of the format
+	// the feed uses. This should appear at the very top, though we recognize
+	// that not all JSON generators allow for ordering.
+	Version string `json:"version"`
+
+	// Title (string, required) is the name of the feed, which will often
+	// correspond to the name of the website (blog, for instance), though
+	// not necessarily.
+	Title string `json:"title"`
+
+	// HomePageUrl (string, optional but strongly recommended) is the URL
+	// of the resource that the feed describes. This resource may or may
+	// not actually be a "home" page, but it should be an HTML page.
+	HomePageUrl string `json:"home_page_url,omitempty"`
+
+	// FeedUrl (string, optional but strongly recommended) is the URL of
+	// the feed, and serves as the unique identifier for the feed.
+	FeedUrl string `json:"feed_url,omitempty"`
+
+	// Description (string, optional) provides more detail, beyond the
+	// title, on what the feed is about. A feed reader may display this
+	// text.
+	Description string `json:"description,omitempty"`
+
+	// Icon (string, optional) is the URL of an image for the feed
+	// suitable to be used in a timeline, much the way an avatar might
+	// be used.
+	Icon string `json:"icon,omitempty"`
+
+	// Favicon (string, optional) is the URL of an image for the feed
+	// suitable to be used in a source list. It should be square and
+	// relatively small, but not smaller than 64 x 64.
+	Favicon string `json:"favicon,omitempty"`
+
+	// Authors (array of objects, optional) specifies one or more feed
+	// authors.
+	Authors []*Author `json:"authors,omitempty"`
+
+	// Language (string, optional) is the primary language for the feed
+	// in the format specified in RFC 5646.
+	Language string `json:"language,omitempty"`
+
+	// Items is an array, and is required.
+	Items []*Item `json:"items"`
+}
@@ -0,0 +1,118 @@
+package jsonld
+
+import "time"
+
+const (
+	// JsonLdContext is the vocabulary of all the nodes.
+	JsonLdContext = "https://schema.org"
+
+	// JsonLdFormat is the ISO 8601 date format used by schema.org.
+	JsonLdFormat = time.RFC3339
+
+	// JsonLdMimeType is the type of the script with the JSON-LD.
+	JsonLdMimeType = "application/ld+json"
+)
+
+// Graph is the top-level JSON-LD document, which holds all the nodes
+// describing the page, so a single script is enough.
+type Graph struct {
+	// Context is always "https://schema.org".
+	Context string `json:"@context"`
+
+	// Nodes are the things described, like the article and its breadcrumbs.
+	Nodes []any `json:"@graph"`
+}
+
+// Article is a schema.org `Article`, or any of its subtypes, like `BlogPosting`.
+type Article struct {
+	// Type is "Article", "BlogPosting", "NewsArticle", etc.
+	Type string `json:"@type"`
+
+	// Headline is the title of the article.
+	Headline string `json:"headline"`
+
+	// Description is a short plain text description of the article.
+	Description string `json:"description,omitempty"`
+
+	// Url is the canonical url of the article.
+	Url string `json:"url"`
+
+	// MainEntityOfPage is the page the article is the main thing of, same as the url.
+	MainEntityOfPage string `json:"mainEntityOfPage,omitempty"`
+
+	// DatePublished is when the article was first published.
+	DatePublished string `json:"datePublished,omitempty"`
+
+	// DateModified is when the article was last changed.
+	DateModified string `json:"dateModified,omitempty"`
+
+	// Author is the author of the article.
+	Author *Person `json:"author,omitempty"`
+
+	// Image is the url of the article's preview image.
+	Image string `json:"image,omitempty"`
+
+	// InLanguage is the language tag of the article.
+	InLanguage string `json:"inLanguage,omitempty"`
+
+	// Keywords are the tags of the article.
+	Keywords []string `json:"keywords,omitempty"`
+
+	// WordCount is the number of words in the article.
+	WordCount int `json:"wordCount,omitempty"`
+}
+
+// Person is a schema.org `Person`.
+type Person struct {
+	// Type is always "Person".
+	Type string `json:"@type"`
+
+	// Name is the name of the person.
+	Name string `json:"name"`
+
+	// Url is the website of the person.
+	Url string `json:"url,omitempty"`
+}
+
+// BreadcrumbList is a schema.org `BreadcrumbList`, the chain
+// of pages leading to the current one.
+type BreadcrumbList struct {
+	// Type is always "BreadcrumbList".
+	Type string `json:"@type"`
+
+	// Items are the breadcrumbs, starting from the root.
+	Items []*ListItem `json:"itemListElement"`
+}
+
+// ListItem is a schema.org `ListItem` of the breadcrumbs.
+type ListItem struct {
+	// Type is always "ListItem".
+	Type string `json:"@type"`
+
+	// Position is the position of the breadcrumb, starting from 1.
+	Position int `json:"position"`
+
+	// Name is the title of the page.
+	Name string `json:"name"`
+
+	// Item is the url of the page.
+	Item string `json:"item"`
+}
+
+// WebSite is a schema.org `WebSite`, described on the root page.
+type WebSite struct {
+	// Type is always "WebSite".
+	Type string `json:"@type"`
+
+	// Name is the title of the website.
+	Name string `json:"name"`
+
+	// Url is the url of the website.
+	Url string `json:"url"`
+
+	// Description is a short plain text description of the website.
+	Description string `json:"description,omitempty"`
+
+	// InLanguage is the language tag of the website.
+	InLanguage string `json:"inLanguage,omitempty"`
+}
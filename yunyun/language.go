@@ -0,0 +1,85 @@
+package yunyun
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// translationBase is the name of the files that can have translations
+	// by the filename convention, as in `index.ja.org`.
+	translationBase = "index"
+)
+
+// languageTagRegexp matches the simple BCP 47 language tags, like
+// `en`, `ja`, `pt-BR`, or `zh-Hant`.
+var languageTagRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// languageNames are the native names of the common languages, shown
+// in the language switcher.
+var languageNames = map[string]string{
+	"ar": "العربية",
+	"de": "Deutsch",
+	"en": "English",
+	"es": "Español",
+	"fr": "Français",
+	"it": "Italiano",
+	"ja": "日本語",
+	"kk": "Қазақша",
+	"ko": "한국어",
+	"nl": "Nederlands",
+	"pl": "Polski",
+	"pt": "Português",
+	"ru": "Русский",
+	"sv": "Svenska",
+	"tr": "Türkçe",
+	"uk": "Українська",
+	"zh": "中文",
+}
+
+// IsLanguageTag returns true if the tag looks like a language tag.
+func IsLanguageTag(tag string) bool {
+	return languageTagRegexp.MatchString(tag)
+}
+
+// NormalizeLanguage turns locales like `en_US` into language tags like `en-US`.
+func NormalizeLanguage(locale string) string {
+	return strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+}
+
+// LanguageName returns the native name of the language, or the tag
+// itself if we don't know it.
+func LanguageName(tag string) string {
+	if name, ok := languageNames[tag]; ok {
+		return name
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	if name, ok := languageNames[primary]; ok {
+		return name + " (" + tag + ")"
+	}
+	return tag
+}
+
+// FileLanguage returns the language of the translation from its filename,
+// so `index.ja.org` gives `ja`, empty if the file is not a translation.
+func FileLanguage[T RelativePathFile | FullPathFile](filename T) string {
+	base := filepath.Base(string(filename))
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	name, language, found := strings.Cut(base, ".")
+	if !found || name != translationBase || !IsLanguageTag(language) {
+		return ""
+	}
+	return language
+}
+
+// FileLocation returns the location of the page in the file, which is its
+// directory, translations by the filename convention are put in their own
+// language directory, so `notes/index.ja.org` is at `notes/ja`.
+func FileLocation(filename RelativePathFile) RelativePathDir {
+	location := RelativePathTrim(filename)
+	if language := FileLanguage(filename); len(language) > 0 {
+		return RelativePathDir(filepath.Join(string(location), language))
+	}
+	return location
+}
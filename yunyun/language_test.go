@@ -0,0 +1,50 @@
+package yunyun
+
+import (
+	"testing"
+)
+
+// TestFileLocation tests that translations by the filename convention
+// get their own language directories
+func TestFileLocation(t *testing.T) {
+	tests := []struct {
+		input            RelativePathFile
+		expectedLanguage string
+		expectedLocation RelativePathDir
+	}{
+		{"index.org", "", "."},
+		{"notes/index.org", "", "notes"},
+		{"notes/index.ja.org", "ja", "notes/ja"},
+		{"index.pt-BR.org", "pt-BR", "pt-BR"},
+		{"notes/page.ja.org", "", "notes"},
+		{"notes/index.draft.org", "", "notes"},
+	}
+	for _, tt := range tests {
+		if got := FileLanguage(tt.input); got != tt.expectedLanguage {
+			t.Errorf("FileLanguage(%q) = %q, expected %q", tt.input, got, tt.expectedLanguage)
+		}
+		if got := FileLocation(tt.input); got != tt.expectedLocation {
+			t.Errorf("FileLocation(%q) = %q, expected %q", tt.input, got, tt.expectedLocation)
+		}
+	}
+}
+
+// TestTranslations tests that pages are grouped by their translation keys
+func TestTranslations(t *testing.T) {
+	site := NewSite([]*SitePage{
+		{Location: "notes", Language: "en", TranslationKey: "dir:notes"},
+		{Location: "notes/ja", Language: "ja", TranslationKey: "dir:notes"},
+		{Location: "notes/a", Language: "en", TranslationKey: "dir:notes/a"},
+		{Location: "notes/a/ja", Language: "ja", TranslationKey: "dir:notes/a", Draft: true},
+	})
+	translations := site.Translations("notes/ja")
+	if len(translations) != 2 || translations[0].Location != "notes" || translations[1].Location != "notes/ja" {
+		t.Errorf("Translations(notes/ja) = %v, expected notes and notes/ja", translations)
+	}
+	if translations := site.Translations("notes/a"); translations != nil {
+		t.Errorf("Translations(notes/a) = %v, expected none as the translation is a draft", translations)
+	}
+	if children := site.Children("notes", false); len(children) != 1 || children[0].Location != "notes/a" {
+		t.Errorf("Children(notes) = %v, expected only notes/a", children)
+	}
+}
@@ -0,0 +1,37 @@
+package yunyun
+
+// ListingSort is the order of the pages in a listing.
+type ListingSort string
+
+const (
+	// ListingSortDate sorts the pages from newest to oldest, pages
+	// without dates go last.
+	ListingSortDate ListingSort = "date"
+	// ListingSortTitle sorts the pages alphabetically by their titles.
+	ListingSortTitle ListingSort = "title"
+)
+
+// Listing is a list of pages in a directory, declared with `#+list_pages:`
+// or generated for a directory. Its items are filled in during enrichment.
+type Listing struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Dir is the directory whose pages are listed.
+	Dir RelativePathDir
+	// Sort is the order of the listed pages.
+	Sort ListingSort
+	// Limit is the maximum number of listed pages, 0 means no limit.
+	Limit int
+	// Recursive will also list pages in the nested directories.
+	Recursive bool
+	// Tag only lists the pages with the tag's slug, if set.
+	Tag string
+	// DatedOnly skips the pages without dates, like feeds do.
+	DatedOnly bool
+	// PageSize is the number of listed pages per page, 0 lists them all.
+	PageSize int
+	// Page is the page of the listing to show, starting at 1.
+	Page int
+	// Items are the listed pages, filled in during enrichment.
+	Items []*SitePage
+}
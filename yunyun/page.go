@@ -2,6 +2,7 @@ package yunyun
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/thecsw/gana"
 )
@@ -23,6 +24,11 @@ type Page struct {
 	Date string
 	// File is the original filename of the page (optional).
 	File RelativePathFile
+	// Tags are the tags of the page from `#+filetags:` or `#+tags:`.
+	Tags []string
+	// SetupFiles are the files pulled in with `#+setupfile:`, relative
+	// to the working directory.
+	SetupFiles []RelativePathFile
 	// Contents is the contents of the page.
 	Contents Contents
 	// Scripts is the scripts of the page.
@@ -36,6 +42,34 @@ type Page struct {
 	// DateHoloscene tells us whether the first paragraph
 	// on the page is given as holoscene date stamp.
 	DateHoloscene bool
+	// Pagination is set if the page is one of many listing pages.
+	Pagination *Pagination
+	// Related are the pages similar to this one, filled in during enrichment.
+	Related []*SitePage
+	// Stats are the word count and alike, filled in during enrichment.
+	Stats *PageStats
+	// Backlinks are the pages linking to this one, filled in during enrichment.
+	Backlinks []*Backlink
+	// SeriesName is the name of the series from `#+series:`.
+	SeriesName string
+	// SeriesOrder is the part of the series from `#+series_order:`, 0 if not set.
+	SeriesOrder int
+	// Series is the sequence of pages this page is in, filled in during enrichment.
+	Series *Series
+	// Language is the language tag of the page from `#+language:`,
+	// the filename, or the site's default.
+	Language string
+	// TranslationKey is the key from `#+translation_key:` shared by
+	// the translations of the page.
+	TranslationKey string
+	// Translations are the page in all of its languages, filled in during enrichment.
+	Translations []*SitePage
+	// Modified is when the page's source was last committed, filled in during
+	// enrichment, zero if unknown.
+	Modified time.Time
+	// Breadcrumbs are the pages of the directories above this one, starting
+	// from the root, filled in during enrichment.
+	Breadcrumbs []*SitePage
 }
 
 // MetaTag is a struct for holding the meta tag.
@@ -0,0 +1,22 @@
+package yunyun
+
+// Pagination tells us where the page is in a listing that is
+// split across multiple pages.
+type Pagination struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Current is the number of this page, starting at 1.
+	Current int
+	// Total is the total number of pages.
+	Total int
+	// Prev is the location of the previous page, empty on the first one.
+	Prev RelativePathDir
+	// Next is the location of the next page, empty on the last one.
+	Next RelativePathDir
+}
+
+// HasPrev returns true if there is a previous page.
+func (p *Pagination) HasPrev() bool { return p != nil && len(p.Prev) > 0 }
+
+// HasNext returns true if there is a next page.
+func (p *Pagination) HasNext() bool { return p != nil && len(p.Next) > 0 }
@@ -0,0 +1,43 @@
+// Package search is the format of the search index that darkness writes
+// and the search page's script reads.
+package search
+
+// Manifest lists all the shards of the search index.
+type Manifest struct {
+	// Shards are the shards of the index, sorted by their names.
+	Shards []*ShardInfo `json:"shards"`
+}
+
+// ShardInfo describes a single shard in the manifest.
+type ShardInfo struct {
+	// Name is the name of the shard, which is its directory.
+	Name string `json:"name"`
+	// Url is the full url of the shard's file.
+	Url string `json:"url"`
+	// Documents is the number of documents in the shard.
+	Documents int `json:"documents"`
+}
+
+// Shard is a part of the search index, holding the pages of a directory.
+type Shard struct {
+	// Documents are the indexed pages.
+	Documents []*Document `json:"documents"`
+	// Terms is the inverted index, mapping every term to its postings.
+	Terms map[string][]Posting `json:"terms"`
+}
+
+// Document is a single indexed page.
+type Document struct {
+	// Url is the full url of the page.
+	Url string `json:"url"`
+	// Title is the plain text title of the page.
+	Title string `json:"title"`
+	// Headings are the plain text headings of the page.
+	Headings []string `json:"headings,omitempty"`
+	// Text is the beginning of the page's plain text, shown in results.
+	Text string `json:"text"`
+}
+
+// Posting is a pair of the document's index in the shard and the term's
+// score in that document, encoded as `[document, score]` to keep it small.
+type Posting [2]int
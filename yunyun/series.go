@@ -0,0 +1,33 @@
+package yunyun
+
+// Series is the sequence of pages that a page is a part of, either a named
+// series from `#+series:` or all the dated pages of a directory.
+type Series struct {
+	// Name is the name of the series, empty for directories.
+	Name string
+	// Parts are all the pages of the series in order.
+	Parts []*SitePage
+	// Current is the index of the page in the parts.
+	Current int
+}
+
+// Prev returns the previous part, nil if this is the first one.
+func (s *Series) Prev() *SitePage {
+	if s == nil || s.Current < 1 {
+		return nil
+	}
+	return s.Parts[s.Current-1]
+}
+
+// Next returns the next part, nil if this is the last one.
+func (s *Series) Next() *SitePage {
+	if s == nil || s.Current+1 >= len(s.Parts) {
+		return nil
+	}
+	return s.Parts[s.Current+1]
+}
+
+// IsNamed returns true if the series was declared with `#+series:`.
+func (s *Series) IsNamed() bool {
+	return s != nil && len(s.Name) > 0
+}
@@ -0,0 +1,237 @@
+package yunyun
+
+import (
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Site is the read-only view of all the pages in a single build, it is
+// built after parsing and before exporting, so that pages can refer to
+// each other (listings, tags, etc.) without touching each other's data.
+type Site struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Pages are the summaries of all the pages, sorted by location.
+	Pages []*SitePage
+	// byLocation is the index of pages by their location.
+	byLocation map[RelativePathDir]*SitePage
+	// backlinks are the pages linking to the page at the location.
+	backlinks map[RelativePathDir][]*Backlink
+	// translations are the pages by their translation keys.
+	translations map[string][]*SitePage
+}
+
+// SitePage is the summary of a single page in the site.
+type SitePage struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Location is the location of the page.
+	Location RelativePathDir
+	// File is the original filename of the page.
+	File RelativePathFile
+	// Title is the title of the page.
+	Title string
+	// Author is the author of the page.
+	Author string
+	// Date is the date of the page as written by the user.
+	Date string
+	// Published is the parsed date of the page, zero if the page has none.
+	Published time.Time
+	// Description is the plain text description of the page.
+	Description string
+	// Preview is the preview image of the page, relative to the page's
+	// location, empty if the page has none.
+	Preview RelativePathFile
+	// Draft tells us whether the page is a draft.
+	Draft bool
+	// Tags are the tags of the page.
+	Tags []string
+	// Terms are the weighted terms of the page's text, used
+	// to find related pages.
+	Terms Terms
+	// Headings are the plain text headings of the page.
+	Headings []string
+	// Text is the plain text of the page's contents.
+	Text string
+	// Stats are the word count, reading time, and alike.
+	Stats PageStats
+	// Links are the links from this page to the other pages.
+	Links []SiteLink
+	// SeriesName is the name of the page's series, empty if none.
+	SeriesName string
+	// SeriesOrder is the part of the series, 0 if not set.
+	SeriesOrder int
+	// Language is the language tag of the page.
+	Language string
+	// TranslationKey is the key shared by the page's translations, which is
+	// the page's directory, unless the page set its own key.
+	TranslationKey string
+	// Modified is when the page's source was last committed, zero if unknown.
+	Modified time.Time
+}
+
+// Clone returns a copy of the page summary that can be weighed and
+// dated on its own, the rest of the slices are shared.
+func (p *SitePage) Clone() *SitePage {
+	clone := *p
+	clone.Terms = slices.Clone(p.Terms)
+	return &clone
+}
+
+// SiteLink is a link from one page to another.
+type SiteLink struct {
+	// Target is the location of the linked page.
+	Target RelativePathDir
+	// Context is the plain text of the paragraph with the link.
+	Context string
+}
+
+// Backlink is a page that links to another page.
+type Backlink struct {
+	// Page is the page with the link.
+	Page *SitePage
+	// Context is the plain text of the paragraph with the link.
+	Context string
+}
+
+// NewSite creates a new `Site` from the page summaries.
+func NewSite(pages []*SitePage) *Site {
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Location < pages[j].Location })
+	s := &Site{
+		Pages:        pages,
+		byLocation:   make(map[RelativePathDir]*SitePage, len(pages)),
+		backlinks:    make(map[RelativePathDir][]*Backlink),
+		translations: make(map[string][]*SitePage),
+	}
+	for _, page := range pages {
+		s.byLocation[page.Location] = page
+		if !page.Draft && len(page.TranslationKey) > 0 {
+			s.translations[page.TranslationKey] = append(s.translations[page.TranslationKey], page)
+		}
+	}
+	// Invert the links, drafts and links to nowhere don't count.
+	for _, page := range pages {
+		if page.Draft {
+			continue
+		}
+		for _, link := range page.Links {
+			if link.Target == page.Location || s.byLocation[link.Target] == nil {
+				continue
+			}
+			s.backlinks[link.Target] = append(s.backlinks[link.Target], &Backlink{Page: page, Context: link.Context})
+		}
+	}
+	return s
+}
+
+// Backlinks returns the pages linking to the page at the location, sorted
+// by their locations.
+func (s *Site) Backlinks(location RelativePathDir) []*Backlink {
+	if s == nil {
+		return nil
+	}
+	return s.backlinks[location]
+}
+
+// Translations returns the page at the location in all of its languages,
+// itself included, sorted by the languages, nil if it has no translations.
+func (s *Site) Translations(location RelativePathDir) []*SitePage {
+	page := s.Page(location)
+	if page == nil || len(page.TranslationKey) < 1 {
+		return nil
+	}
+	// Only one page per language, the page itself wins.
+	translations := []*SitePage{page}
+	seen := map[string]bool{page.Language: true}
+	for _, translation := range s.translations[page.TranslationKey] {
+		if !seen[translation.Language] {
+			seen[translation.Language] = true
+			translations = append(translations, translation)
+		}
+	}
+	if len(translations) < 2 {
+		return nil
+	}
+	sort.Slice(translations, func(i, j int) bool { return translations[i].Language < translations[j].Language })
+	return translations
+}
+
+// Page returns the page at the location, nil if not found.
+func (s *Site) Page(location RelativePathDir) *SitePage {
+	if s == nil {
+		return nil
+	}
+	return s.byLocation[location]
+}
+
+// Children returns the pages that are inside of the directory, only the direct
+// children unless recursive, the directory's own page is never included. If
+// the directory has a page, only the children in its language are returned.
+func (s *Site) Children(dir RelativePathDir, recursive bool) []*SitePage {
+	if s == nil {
+		return nil
+	}
+	parent := s.Page(dir)
+	children := make([]*SitePage, 0, 8)
+	for _, page := range s.Pages {
+		if page.Location == dir || !IsInsideDir(page.Location, dir) {
+			continue
+		}
+		if parent != nil && page.Language != parent.Language {
+			continue
+		}
+		if !recursive && RelativePathDir(filepath.Dir(string(page.Location))) != dir {
+			continue
+		}
+		children = append(children, page)
+	}
+	return children
+}
+
+// Tagged returns all the pages with the tag's slug.
+func (s *Site) Tagged(slug string) []*SitePage {
+	if s == nil {
+		return nil
+	}
+	tagged := make([]*SitePage, 0, 8)
+	for _, page := range s.Pages {
+		if page.HasTag(slug) {
+			tagged = append(tagged, page)
+		}
+	}
+	return tagged
+}
+
+// TagNames returns the names of all the tags by their slugs, the name of a
+// tag is how it was written on the first page (by location) that used it.
+func (s *Site) TagNames() map[string]string {
+	names := make(map[string]string)
+	if s == nil {
+		return names
+	}
+	for _, page := range s.Pages {
+		for _, tag := range page.Tags {
+			slug := TagSlug(tag)
+			if _, seen := names[slug]; !seen && len(slug) > 0 {
+				names[slug] = tag
+			}
+		}
+	}
+	return names
+}
+
+// HasDate returns true if the page has a valid date.
+func (p *SitePage) HasDate() bool {
+	return !p.Published.IsZero()
+}
+
+// IsInsideDir returns true if the location is the dir or is inside of it,
+// the root dir (".") contains everything.
+func IsInsideDir(location, dir RelativePathDir) bool {
+	if dir == "." || dir == "" || location == dir {
+		return true
+	}
+	return len(location) > len(dir) && location[:len(dir)] == dir && location[len(dir)] == '/'
+}
@@ -0,0 +1,31 @@
+package sitemap
+
+import "encoding/xml"
+
+// Index Encapsulates information about all of the Sitemaps in the file.
+//
+// You can provide multiple Sitemap files, but each Sitemap file that you
+// provide must have no more than 50,000 URLs and must be no larger than
+// 50MB (52,428,800 bytes). If you want to list more than 50,000 URLs, you
+// must create multiple Sitemap files, and list them in a Sitemap index file.
+type Index struct {
+	XMLName xml.Name `xml:"sitemapindex"`
+
+	// Must be "http://www.sitemaps.org/schemas/sitemap/0.9"
+	Xmlns string `xml:"xmlns,attr"`
+
+	// Encapsulates information about an individual Sitemap.
+	Sitemaps []*Sitemap `xml:"sitemap"`
+}
+
+// Sitemap Encapsulates information about an individual Sitemap.
+type Sitemap struct {
+	// Identifies the location of the Sitemap. This location can be a
+	// Sitemap, an Atom file, RSS file or a simple text file.
+	Loc string `xml:"loc"`
+
+	// Identifies the time that the corresponding Sitemap file was modified.
+	// It does not correspond to the time that any of the pages listed in
+	// that Sitemap were changed.
+	LastMod string `xml:"lastmod,omitempty"`
+}
@@ -0,0 +1,37 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const (
+	// SitemapNamespace is the namespace of the sitemap protocol.
+	SitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+	// SitemapFormat is the W3C Datetime format used in sitemaps.
+	SitemapFormat = time.RFC3339
+
+	// SitemapDocs sitemap protocol implemented.
+	SitemapDocs = "https://www.sitemaps.org/protocol.html"
+
+	// MaxUrls is the maximum number of urls a single sitemap file can have,
+	// anything more should be split across files listed in a sitemap index.
+	MaxUrls = 50_000
+)
+
+// UrlSet Encapsulates the file and references the current protocol standard.
+//
+// The Sitemap must begin with an opening <urlset> tag and end with a
+// closing </urlset> tag, specify the namespace (protocol standard) within
+// the <urlset> tag, include a <url> entry for each URL, as a parent XML
+// tag, and include a <loc> child entry for each <url> parent tag.
+type UrlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+
+	// Must be "http://www.sitemaps.org/schemas/sitemap/0.9"
+	Xmlns string `xml:"xmlns,attr"`
+
+	// Parent tag for each URL entry. The remaining tags are children of this tag.
+	Urls []*Url `xml:"url"`
+}
@@ -0,0 +1,38 @@
+package sitemap
+
+// Url Parent tag for each URL entry. The remaining tags are children of this tag.
+type Url struct {
+	// URL of the page. This URL must begin with the protocol (such as http)
+	// and end with a trailing slash, if your web server requires it. This
+	// value must be less than 2,048 characters.
+	Loc string `xml:"loc"`
+
+	// The date of last modification of the page. This date should be in W3C
+	// Datetime format. This format allows you to omit the time portion, if
+	// desired, and use YYYY-MM-DD.
+	//
+	// Note that the date must be set to the date the linked page was last
+	// modified, not when the sitemap is generated.
+	LastMod string `xml:"lastmod,omitempty"`
+
+	// How frequently the page is likely to change. This value provides general
+	// information to search engines and may not correlate exactly to how often
+	// they crawl the page. Valid values are:
+	//
+	//  - always
+	//  - hourly
+	//  - daily
+	//  - weekly
+	//  - monthly
+	//  - yearly
+	//  - never
+	ChangeFreq string `xml:"changefreq,omitempty"`
+
+	// The priority of this URL relative to other URLs on your site. Valid values
+	// range from 0.0 to 1.0. This value does not affect how your pages are compared
+	// to pages on other sites—it only lets the search engines know which pages you
+	// deem most important for the crawlers.
+	//
+	// The default priority of a page is 0.5.
+	Priority string `xml:"priority,omitempty"`
+}
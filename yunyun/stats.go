@@ -0,0 +1,24 @@
+package yunyun
+
+import "time"
+
+// PageStats are the statistics of the page's contents.
+type PageStats struct {
+	// Words is the number of words, where every CJK character counts as one.
+	Words int
+	// ReadingTime is the estimated time it takes to read the page.
+	ReadingTime time.Duration
+	// CodeBlocks is the number of source code blocks.
+	CodeBlocks int
+	// Images is the number of images, including the ones in galleries.
+	Images int
+}
+
+// ReadingMinutes returns the reading time rounded up to whole minutes,
+// with at least a minute for any page that has something to read.
+func (s PageStats) ReadingMinutes() int {
+	if s.ReadingTime <= 0 {
+		return 0
+	}
+	return int((s.ReadingTime + time.Minute - 1) / time.Minute)
+}
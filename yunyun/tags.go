@@ -0,0 +1,55 @@
+package yunyun
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SiteTag is a tag with the number of pages it's used on.
+type SiteTag struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Name is the tag as written by the user first.
+	Name string
+	// Slug is the url-friendly version of the tag, tags are
+	// grouped together by their slugs.
+	Slug string
+	// Count is the number of listed pages with this tag.
+	Count int
+}
+
+// SplitTags splits the tags declared as `:go:web:`, `go, web`, or `go web`.
+func SplitTags(what string) []string {
+	return strings.FieldsFunc(what, func(r rune) bool {
+		return r == ':' || r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// TagSlug returns the url-friendly version of the tag, lowercased with
+// anything that isn't a letter or a digit replaced by dashes.
+func TagSlug(tag string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			slug.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && slug.Len() > 0 {
+			slug.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimRight(slug.String(), "-")
+}
+
+// HasTag returns true if the page has a tag with the same slug.
+func (p *SitePage) HasTag(slug string) bool {
+	for _, tag := range p.Tags {
+		if TagSlug(tag) == slug {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package yunyun
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestSplitTags tests the different ways of declaring tags
+func TestSplitTags(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{":go:web:", []string{"go", "web"}},
+		{"go, web", []string{"go", "web"}},
+		{"go web  rust", []string{"go", "web", "rust"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := SplitTags(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("SplitTags(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+// TestTagSlug tests that tags are turned into url-friendly slugs
+func TestTagSlug(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"go", "go"},
+		{"Go", "go"},
+		{"C++", "c"},
+		{" machine learning ", "machine-learning"},
+		{"web/dev--tools", "web-dev-tools"},
+		{"日本語", "日本語"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := TagSlug(tt.input); got != tt.expected {
+			t.Errorf("TagSlug(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package yunyun
+
+import "math"
+
+// TermWeight is the weight of a single term in the page's text.
+type TermWeight struct {
+	// To prevent unkeyed literars.
+	_ struct{}
+	// Term is the lowercased word.
+	Term string
+	// Weight is the importance of the term in the page.
+	Weight float64
+}
+
+// Terms are the weighted terms of a page, always sorted by the term,
+// so that two pages can be compared in one pass.
+type Terms []TermWeight
+
+// Dot returns the dot product of the two term vectors, which is the cosine
+// similarity if both are normalized. The sum always goes in the order of the
+// terms, so the result is exactly the same between builds.
+func (t Terms) Dot(other Terms) float64 {
+	sum := 0.0
+	for i, j := 0, 0; i < len(t) && j < len(other); {
+		switch {
+		case t[i].Term < other[j].Term:
+			i++
+		case t[i].Term > other[j].Term:
+			j++
+		default:
+			sum += t[i].Weight * other[j].Weight
+			i++
+			j++
+		}
+	}
+	return sum
+}
+
+// Normalize scales the weights, so that the vector has the length of one.
+func (t Terms) Normalize() {
+	norm := math.Sqrt(t.Dot(t))
+	if norm == 0 {
+		return
+	}
+	for i := range t {
+		t[i].Weight /= norm
+	}
+}